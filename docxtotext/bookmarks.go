@@ -0,0 +1,345 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package docxtotext
+
+import (
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/young2j/oxmltotext/opc"
+	"github.com/young2j/oxmltotext/types"
+
+	qxml "github.com/dgrr/quickxml"
+)
+
+// bookmarkState is the parsing state of a bookmark.
+type bookmarkState struct {
+	index int // index of the bookmark in the results
+	text  strings.Builder
+}
+
+// Bookmarks extracts the bookmarks(w:bookmarkStart and w:bookmarkEnd) of the document body,
+// with the text of the bookmarked ranges and the estimated pages where they start.
+//
+// Parameters:
+//   - None
+//
+// Returns:
+//   - []types.Bookmark: the bookmarks in document order.
+//   - error: an error if any.
+func (dp *DocxParser) Bookmarks() ([]types.Bookmark, error) {
+	if dp.documentFile == nil {
+		dp.logWarn(types.ErrNoDocument)
+		return nil, nil
+	}
+
+	rc, err := opc.OpenPart(dp.documentFile)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var (
+		bookmarks = make([]types.Bookmark, 0, 8)
+		open      = make(map[string]*bookmarkState)
+		texts     = new(strings.Builder)
+		paragraph = new(strings.Builder)
+		ps        = newPageState(texts, "")
+		w_t       = ""
+	)
+	r := qxml.NewReader(rc)
+
+	// writeText writes the text to the paragraph and the open bookmarks.
+	writeText := func(text string) {
+		paragraph.WriteString(text)
+		for _, bm := range open {
+			bm.text.WriteString(text)
+		}
+	}
+
+NEXT:
+	for r.Next() {
+		switch e := r.Element().(type) {
+		case *qxml.StartElement:
+			switch e.Name() {
+			case "w:pPr":
+				ps.paragraphProps(paragraph, parseParagraphProps(r, e))
+
+			case "w:bookmarkStart":
+				attrs := e.Attrs()
+				id, name := attrs.Get("w:id"), attrs.Get("w:name")
+				if id == nil || name == nil {
+					continue
+				}
+				bookmarks = append(bookmarks, types.Bookmark{
+					Name: html.UnescapeString(name.Value()),
+					Page: len(ps.starts),
+				})
+				open[id.Value()] = &bookmarkState{index: len(bookmarks) - 1}
+
+			case "w:bookmarkEnd":
+				id := e.Attrs().Get("w:id")
+				if id == nil {
+					continue
+				}
+				if bm, ok := open[id.Value()]; ok {
+					bookmarks[bm.index].Text = strings.TrimSpace(bm.text.String())
+					delete(open, id.Value())
+				}
+
+			case "w:t":
+				r.AssignNext(&w_t)
+				if !r.Next() {
+					break NEXT
+				}
+				writeText(html.UnescapeString(w_t))
+				w_t = ""
+
+			case "w:tab":
+				writeText("\t")
+
+			case "w:lastRenderedPageBreak":
+				ps.pageBreak(paragraph, false)
+
+			case "w:br":
+				if brType := e.Attrs().Get("w:type"); brType != nil && brType.Value() == "page" {
+					ps.pageBreak(paragraph, true)
+				}
+			}
+
+		case *qxml.EndElement:
+			if e.Name() == "w:p" {
+				writeText("\n")
+				texts.WriteString(paragraph.String())
+				paragraph.Reset()
+				ps.flush()
+			}
+		}
+	}
+
+	// the bookmarks without end contain the text to the end of document.
+	for _, bm := range open {
+		bookmarks[bm.index].Text = strings.TrimSpace(bm.text.String())
+	}
+
+	return bookmarks, nil
+}
+
+// refField is a field being parsed, the result of a cross-reference field is collected to format.
+type refField struct {
+	kind     string // the field type, REF or PAGEREF, empty for other fields
+	bookmark string // the referenced bookmark name
+	instr    strings.Builder
+	result   strings.Builder
+	inResult bool
+}
+
+// parseInstr parses the field instruction, a bookmark name without field type is an implicit REF field.
+func (f *refField) parseInstr(instr string, bookmarks func() map[string]types.Bookmark) {
+	fields := strings.Fields(instr)
+	if len(fields) == 0 {
+		return
+	}
+	switch kind := strings.ToUpper(fields[0]); kind {
+	case "REF", "PAGEREF":
+		if len(fields) > 1 {
+			f.kind, f.bookmark = kind, fields[1]
+		}
+	default:
+		if _, ok := bookmarks()[fields[0]]; ok {
+			f.kind, f.bookmark = "REF", fields[0]
+		}
+	}
+}
+
+// fieldState tracks the fields(w:fldSimple and w:fldChar) while iterating the document body,
+// and formats the cross-reference fields(REF and PAGEREF) by the cross-reference format.
+type fieldState struct {
+	dp        *DocxParser
+	format    string
+	bookmarks map[string]types.Bookmark
+	fields    []*refField
+}
+
+// newFieldState returns a new fieldState, or nil if the cross-reference format is not set.
+func (dp *DocxParser) newFieldState() *fieldState {
+	if dp.crossRefFormat == "" {
+		return nil
+	}
+
+	return &fieldState{dp: dp, format: dp.crossRefFormat}
+}
+
+// loadBookmarks parses the bookmarks once for resolving the cross-reference fields without result.
+func (fs *fieldState) loadBookmarks() map[string]types.Bookmark {
+	if fs.bookmarks == nil {
+		bookmarks, err := fs.dp.Bookmarks()
+		fs.dp.logWarn(err)
+		fs.bookmarks = make(map[string]types.Bookmark, len(bookmarks))
+		for _, bm := range bookmarks {
+			fs.bookmarks[bm.Name] = bm
+		}
+	}
+
+	return fs.bookmarks
+}
+
+// start tracks the start element of fields.
+//
+// Parameters:
+//   - r: the qxml.Reader of the part.
+//   - e: the start element that has been read.
+//   - w: the writer of current paragraph or table row.
+//
+// Returns:
+//   - bool: true if the element is consumed.
+func (fs *fieldState) start(r *qxml.Reader, e *qxml.StartElement, w *strings.Builder) bool {
+	if fs == nil {
+		return false
+	}
+
+	switch e.Name() {
+	case "w:fldSimple":
+		f := &refField{inResult: true}
+		if instr := e.Attrs().Get("w:instr"); instr != nil {
+			f.parseInstr(html.UnescapeString(instr.Value()), fs.loadBookmarks)
+		}
+		fs.fields = append(fs.fields, f)
+		if e.HasEnd() {
+			fs.end(w)
+		}
+		return true
+
+	case "w:fldChar":
+		fldCharType := e.Attrs().Get("w:fldCharType")
+		if fldCharType == nil || len(fs.fields) == 0 && fldCharType.Value() != "begin" {
+			return false
+		}
+		switch fldCharType.Value() {
+		case "begin":
+			fs.fields = append(fs.fields, new(refField))
+		case "separate":
+			f := fs.fields[len(fs.fields)-1]
+			f.parseInstr(f.instr.String(), fs.loadBookmarks)
+			f.inResult = true
+		case "end":
+			f := fs.fields[len(fs.fields)-1]
+			if !f.inResult {
+				f.parseInstr(f.instr.String(), fs.loadBookmarks)
+			}
+			fs.end(w)
+		}
+		return true
+
+	case "w:instrText":
+		var text string
+		r.AssignNext(&text)
+		if r.Next() && len(fs.fields) > 0 {
+			fs.fields[len(fs.fields)-1].instr.WriteString(html.UnescapeString(text))
+		}
+		return true
+	}
+
+	return false
+}
+
+// stop tracks the end element of fields.
+func (fs *fieldState) stop(e *qxml.EndElement, w *strings.Builder) {
+	if fs != nil && e.Name() == "w:fldSimple" && len(fs.fields) > 0 {
+		fs.end(w)
+	}
+}
+
+// writer returns the result of the innermost cross-reference field being parsed, or w if none.
+func (fs *fieldState) writer(w *strings.Builder) *strings.Builder {
+	if fs == nil {
+		return w
+	}
+	for i := len(fs.fields) - 1; i >= 0; i-- {
+		if f := fs.fields[i]; f.kind != "" && f.inResult {
+			return &f.result
+		}
+	}
+
+	return w
+}
+
+// end ends the innermost field, and writes the formatted cross-reference.
+// The cached field result is preferred, the text or the estimated page of the bookmark is used if the result is empty.
+func (fs *fieldState) end(w *strings.Builder) {
+	f := fs.fields[len(fs.fields)-1]
+	fs.fields = fs.fields[:len(fs.fields)-1]
+	if f.kind == "" {
+		return
+	}
+
+	text := strings.TrimSpace(f.result.String())
+	if text == "" {
+		bm, ok := fs.loadBookmarks()[f.bookmark]
+		if !ok {
+			return
+		}
+		text = bm.Text
+		if f.kind == "PAGEREF" {
+			text = strconv.Itoa(bm.Page)
+		}
+	}
+	formatVerbs(fs.writer(w), fs.format, text, f.bookmark)
+}
+
+// writeBookmark writes the anchor of the bookmark by the bookmark format.
+func (dp *DocxParser) writeBookmark(e *qxml.StartElement, w *strings.Builder) {
+	if dp.bookmarkFormat == "" {
+		return
+	}
+	name := e.Attrs().Get("w:name")
+	if name == nil || name.Value() == "_GoBack" {
+		return
+	}
+	formatVerbs(w, dp.bookmarkFormat, html.UnescapeString(name.Value()))
+}
+
+// formatVerbs writes the format with its "%s" verbs replaced by the args in order, the explicit argument
+// indexes like "%[2]s" and the escaped "%%" are supported. Other text, including other verbs, is written as is,
+// and the verbs without args are dropped.
+func formatVerbs(w *strings.Builder, format string, args ...string) {
+	next := 0 // the index of the arg of the next "%s"
+	for {
+		i := strings.IndexByte(format, '%')
+		if i < 0 {
+			w.WriteString(format)
+			return
+		}
+		w.WriteString(format[:i])
+		format = format[i:]
+
+		switch {
+		case strings.HasPrefix(format, "%%"):
+			w.WriteByte('%')
+			format = format[2:]
+			continue
+		case strings.HasPrefix(format, "%s"):
+			if next < len(args) {
+				w.WriteString(args[next])
+			}
+			next++
+			format = format[2:]
+			continue
+		case strings.HasPrefix(format, "%["):
+			if j := strings.Index(format, "]s"); j > 2 {
+				if n, err := strconv.Atoi(format[2:j]); err == nil && n > 0 && n <= len(args) {
+					w.WriteString(args[n-1])
+					next = n
+					format = format[j+2:]
+					continue
+				}
+			}
+		}
+		w.WriteByte('%')
+		format = format[1:]
+	}
+}
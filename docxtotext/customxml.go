@@ -0,0 +1,114 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package docxtotext
+
+import (
+	"archive/zip"
+	"strconv"
+	"strings"
+
+	"github.com/young2j/oxmltotext/opc"
+	"github.com/young2j/oxmltotext/utils"
+
+	qxml "github.com/dgrr/quickxml"
+)
+
+// parseXMLTree parses the xml part into an element tree.
+//
+// Parameters:
+//   - f: the xml part file.
+//
+// Returns:
+//   - *utils.XMLNode: the document node whose child is the root element.
+//   - error: an error if any.
+func parseXMLTree(f *zip.File) (*utils.XMLNode, error) {
+	rc, err := opc.OpenPart(f)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return utils.ParseXMLTree(qxml.NewReader(rc)), nil
+}
+
+// evalXPath evaluates the absolute XPath used by data binding,
+// like /ns0:root[1]/ns0:item[2] or /root/item/@id. Only element steps
+// with an optional position predicate and a final attribute step are supported.
+//
+// Parameters:
+//   - n: the document node of custom XML data.
+//   - xpath: the XPath to evaluate.
+//
+// Returns:
+//   - string: the text content of the selected element or the value of the selected attribute.
+//   - bool: true if the XPath selects a node.
+func evalXPath(n *utils.XMLNode, xpath string) (string, bool) {
+	node := n
+	steps := strings.Split(strings.TrimPrefix(strings.TrimSpace(xpath), "/"), "/")
+	for i, step := range steps {
+		if strings.HasPrefix(step, "@") {
+			if i != len(steps)-1 {
+				return "", false
+			}
+			return node.Attr(utils.LocalName(step[1:]))
+		}
+
+		pos := 1
+		if j := strings.IndexByte(step, '['); j >= 0 {
+			p, err := strconv.Atoi(strings.TrimSuffix(step[j+1:], "]"))
+			if err != nil {
+				return "", false
+			}
+			step, pos = step[:j], p
+		}
+		name := utils.LocalName(step)
+
+		var next *utils.XMLNode
+		for _, child := range node.Children {
+			if name == "*" || child.Name == name {
+				pos--
+				if pos == 0 {
+					next = child
+					break
+				}
+			}
+		}
+		if next == nil {
+			return "", false
+		}
+		node = next
+	}
+
+	return node.TextContent(), true
+}
+
+// loadCustomXml parses the custom XML data parts and maps them by the store item id(ds:itemID).
+func (dp *DocxParser) loadCustomXml() map[string]*utils.XMLNode {
+	stores := make(map[string]*utils.XMLNode, len(dp.customXmlFiles))
+	for name, f := range dp.customXmlFiles {
+		if !strings.Contains(name, "itemProps") {
+			continue
+		}
+		props, err := parseXMLTree(f)
+		if err != nil {
+			dp.logWarn(err)
+			continue
+		}
+		item, ok := dp.customXmlFiles[strings.Replace(name, "itemProps", "item", 1)]
+		if !ok || len(props.Children) == 0 {
+			continue
+		}
+		itemID, _ := props.Children[0].Attr("itemID")
+		data, err := parseXMLTree(item)
+		if err != nil {
+			dp.logWarn(err)
+			continue
+		}
+		stores[strings.ToUpper(itemID)] = data
+	}
+
+	return stores
+}
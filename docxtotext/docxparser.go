@@ -16,18 +16,25 @@ import (
 
 // DocxParser represents the XML file structure and settings for parsing a docx file.
 type DocxParser struct {
-	zipReadCloser *zip.ReadCloser
-	documentFile  *zip.File
-	commentsFile  *zip.File
-	headerFiles   []*zip.File
-	footerFiles   []*zip.File
-	footnotesFile *zip.File
-	endnotesFile  *zip.File
-	chartsFiles   map[string]*zip.File
-	imagesFiles   map[string]*zip.File
-	diagramsFiles map[string]*zip.File
-	docRelsMap    map[string]string
-	ocr           types.OCR
+	zipReadCloser  *zip.ReadCloser
+	documentFile   *zip.File
+	commentsFile   *zip.File
+	headerFiles    []*zip.File
+	footerFiles    []*zip.File
+	footnotesFile  *zip.File
+	endnotesFile   *zip.File
+	stylesFile     *zip.File
+	chartsFiles    map[string]*zip.File
+	imagesFiles    map[string]*zip.File
+	diagramsFiles  map[string]*zip.File
+	embedsFiles    map[string]*zip.File
+	chunksFiles    map[string]*zip.File
+	customXmlFiles map[string]*zip.File
+	docRelsMap     map[string]string
+	styles         *docxStyles
+	pages          []string // the cached texts of the estimated pages
+	ocr            types.OCR
+	embed          types.Embed
 
 	parseComments  bool
 	parseHeaders   bool
@@ -36,11 +43,23 @@ type DocxParser struct {
 	parseEndnotes  bool
 	parseCharts    bool
 	parseImages    bool
+	parseAltText   bool
+	preferAltText  bool
 	parseDiagrams  bool
+	parseEmbeds    bool
+	parseChunks    bool
 	drawingsNoFmt  bool
+	embedsMaxDepth int
+	hiddenMode     types.HiddenMode
+	mathMode       types.MathMode
+	crossRefFormat string
+	bookmarkFormat string
+	bidiMarks      bool
 
 	paragraphSep string
 	partSep      string
+	pageSep      string
+	pageMarker   string
 	tableRowSep  string
 	tableColSep  string
 
@@ -57,8 +76,11 @@ func newDocxParser() *DocxParser {
 		parseFootnotes: true,
 		parseFooters:   true,
 		parseHeaders:   true,
+		parseChunks:    true,
+		embedsMaxDepth: 1,
 		paragraphSep:   "\n",
 		partSep:        strings.Repeat("-", 100) + "\n",
+		pageSep:        strings.Repeat("-", 100) + "\n",
 		tableRowSep:    "\n",
 		tableColSep:    "\t",
 		logger:         logger,
@@ -10,7 +10,9 @@ import (
 	"strings"
 
 	"github.com/young2j/oxmltotext/ocr"
+	"github.com/young2j/oxmltotext/opc"
 	"github.com/young2j/oxmltotext/types"
+	"github.com/young2j/oxmltotext/utils"
 
 	qxml "github.com/dgrr/quickxml"
 )
@@ -18,6 +20,7 @@ import (
 // SetParagraphSep sets paragraph separator. Default is "\n".
 func (dp *DocxParser) SetParagraphSep(sep string) {
 	dp.paragraphSep = sep
+	dp.pages = nil
 }
 
 // SetPartSep sets document part(every XML file like header, footer, etc.) separator. Default is "-"x100.
@@ -28,11 +31,13 @@ func (dp *DocxParser) SetPartSep(sep string) {
 // SetTableRowSep sets table row separator. Default is "\n".
 func (dp *DocxParser) SetTableRowSep(sep string) {
 	dp.tableRowSep = sep
+	dp.pages = nil
 }
 
 // SetTableColSep sets table column separator. Default is "\t".
 func (dp *DocxParser) SetTableColSep(sep string) {
 	dp.tableColSep = sep
+	dp.pages = nil
 }
 
 // SetParseComments parses comments or not. Default is true.
@@ -60,34 +65,132 @@ func (dp *DocxParser) SetParseHeaders(v bool) {
 	dp.parseHeaders = v
 }
 
+// SetParseAltChunks parses imported alternative format content(w:altChunk) or not. Default is true.
+// HTML, MHT, RTF, plain text and docx content are supported.
+func (dp *DocxParser) SetParseAltChunks(v bool) {
+	dp.parseChunks = v
+	dp.pages = nil
+}
+
 // SetParseCharts parses charts or not. Default is false.
 func (dp *DocxParser) SetParseCharts(v bool) {
 	dp.parseCharts = v
+	dp.pages = nil
 }
 
 // SetParseDiagrams parses diagrams or not. Default is false.
 func (dp *DocxParser) SetParseDiagrams(v bool) {
 	dp.parseDiagrams = v
+	dp.pages = nil
 }
 
 // SetParseImages parses images or not. Default is false.
 // When ocr interface is not set, default tesseract-ocr will be used.
 func (dp *DocxParser) SetParseImages(v bool) {
 	dp.parseImages = v
+	dp.pages = nil
 
 	if v && dp.ocr == nil {
 		dp.ocr = ocr.NewDefaultOcr()
 	}
 }
 
+// SetParseAltText writes the alt text(title and description) of images alongside them or not. Default is false.
+func (dp *DocxParser) SetParseAltText(v bool) {
+	dp.parseAltText = v
+	dp.pages = nil
+}
+
+// SetPreferAltText uses the alt text of images instead of ocr when present or not. Default is false.
+// It takes effect only when images are parsed, see SetParseImages.
+func (dp *DocxParser) SetPreferAltText(v bool) {
+	dp.preferAltText = v
+	dp.pages = nil
+}
+
+// SetParseEmbeddings parses embedded objects(OLE objects and packages) or not. Default is false.
+// The embed interface must be set by SetEmbedInterface, see package embedtotext.
+func (dp *DocxParser) SetParseEmbeddings(v bool) {
+	dp.parseEmbeds = v
+	dp.pages = nil
+}
+
+// SetEmbeddingsMaxDepth sets the max depth of recursively parsing embedded objects. Default is 1.
+// The depth of 0 or less does not parse the embedded objects.
+func (dp *DocxParser) SetEmbeddingsMaxDepth(depth int) {
+	dp.embedsMaxDepth = depth
+	dp.pages = nil
+}
+
+// SetEmbedInterface sets the embed interface used to extract text from embedded objects.
+func (dp *DocxParser) SetEmbedInterface(embed types.Embed) {
+	dp.embed = embed
+	dp.pages = nil
+}
+
+// SetHiddenMode sets the mode of handling hidden runs(w:vanish and w:specVanish). Default is types.HiddenInclude.
+// The run properties are resolved through the styles part, so the inherited hidden formatting is respected.
+func (dp *DocxParser) SetHiddenMode(mode types.HiddenMode) {
+	dp.hiddenMode = mode
+	dp.pages = nil
+}
+
+// SetMathMode sets the mode of converting Office Math(OMML) equations to text. Default is types.MathLaTeX.
+func (dp *DocxParser) SetMathMode(mode types.MathMode) {
+	dp.mathMode = mode
+	dp.pages = nil
+}
+
+// SetBidiMarks sets whether to write unicode bidi marks for right-to-left text. Default is false.
+// A right-to-left mark(U+200F) is written at the start of right-to-left paragraphs(w:bidi), and the runs
+// whose direction(w:rtl) differs from the paragraph are surrounded by directional isolates(U+2066/U+2067 and U+2069),
+// so that the logically ordered text is displayed correctly by bidi-aware renderers.
+func (dp *DocxParser) SetBidiMarks(v bool) {
+	dp.bidiMarks = v
+	dp.pages = nil
+}
+
+// SetPageSep sets the page separator of ExtractPageTexts. Default is "-"x100.
+func (dp *DocxParser) SetPageSep(sep string) {
+	dp.pageSep = sep
+}
+
+// SetPageMarker sets the format of the marker written at the start of each estimated page of document body,
+// the placeholder "%d" is replaced by the page number(start 1), like "[page %d]\n". Other text, including
+// a literal "%", is written as is. Default is "", no marker is written.
+func (dp *DocxParser) SetPageMarker(format string) {
+	dp.pageMarker = format
+	dp.pages = nil
+}
+
+// SetCrossRefFormat sets the format of cross-reference fields(REF and PAGEREF) of document body,
+// the "%s" placeholders are replaced by the reference text and the bookmark name in order, like "[%s](#%s)"
+// for Markdown links, or by the explicit indexes, like `<a href="#%[2]s">%[1]s</a>` for HTML links.
+// "%%" is written as "%", other text is written as is. Default is "", the field results are written as is.
+// The field result is preferred, the text or the estimated page of the bookmark is used if the result is empty.
+func (dp *DocxParser) SetCrossRefFormat(format string) {
+	dp.crossRefFormat = format
+	dp.pages = nil
+}
+
+// SetBookmarkFormat sets the format of the anchor written at the start of bookmarks of document body,
+// the placeholder "%s" is replaced by the bookmark name, like "{#%s}" or `<a id="%s"></a>`, "%%" is written as "%".
+// Default is "", no anchor is written.
+func (dp *DocxParser) SetBookmarkFormat(format string) {
+	dp.bookmarkFormat = format
+	dp.pages = nil
+}
+
 // SetDrawingsNoFmt sets drawings text no outline format.
 func (dp *DocxParser) SetDrawingsNoFmt(v bool) {
 	dp.drawingsNoFmt = v
+	dp.pages = nil
 }
 
 // SetOcrInterface overrides default ocr interface.
 func (dp *DocxParser) SetOcrInterface(ocr types.OCR) {
 	dp.ocr = ocr
+	dp.pages = nil
 }
 
 // DisableLogging disables logging.
@@ -114,7 +217,7 @@ func (dp *DocxParser) Close() (err error) {
 	return nil
 }
 
-// ExtractImages extracts images from the docx file.
+// ExtractImages extracts images from the docx file, along with the alt text and caption of the drawings referencing them.
 //
 // Parameters:
 //   - None
@@ -123,6 +226,9 @@ func (dp *DocxParser) Close() (err error) {
 //   - []types.Image: a slice of images extracted from the docx file.
 //   - error: an error if any occurred during the extraction process.
 func (dp *DocxParser) ExtractImages() ([]types.Image, error) {
+	infos, err := dp.parseImageInfos()
+	dp.logWarn(err)
+
 	images := make([]types.Image, 0, len(dp.imagesFiles))
 	for name, f := range dp.imagesFiles {
 		r, err := f.Open()
@@ -136,11 +242,15 @@ func (dp *DocxParser) ExtractImages() ([]types.Image, error) {
 		}
 		r.Close()
 
-		images = append(images, types.Image{
+		info := types.Image{
 			Raw:    img,
 			Name:   name,
 			Format: format,
-		})
+		}
+		if alt, ok := infos[name]; ok {
+			info.AltText, info.Title, info.Caption = alt.AltText, alt.Title, alt.Caption
+		}
+		images = append(images, info)
 	}
 
 	return images, nil
@@ -155,7 +265,7 @@ func (dp *DocxParser) ExtractImages() ([]types.Image, error) {
 //   - string: The extracted texts.
 //   - error: An error if any.
 func (dp *DocxParser) ExtractTexts() (string, error) {
-	texts, err := dp.extractDocument()
+	texts, _, err := dp.extractDocument()
 	if err != nil {
 		return "", err
 	}
@@ -225,16 +335,18 @@ func (dp *DocxParser) ExtractTexts() (string, error) {
 //
 // Returns:
 //   - *strings.Builder: a strings.Builder containing the extracted document text.
+//   - *pageState: the estimated pages of the document text.
 //   - error: an error if any.
-func (dp *DocxParser) extractDocument() (*strings.Builder, error) {
+func (dp *DocxParser) extractDocument() (*strings.Builder, *pageState, error) {
 	if dp.documentFile == nil {
 		dp.logWarn(types.ErrNoDocument)
-		return new(strings.Builder), nil
+		texts := new(strings.Builder)
+		return texts, newPageState(texts, dp.pageMarker), nil
 	}
 
-	rc, err := dp.documentFile.Open()
+	rc, err := opc.OpenPart(dp.documentFile)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer rc.Close()
 
@@ -242,6 +354,10 @@ func (dp *DocxParser) extractDocument() (*strings.Builder, error) {
 		texts     = new(strings.Builder)
 		paragraph = new(strings.Builder)
 		w_t       = ""
+		embedded  = make(map[string]bool)
+		rs        = dp.newRunState()
+		ps        = newPageState(texts, dp.pageMarker)
+		fs        = dp.newFieldState()
 	)
 	r := qxml.NewReader(rc)
 
@@ -249,6 +365,15 @@ NEXT:
 	for r.Next() {
 		switch e := r.Element().(type) {
 		case *qxml.StartElement:
+			if e.Name() == "w:pPr" {
+				props := parseParagraphProps(r, e)
+				rs.setParagraphProps(props)
+				ps.paragraphProps(paragraph, props)
+				continue
+			}
+			if rs.start(r, e) || fs.start(r, e, paragraph) {
+				continue
+			}
 			switch e.Name() {
 			case "w:t":
 				r.AssignNext(&w_t)
@@ -256,67 +381,182 @@ NEXT:
 					break NEXT
 				}
 				if len(w_t) > 0 {
-					paragraph.WriteString(w_t)
+					rs.writeText(fs.writer(paragraph), w_t)
 					w_t = ""
 				}
 
+			case "w:bookmarkStart":
+				dp.writeBookmark(e, fs.writer(paragraph))
+
+			case "m:oMathPara", "m:oMath":
+				math := utils.ReadXMLElement(r, e)
+				if !rs.excluded() {
+					paragraph.WriteString(utils.OMMLToText(math, dp.mathMode))
+				}
+
+			case "w:lastRenderedPageBreak":
+				ps.pageBreak(paragraph, false)
+
+			case "w:br":
+				if brType := e.Attrs().Get("w:type"); brType != nil && brType.Value() == "page" {
+					ps.pageBreak(paragraph, true)
+				}
+
 			case "w:tbl":
-				table := dp.extractTable(r)
+				table := dp.extractTable(r, rs, ps, fs)
 				if table != nil {
 					texts.WriteString(table.String())
 				}
+				ps.flush()
 
 			case "w:drawing":
+				if rs.excluded() {
+					utils.SkipElement(r, e)
+					continue
+				}
 				drawings := dp.extractDrawings(r)
 				if drawings != nil {
 					texts.WriteString(drawings.String())
 				}
+
+			case "w:altChunk":
+				if !dp.parseChunks {
+					continue
+				}
+				attrs := e.Attrs()
+				if attrs.Len() > 0 {
+					rIdKV := attrs.Get("r:id")
+					if rIdKV == nil {
+						continue
+					}
+					chunk, err := dp.extractAltChunk(rIdKV.Value())
+					dp.logWarn(err)
+					if chunk != nil {
+						texts.WriteString(chunk.String())
+					}
+				}
+
+			case "o:OLEObject", "w:objectEmbed":
+				if !dp.parseEmbeds || rs.excluded() {
+					continue
+				}
+				attrs := e.Attrs()
+				if attrs.Len() > 0 {
+					rIdKV := attrs.Get("r:id")
+					if rIdKV == nil || embedded[rIdKV.Value()] {
+						continue
+					}
+					embedded[rIdKV.Value()] = true
+					embedding, err := dp.extractEmbedding(rIdKV.Value())
+					dp.logWarn(err)
+					if embedding != nil {
+						texts.WriteString(embedding.String())
+					}
+				}
 			}
 
 		case *qxml.EndElement:
+			rs.end(e)
+			fs.stop(e, paragraph)
 			if e.Name() == "w:p" {
+				rs.closeMark(paragraph)
 				if paragraph.Len() > 0 {
 					texts.WriteString(paragraph.String())
 					texts.WriteString(dp.paragraphSep)
 					paragraph.Reset()
 					w_t = ""
 				}
+				ps.flush()
 			}
 		}
 	}
 
-	return texts, nil
+	return texts, ps, nil
 }
 
 // extractTable extracts the table from the given qxml.Reader and returns a strings.Builder with the extracted table contents.
 //
 // Parameters:
 //   - r: a qxml.Reader instance from which the table is extracted.
+//   - rs: the runState of the part containing the table.
+//   - ps: the pageState of the document body.
+//   - fs: the fieldState of the document body.
 //
 // Return:
 //   - texts: a strings.Builder instance containing the extracted table contents.
-func (dp *DocxParser) extractTable(r *qxml.Reader) *strings.Builder {
+func (dp *DocxParser) extractTable(r *qxml.Reader, rs *runState, ps *pageState, fs *fieldState) *strings.Builder {
 	var (
 		texts = new(strings.Builder)
 		row   = new(strings.Builder)
+		ref   = new(strings.Builder) // the formatted cross-reference, written as the cell text
 		w_t   = ""
 	)
 
+	// writeCell writes the text of the cell followed by the column separator.
+	writeCell := func(text string) {
+		if !rs.excluded() {
+			rs.writeText(row, text)
+			rs.closeMark(row)
+			row.WriteString(dp.tableColSep)
+		}
+	}
+
 NEXT:
 	for r.Next() {
 		switch e := r.Element().(type) {
 		case *qxml.StartElement:
-			if e.Name() == "w:t" {
+			if e.Name() == "w:pPr" {
+				props := parseParagraphProps(r, e)
+				rs.setParagraphProps(props)
+				ps.paragraphProps(nil, props)
+				continue
+			}
+			if rs.start(r, e) || fs.start(r, e, ref) {
+				if ref.Len() > 0 {
+					writeCell(ref.String())
+					ref.Reset()
+				}
+				continue
+			}
+			switch e.Name() {
+			case "w:t":
 				r.AssignNext(&w_t)
 				if !r.Next() {
 					break NEXT
 				}
-				row.WriteString(w_t)
-				row.WriteString(dp.tableColSep)
+				if w := fs.writer(row); w != row {
+					rs.writeText(w, w_t)
+				} else {
+					writeCell(w_t)
+				}
 				w_t = ""
+
+			case "w:bookmarkStart":
+				dp.writeBookmark(e, fs.writer(row))
+
+			case "w:lastRenderedPageBreak":
+				ps.pageBreak(nil, false)
+
+			case "w:br":
+				if brType := e.Attrs().Get("w:type"); brType != nil && brType.Value() == "page" {
+					ps.pageBreak(nil, true)
+				}
+
+			case "m:oMathPara", "m:oMath":
+				math := utils.ReadXMLElement(r, e)
+				if !rs.excluded() {
+					row.WriteString(utils.OMMLToText(math, dp.mathMode))
+					row.WriteString(dp.tableColSep)
+				}
 			}
 
 		case *qxml.EndElement:
+			rs.end(e)
+			fs.stop(e, ref)
+			if ref.Len() > 0 {
+				writeCell(ref.String())
+				ref.Reset()
+			}
 			switch e.Name() {
 			case "w:tr":
 				if row.Len() > 0 {
@@ -348,7 +588,7 @@ func (dp *DocxParser) extractComments() (*strings.Builder, error) {
 		return new(strings.Builder), nil
 	}
 
-	rc, err := dp.commentsFile.Open()
+	rc, err := opc.OpenPart(dp.commentsFile)
 	if err != nil {
 		return nil, err
 	}
@@ -357,6 +597,7 @@ func (dp *DocxParser) extractComments() (*strings.Builder, error) {
 	var (
 		texts = new(strings.Builder)
 		w_t   = ""
+		rs    = dp.newRunState()
 	)
 	r := qxml.NewReader(rc)
 
@@ -364,19 +605,24 @@ NEXT:
 	for r.Next() {
 		switch e := r.Element().(type) {
 		case *qxml.StartElement:
+			if rs.start(r, e) {
+				continue
+			}
 			if e.Name() == "w:t" {
 				r.AssignNext(&w_t)
 				if !r.Next() {
 					break NEXT
 				}
 				if len(w_t) > 0 {
-					texts.WriteString(w_t)
+					rs.writeText(texts, w_t)
 					w_t = ""
 				}
 			}
 
 		case *qxml.EndElement:
+			rs.end(e)
 			if e.Name() == "w:comment" {
+				rs.closeMark(texts)
 				texts.WriteString(dp.paragraphSep)
 			}
 		}
@@ -399,7 +645,7 @@ func (dp *DocxParser) extractEndnotes() (*strings.Builder, error) {
 		return new(strings.Builder), nil
 	}
 
-	rc, err := dp.endnotesFile.Open()
+	rc, err := opc.OpenPart(dp.endnotesFile)
 	if err != nil {
 		return nil, err
 	}
@@ -409,6 +655,7 @@ func (dp *DocxParser) extractEndnotes() (*strings.Builder, error) {
 		texts   = new(strings.Builder)
 		endnote = new(strings.Builder)
 		w_t     = ""
+		rs      = dp.newRunState()
 	)
 	r := qxml.NewReader(rc)
 
@@ -416,19 +663,24 @@ NEXT:
 	for r.Next() {
 		switch e := r.Element().(type) {
 		case *qxml.StartElement:
+			if rs.start(r, e) {
+				continue
+			}
 			if e.Name() == "w:t" {
 				r.AssignNext(&w_t)
 				if !r.Next() {
 					break NEXT
 				}
 				if len(w_t) > 0 {
-					endnote.WriteString(w_t)
+					rs.writeText(endnote, w_t)
 					w_t = ""
 				}
 			}
 
 		case *qxml.EndElement:
+			rs.end(e)
 			if e.Name() == "w:endnote" {
+				rs.closeMark(endnote)
 				if endnote.Len() > 0 {
 					texts.WriteString(endnote.String())
 					texts.WriteString(dp.paragraphSep)
@@ -457,7 +709,7 @@ func (dp *DocxParser) extractFootnotes() (*strings.Builder, error) {
 		return new(strings.Builder), nil
 	}
 
-	rc, err := dp.footnotesFile.Open()
+	rc, err := opc.OpenPart(dp.footnotesFile)
 	if err != nil {
 		return nil, err
 	}
@@ -467,6 +719,7 @@ func (dp *DocxParser) extractFootnotes() (*strings.Builder, error) {
 		texts    = new(strings.Builder)
 		footnote = new(strings.Builder)
 		w_t      = ""
+		rs       = dp.newRunState()
 	)
 	r := qxml.NewReader(rc)
 
@@ -474,19 +727,24 @@ NEXT:
 	for r.Next() {
 		switch e := r.Element().(type) {
 		case *qxml.StartElement:
+			if rs.start(r, e) {
+				continue
+			}
 			if e.Name() == "w:t" {
 				r.AssignNext(&w_t)
 				if !r.Next() {
 					break NEXT
 				}
 				if len(w_t) > 0 {
-					footnote.WriteString(w_t)
+					rs.writeText(footnote, w_t)
 					w_t = ""
 				}
 			}
 
 		case *qxml.EndElement:
+			rs.end(e)
 			if e.Name() == "w:footnote" {
+				rs.closeMark(footnote)
 				if footnote.Len() > 0 {
 					texts.WriteString(footnote.String())
 					texts.WriteString(dp.paragraphSep)
@@ -529,7 +787,7 @@ func (dp *DocxParser) extractFooters() (*strings.Builder, error) {
 //   - *strings.Builder: the extracted footer
 //   - error: any error that occurred during the extraction process
 func (dp *DocxParser) extractFooter(i int) (*strings.Builder, error) {
-	rc, err := dp.footerFiles[i].Open()
+	rc, err := opc.OpenPart(dp.footerFiles[i])
 	if err != nil {
 		return nil, err
 	}
@@ -539,6 +797,7 @@ func (dp *DocxParser) extractFooter(i int) (*strings.Builder, error) {
 		texts = new(strings.Builder)
 		ftr   = new(strings.Builder)
 		w_t   = ""
+		rs    = dp.newRunState()
 	)
 	r := qxml.NewReader(rc)
 
@@ -546,19 +805,24 @@ NEXT:
 	for r.Next() {
 		switch e := r.Element().(type) {
 		case *qxml.StartElement:
+			if rs.start(r, e) {
+				continue
+			}
 			if e.Name() == "w:t" {
 				r.AssignNext(&w_t)
 				if !r.Next() {
 					break NEXT
 				}
 				if len(w_t) > 0 {
-					ftr.WriteString(w_t)
+					rs.writeText(ftr, w_t)
 					w_t = ""
 				}
 			}
 
 		case *qxml.EndElement:
+			rs.end(e)
 			if e.Name() == "w:p" {
+				rs.closeMark(ftr)
 				if ftr.Len() > 0 {
 					texts.WriteString(ftr.String())
 					texts.WriteString(dp.paragraphSep)
@@ -601,7 +865,7 @@ func (dp *DocxParser) extractHeaders() (*strings.Builder, error) {
 //   - *strings.Builder: The extracted header text.
 //   - error: An error if there was a problem opening or processing the header file.
 func (dp *DocxParser) extractHeader(i int) (*strings.Builder, error) {
-	rc, err := dp.headerFiles[i].Open()
+	rc, err := opc.OpenPart(dp.headerFiles[i])
 	if err != nil {
 		return nil, err
 	}
@@ -611,6 +875,7 @@ func (dp *DocxParser) extractHeader(i int) (*strings.Builder, error) {
 		texts = new(strings.Builder)
 		hdr   = new(strings.Builder)
 		w_t   = ""
+		rs    = dp.newRunState()
 	)
 	r := qxml.NewReader(rc)
 
@@ -618,19 +883,24 @@ NEXT:
 	for r.Next() {
 		switch e := r.Element().(type) {
 		case *qxml.StartElement:
+			if rs.start(r, e) {
+				continue
+			}
 			if e.Name() == "w:t" {
 				r.AssignNext(&w_t)
 				if !r.Next() {
 					break NEXT
 				}
 				if len(w_t) > 0 {
-					hdr.WriteString(w_t)
+					rs.writeText(hdr, w_t)
 					w_t = ""
 				}
 			}
 
 		case *qxml.EndElement:
+			rs.end(e)
 			if e.Name() == "w:p" {
+				rs.closeMark(hdr)
 				if hdr.Len() > 0 {
 					texts.WriteString(hdr.String())
 					texts.WriteString(dp.paragraphSep)
@@ -8,13 +8,28 @@ package docxtotext
 import (
 	"bytes"
 	"image/jpeg"
+	"io"
 	"os"
+	"strings"
 	"testing"
+
+	"github.com/young2j/oxmltotext/types"
 )
 
 var (
-	docxPath = "../filesamples/file-sample_100kb.docx"
-	docxURL  = "http://www.hbdxzj.org.cn/Uploads/detail/file/20230119/63c891e9e10c8.docx"
+	docxPath          = "../filesamples/file-sample_100kb.docx"
+	docxAltChunksPath = "../filesamples/file-sample_altchunks.docx"
+	docxHiddenPath    = "../filesamples/file-sample_hidden.docx"
+	docxFormsPath     = "../filesamples/file-sample_forms.docx"
+	docxMathPath      = "../filesamples/file-sample_math.docx"
+	docxAltTextPath   = "../filesamples/file-sample_alttext.docx"
+	docxPagesPath     = "../filesamples/file-sample_pages.docx"
+	docxBookmarksPath = "../filesamples/file-sample_bookmarks.docx"
+	docxLangPath      = "../filesamples/file-sample_lang.docx"
+	docxFormatPath    = "../filesamples/file-sample_format.docx"
+	docxRenamedPath   = "../filesamples/file-sample_renamed.docm"
+	docxStrictPath    = "../filesamples/file-sample_strict.docx"
+	docxURL           = "http://www.hbdxzj.org.cn/Uploads/detail/file/20230119/63c891e9e10c8.docx"
 )
 
 func TestOpen(t *testing.T) {
@@ -172,3 +187,485 @@ func TestParseImages(t *testing.T) {
 
 	t.Log(texts)
 }
+
+func TestParseAltChunks(t *testing.T) {
+	dp, err := Open(docxAltChunksPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dp.Close()
+
+	texts, err := dp.ExtractTexts()
+	if err != nil {
+		t.Error(err)
+	}
+
+	for _, want := range []string{
+		"Revenue grew by 12% & costs fell.",
+		"MHT paragraph two with a soft line break.",
+		"RTF chunk with café and € sign.",
+		"Nested docx chunk paragraph.",
+	} {
+		if !strings.Contains(texts, want) {
+			t.Errorf("missing alt chunk text: %q", want)
+		}
+	}
+
+	t.Log(texts)
+}
+
+func TestHiddenMode(t *testing.T) {
+	dp, err := Open(docxHiddenPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dp.Close()
+
+	hidden := []string{
+		"[instruction by character style]",
+		"Instruction inherited from paragraph style.",
+		"Directly hidden paragraph.",
+		"vanish",
+		"Cell hidden",
+	}
+
+	texts, err := dp.ExtractTexts()
+	if err != nil {
+		t.Error(err)
+	}
+	for _, s := range hidden {
+		if !strings.Contains(texts, s) {
+			t.Errorf("hidden text should be included by default: %q", s)
+		}
+	}
+
+	dp.SetHiddenMode(types.HiddenExclude)
+	texts, err = dp.ExtractTexts()
+	if err != nil {
+		t.Error(err)
+	}
+	for _, s := range hidden {
+		if strings.Contains(texts, s) {
+			t.Errorf("hidden text should be excluded: %q", s)
+		}
+	}
+	for _, s := range []string{"Visible start visible end.", "Unhidden by direct formatting.", "Special ", "Cell visible"} {
+		if !strings.Contains(texts, s) {
+			t.Errorf("visible text is missing: %q", s)
+		}
+	}
+
+	dp.SetHiddenMode(types.HiddenMark)
+	texts, err = dp.ExtractTexts()
+	if err != nil {
+		t.Error(err)
+	}
+	for _, s := range []string{
+		"Visible start [hidden][instruction by character style] [/hidden]visible end.",
+		"[hidden]Instruction inherited from paragraph style. [/hidden]Unhidden by direct formatting.",
+		"[hidden]Cell hidden[/hidden]",
+	} {
+		if !strings.Contains(texts, s) {
+			t.Errorf("hidden text is not marked: %q", s)
+		}
+	}
+
+	t.Log(texts)
+}
+
+func TestFormFields(t *testing.T) {
+	dp, err := Open(docxFormsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dp.Close()
+
+	fields, err := dp.FormFields()
+	if err != nil {
+		t.Error(err)
+	}
+
+	want := []types.FormField{
+		{Tag: "name", Alias: "Full Name", Type: types.FormFieldText, Value: "Alice & Bob"},
+		{Tag: "notes", Alias: "Notes", Type: types.FormFieldRichText, Value: "First line.\nSecond line."},
+		{Tag: "date", Type: types.FormFieldDate, Value: "2023-06-01T00:00:00Z"},
+		{Tag: "color", Type: types.FormFieldDropDown, Value: "Green", Options: []string{"Red", "Green"}},
+		{Tag: "city", Type: types.FormFieldComboBox, Value: "Berlin", Options: []string{"Paris"}},
+		{Tag: "agree", Type: types.FormFieldCheckBox, Value: "true"},
+		{Tag: "empty", Type: types.FormFieldText, Value: ""},
+		{Tag: "email", Type: types.FormFieldText, Value: "alice@example.com", Binding: "/ns0:form[1]/ns0:email[1]"},
+		{Tag: "Text1", Type: types.FormFieldText, Value: "Typed value", Legacy: true},
+		{Tag: "Check1", Type: types.FormFieldCheckBox, Value: "true", Legacy: true},
+		{Tag: "Dropdown1", Type: types.FormFieldDropDown, Value: "Large", Options: []string{"Small", "Medium", "Large"}, Legacy: true},
+	}
+	if len(fields) < len(want) {
+		t.Fatalf("got %d form fields, want at least %d", len(fields), len(want))
+	}
+	for i, w := range want {
+		f := fields[i]
+		if f.Tag != w.Tag || f.Alias != w.Alias || f.Type != w.Type || f.Value != w.Value ||
+			f.Binding != w.Binding || f.Legacy != w.Legacy || strings.Join(f.Options, "|") != strings.Join(w.Options, "|") {
+			t.Errorf("form field %d: got %+v, want %+v", i, f, w)
+		}
+	}
+
+	for _, f := range fields {
+		t.Logf("%+v", f)
+	}
+}
+
+func TestMathMode(t *testing.T) {
+	dp, err := Open(docxMathPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dp.Close()
+
+	texts, err := dp.ExtractTexts()
+	if err != nil {
+		t.Error(err)
+	}
+	for _, want := range []string{
+		`Quadratic formula: $x=\frac{-b\pm\sqrt{b^{2}-4ac}}{2a}$`,
+		"$$\\sum_{i=1}^{n}{x_{i}}$$\n$$",
+		"Cell\t$e^{i\\pi}+1=0$",
+	} {
+		if !strings.Contains(texts, want) {
+			t.Errorf("missing LaTeX equation: %q", want)
+		}
+	}
+
+	dp.SetMathMode(types.MathLinear)
+	texts, err = dp.ExtractTexts()
+	if err != nil {
+		t.Error(err)
+	}
+	if !strings.Contains(texts, "x=(−b±√(b^2−4ac))/(2a)") {
+		t.Error("missing linear equation")
+	}
+
+	t.Log(texts)
+}
+
+// textOcr is an ocr interface returning the fixed text.
+type textOcr string
+
+func (o textOcr) Run(r io.Reader) (string, error) { return string(o), nil }
+func (o textOcr) Close() error                    { return nil }
+
+func TestAltText(t *testing.T) {
+	dp, err := Open(docxAltTextPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dp.Close()
+
+	imgs, err := dp.ExtractImages()
+	if err != nil {
+		t.Error(err)
+	}
+	captions := make(map[string]string, len(imgs))
+	for _, img := range imgs {
+		t.Logf("img: %v title: %q alt text: %q caption: %q", img.Name, img.Title, img.AltText, img.Caption)
+		captions[img.AltText] = img.Caption
+	}
+	if captions["Bar chart of quarterly sales & profit"] != "Figure 1: Quarterly sales" {
+		t.Error("caption below the image is not matched")
+	}
+	if captions["Company logo"] != "Figure 2: Logo" {
+		t.Error("caption above the image is not matched")
+	}
+
+	dp.SetParseAltText(true)
+	texts, err := dp.ExtractTexts()
+	if err != nil {
+		t.Error(err)
+	}
+	if !strings.Contains(texts, " Sales\n Bar chart of quarterly sales & profit\n") ||
+		!strings.Contains(texts, " Company logo\n") {
+		t.Error("missing alt text")
+	}
+
+	dp.SetParseAltText(false)
+	dp.SetOcrInterface(textOcr("ocr text"))
+	dp.SetParseImages(true)
+	dp.SetPreferAltText(true)
+	dp.SetDrawingsNoFmt(true)
+	texts, err = dp.ExtractTexts()
+	if err != nil {
+		t.Error(err)
+	}
+	if strings.Contains(texts, "ocr text") || !strings.Contains(texts, "Company logo\n") {
+		t.Error("alt text is not preferred to ocr")
+	}
+
+	t.Log(texts)
+}
+
+func TestPages(t *testing.T) {
+	dp, err := Open(docxPagesPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dp.Close()
+
+	if n := dp.NumPages(); n != 6 {
+		t.Errorf("expected 6 pages, got %d", n)
+	}
+
+	dp.SetPageSep("\n")
+	texts, err := dp.ExtractPageTexts(2, 4)
+	if err != nil {
+		t.Error(err)
+	}
+	if texts != "Second page.\nStill second page, continued on third.\n\nFourth page.\n\n" {
+		t.Errorf("unexpected page texts: %q", texts)
+	}
+	for _, page := range []int{0, 7} {
+		if _, err = dp.ExtractPageTexts(page); err != types.ErrNoPage {
+			t.Errorf("expected ErrNoPage of page %d", page)
+		}
+	}
+
+	dp.SetPageMarker("[page %d]\n")
+	texts, err = dp.ExtractTexts()
+	if err != nil {
+		t.Error(err)
+	}
+	for _, want := range []string{
+		"[page 1]\nCover page.",
+		"[page 3]\nThird page.",
+		"[page 5]\nFifth page.\nCell one\tCell two\t\n[page 6]\nSixth page.\nSixth end.",
+	} {
+		if !strings.Contains(texts, want) {
+			t.Errorf("missing page marker: %q", want)
+		}
+	}
+	// the cached pages are split again with the page marker
+	texts, err = dp.ExtractPageTexts(3)
+	if err != nil || !strings.HasPrefix(texts, "[page 3]\nThird page.") {
+		t.Errorf("unexpected page texts: %q", texts)
+	}
+
+	// the literal "%" is written as is
+	dp.SetPageMarker("<100% page %d>\n")
+	texts, _ = dp.ExtractTexts()
+	if !strings.HasPrefix(texts, "<100% page 1>\nCover page.") || strings.Contains(texts, "%!") {
+		t.Errorf("unexpected page marker: %q", texts)
+	}
+
+	t.Log(texts)
+}
+
+func TestBookmarks(t *testing.T) {
+	dp, err := Open(docxBookmarksPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dp.Close()
+
+	bookmarks, err := dp.Bookmarks()
+	if err != nil {
+		t.Error(err)
+	}
+	for _, bm := range bookmarks {
+		t.Logf("bookmark: %q text: %q page: %d", bm.Name, bm.Text, bm.Page)
+	}
+	if len(bookmarks) != 3 ||
+		bookmarks[0] != (types.Bookmark{Name: "_Ref100", Text: "4.2 Installation", Page: 1}) ||
+		bookmarks[2] != (types.Bookmark{Name: "Appendix", Text: "Appendix A\nMore appendix", Page: 2}) {
+		t.Error("unexpected bookmarks")
+	}
+
+	dp.SetCrossRefFormat("[%s](#%s)")
+	dp.SetBookmarkFormat("{#%s}")
+	texts, err := dp.ExtractTexts()
+	if err != nil {
+		t.Error(err)
+	}
+	for _, want := range []string{
+		"{#_Ref100}4.2 Installation",
+		"See [Section 4.2](#_Ref100).",
+		"Details on page [2](#Appendix).",
+		"{#Appendix}Appendix A",
+		"Ref\t[Appendix A](#Appendix)\t",
+	} {
+		if !strings.Contains(texts, want) {
+			t.Errorf("missing cross-reference: %q", want)
+		}
+	}
+	t.Log(texts)
+
+	// the formats are not formatted by fmt, a literal "%" is written as is
+	dp.SetCrossRefFormat(`<a href="#%[2]s">%[1]s</a> 100%%`)
+	dp.SetBookmarkFormat("<%s 50%>")
+	texts, err = dp.ExtractTexts()
+	if err != nil {
+		t.Error(err)
+	}
+	for _, want := range []string{
+		"<_Ref100 50%>4.2 Installation",
+		`See <a href="#_Ref100">Section 4.2</a> 100%.`,
+	} {
+		if !strings.Contains(texts, want) {
+			t.Errorf("missing cross-reference: %q", want)
+		}
+	}
+	t.Log(texts)
+}
+
+func TestParagraphs(t *testing.T) {
+	dp, err := Open(docxLangPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dp.Close()
+
+	paragraphs, err := dp.ExtractParagraphs()
+	if err != nil {
+		t.Error(err)
+	}
+	for _, p := range paragraphs {
+		t.Logf("paragraph: %q style: %q rtl: %v page: %d", p.Text, p.Style, p.RTL, p.Page)
+		for _, span := range p.Spans {
+			t.Logf("  span: %q lang: %q rtl: %v", span.Text, span.Lang, span.RTL)
+		}
+	}
+	if len(paragraphs) != 4 {
+		t.Fatalf("unexpected paragraphs: %d", len(paragraphs))
+	}
+	if spans := paragraphs[0].Spans; len(spans) != 3 || spans[0].Lang != "en-US" || spans[1] != (types.Span{Text: "世界", Lang: "zh-CN"}) {
+		t.Error("unexpected east asian language")
+	}
+	if p := paragraphs[1]; !p.RTL || len(p.Spans) != 3 ||
+		p.Spans[0] != (types.Span{Text: "مرحبا بالعالم ", Lang: "ar-SA", RTL: true}) ||
+		p.Spans[1] != (types.Span{Text: "Word", Lang: "en-GB"}) {
+		t.Error("unexpected bidi language")
+	}
+	if p := paragraphs[2]; !p.RTL || p.Style != "Arabic" || len(p.Spans) != 1 || !p.Spans[0].RTL {
+		t.Error("unexpected direction inherited from paragraph style")
+	}
+
+	dp.SetBidiMarks(true)
+	texts, err := dp.ExtractTexts()
+	if err != nil {
+		t.Error(err)
+	}
+	for _, want := range []string{
+		"\u200fمرحبا بالعالم \u2066Word\u2069، ٢٠٢٣\n",
+		"\u200fالسلام عليكم\n",
+		"English with \u2067عربي\u2069.\n",
+	} {
+		if !strings.Contains(texts, want) {
+			t.Errorf("missing bidi text: %q", want)
+		}
+	}
+
+	t.Logf("%q", texts)
+}
+
+func TestFormatting(t *testing.T) {
+	dp, err := Open(docxFormatPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dp.Close()
+
+	paragraphs, err := dp.ExtractParagraphs()
+	if err != nil {
+		t.Error(err)
+	}
+	spans := make(map[string]types.Span)
+	for _, p := range paragraphs {
+		for _, span := range p.Spans {
+			t.Logf("span: %+v", span)
+			spans[span.Text] = span
+		}
+	}
+
+	for text, want := range map[string]types.Span{
+		"Heading ": {Bold: true}, // paragraph style
+		"not bold": {},           // direct formatting overrides style
+		"bold":     {Bold: true}, // character style merged with direct formatting
+		"italic":   {Italic: true},
+		"under":    {Underline: true},
+		"no line":  {},
+		"struck":   {Strike: true},          // double strikethrough
+		"عريض":     {Bold: true, RTL: true}, // complex script bold
+		"ليس":      {RTL: true},
+		"E=mc":     {},
+		"Plain ":   {},
+		"and ":     {},
+	} {
+		span := spans[text]
+		got := types.Span{Bold: span.Bold, Italic: span.Italic, Underline: span.Underline, Strike: span.Strike, RTL: span.RTL}
+		if got != want {
+			t.Errorf("unexpected formatting of %q: %+v", text, span)
+		}
+	}
+	if p := paragraphs[1]; len(p.Spans) != 12 || !p.Spans[8].Superscript || !p.Spans[10].Subscript {
+		t.Error("unexpected vertical alignment")
+	}
+}
+
+func TestRenamedParts(t *testing.T) {
+	want, err := Open(docxPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer want.Close()
+	dp, err := Open(docxRenamedPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dp.Close()
+
+	// the parts are discovered by relationships instead of their conventional names
+	want.SetParseCharts(true)
+	dp.SetParseCharts(true)
+	wantTexts, _ := want.ExtractTexts()
+	texts, err := dp.ExtractTexts()
+	if err != nil {
+		t.Error(err)
+	}
+	if texts == "" || texts != wantTexts {
+		t.Error("unexpected texts of renamed parts")
+	}
+	imgs, err := dp.ExtractImages()
+	if err != nil {
+		t.Error(err)
+	}
+	t.Logf("images: %d", len(imgs))
+	if len(imgs) == 0 {
+		t.Error("missing images")
+	}
+
+	t.Log(texts)
+}
+
+func TestStrict(t *testing.T) {
+	want, err := Open(docxPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer want.Close()
+	dp, err := Open(docxStrictPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dp.Close()
+
+	// the parts use the Strict namespaces and non-default prefixes
+	want.SetParseCharts(true)
+	dp.SetParseCharts(true)
+	wantTexts, _ := want.ExtractTexts()
+	texts, err := dp.ExtractTexts()
+	if err != nil {
+		t.Error(err)
+	}
+	if texts == "" || texts != wantTexts {
+		t.Error("unexpected texts of strict parts")
+	}
+
+	t.Log(texts)
+}
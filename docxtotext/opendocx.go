@@ -11,20 +11,14 @@ import (
 	"io"
 	"regexp"
 
+	"github.com/young2j/oxmltotext/opc"
+	"github.com/young2j/oxmltotext/types"
 	"github.com/young2j/oxmltotext/utils"
 )
 
 var (
-	re_DOCUMENT  = regexp.MustCompile(`word/document\.xml`)
-	re_COMMENTS  = regexp.MustCompile(`word/comments\.xml`)
-	re_ENDNOTES  = regexp.MustCompile(`word/endnotes\.xml`)
-	re_FOOTNOTES = regexp.MustCompile(`word/footnotes\.xml`)
-	re_FOOTER    = regexp.MustCompile(`word/footer\d+\.xml`)
-	re_HEADER    = regexp.MustCompile(`word/header\d+\.xml`)
-	re_DOC_RELS  = regexp.MustCompile(`word/_rels/document\.xml\.rels`)
-	re_CHARTS    = regexp.MustCompile(`word/charts/chart\d+\.xml`)
-	re_IMAGES    = regexp.MustCompile(`word/media/image\d+\.(?:png|gif|jpg|jpeg)`)
-	re_DIAGRAMS  = regexp.MustCompile(`word/diagrams/data\d+\.xml`)
+	re_IMAGES = regexp.MustCompile(`\.(?i:png|gif|jpg|jpeg)$`)
+	re_CHUNKS = regexp.MustCompile(`\.(?i:mht|mhtml|htm|html|xhtml|rtf|txt|docx|docm|dotx|dotm)$`)
 )
 
 // Open opens the specified docx file path and returns a new DocxParser instance and an error, if any.
@@ -99,10 +93,11 @@ func OpenURL(u string) (*DocxParser, int, error) {
 
 // matchZipFile matches the zip file with the given DocxParser and zip.Reader.
 //
-// It populates the footerFiles, headerFiles, chartsFiles, imagesFiles, and diagramsFiles
-// fields of the DocxParser based on the files found in the zip.Reader. It also sets the
-// documentFile, commentsFile, endnotesFile, footnotesFile, and docRelsMap fields if the
-// corresponding files are found in the zip.Reader.
+// The parts are discovered by the relationships of the package: the main document part is the target of
+// officeDocument relationship(so .docm/.dotx/.dotm and renamed parts are supported), the comments, endnotes,
+// footnotes, footers, headers and styles are the targets of the main part relationships, and the charts,
+// images, diagrams, embeddings, imported chunks and custom XML parts are the targets of the relationships
+// of any part reachable from the package.
 //
 // Parameters:
 //   - dp: A pointer to the DocxParser instance.
@@ -111,39 +106,66 @@ func OpenURL(u string) (*DocxParser, int, error) {
 // Returns:
 //   - error: An error if any occurs during the matching process.
 func matchZipFile(dp *DocxParser, r *zip.Reader) error {
-	dp.footerFiles = make([]*zip.File, 0, max(len(r.File)-20, 1))
-	dp.headerFiles = make([]*zip.File, 0, max(len(r.File)-20, 1))
+	pkg, err := opc.Open(r)
+	if err != nil {
+		return err
+	}
+
+	dp.footerFiles = make([]*zip.File, 0, 4)
+	dp.headerFiles = make([]*zip.File, 0, 4)
 	dp.chartsFiles = make(map[string]*zip.File, 4)
 	dp.imagesFiles = make(map[string]*zip.File, 4)
 	dp.diagramsFiles = make(map[string]*zip.File, 4)
-	for _, file := range r.File {
-		switch {
-		case re_DOCUMENT.MatchString(file.Name):
-			dp.documentFile = file
-		case re_COMMENTS.MatchString(file.Name):
+	dp.embedsFiles = make(map[string]*zip.File, 4)
+	dp.chunksFiles = make(map[string]*zip.File, 4)
+	dp.customXmlFiles = make(map[string]*zip.File, 4)
+
+	main := pkg.MainPart("word/document.xml")
+	if main == "" {
+		return types.ErrNonePart
+	}
+	dp.documentFile = pkg.File(main)
+	if dp.docRelsMap, err = pkg.RelsMap(main); err != nil {
+		return err
+	}
+
+	return pkg.Walk(func(source string, rel opc.Relationship) {
+		file := pkg.File(rel.Target)
+		switch rel.Kind() {
+		case "chart":
+			dp.chartsFiles[rel.Target] = file
+		case "image":
+			if re_IMAGES.MatchString(rel.Target) {
+				dp.imagesFiles[rel.Target] = file
+			}
+		case "diagramData":
+			dp.diagramsFiles[rel.Target] = file
+		case "oleObject", "package":
+			dp.embedsFiles[rel.Target] = file
+		case "aFChunk":
+			if re_CHUNKS.MatchString(rel.Target) {
+				dp.chunksFiles[rel.Target] = file
+			}
+		case "customXml", "customXmlProps":
+			dp.customXmlFiles[rel.Target] = file
+		}
+		if source != main {
+			return
+		}
+
+		switch rel.Kind() {
+		case "comments":
 			dp.commentsFile = file
-		case re_ENDNOTES.MatchString(file.Name):
+		case "endnotes":
 			dp.endnotesFile = file
-		case re_FOOTNOTES.MatchString(file.Name):
+		case "footnotes":
 			dp.footnotesFile = file
-		case re_FOOTER.MatchString(file.Name):
+		case "footer":
 			dp.footerFiles = append(dp.footerFiles, file)
-		case re_HEADER.MatchString(file.Name):
+		case "header":
 			dp.headerFiles = append(dp.headerFiles, file)
-		case re_CHARTS.MatchString(file.Name):
-			dp.chartsFiles[file.Name] = file
-		case re_IMAGES.MatchString(file.Name):
-			dp.imagesFiles[file.Name] = file
-		case re_DIAGRAMS.MatchString(file.Name):
-			dp.diagramsFiles[file.Name] = file
-		case re_DOC_RELS.MatchString(file.Name):
-			relsMap, err := utils.ParseRelsMap(file, "word/")
-			if err != nil {
-				return err
-			}
-			dp.docRelsMap = relsMap
+		case "styles":
+			dp.stylesFile = file
 		}
-	}
-
-	return nil
+	})
 }
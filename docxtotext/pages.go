@@ -0,0 +1,202 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package docxtotext
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/young2j/oxmltotext/types"
+)
+
+// pageState estimates the pages of the document body, since docx has no rendered pages.
+//
+// A new page is started by the page breaks rendered by Word(w:lastRenderedPageBreak),
+// page breaks(w:br w:type="page"), paragraphs with w:pageBreakBefore and section breaks
+// whose next section starts on a new page. The rendered page break right after an explicit
+// page break is the same page break and is skipped.
+//
+// A page break before the text of a paragraph starts the page at the paragraph,
+// otherwise the page starts after the paragraph or the table containing the break.
+type pageState struct {
+	texts    *strings.Builder // the texts of document body
+	marker   string
+	starts   []int // the offsets of texts where the pages start
+	pending  int   // the page breaks applied after current paragraph or table
+	explicit int   // the offset of texts after the last explicit page break, -1 if none
+	// the pending page breaks contain an explicit page break
+	pendingExplicit bool
+	sectBreak       bool
+}
+
+// newPageState creates a pageState of the document body texts, and starts the first page.
+//
+// Parameters:
+//   - texts: the texts of document body.
+//   - marker: the format of the page marker written at the start of each page, empty for no marker.
+//
+// Returns:
+//   - *pageState: the pageState.
+func newPageState(texts *strings.Builder, marker string) *pageState {
+	ps := &pageState{
+		texts:    texts,
+		marker:   marker,
+		starts:   make([]int, 0, 8),
+		explicit: -1,
+	}
+	ps.newPage()
+
+	return ps
+}
+
+// newPage starts a new page at the end of texts and writes the page marker.
+func (ps *pageState) newPage() {
+	ps.starts = append(ps.starts, ps.texts.Len())
+	if ps.marker != "" {
+		ps.texts.WriteString(strings.ReplaceAll(ps.marker, "%d", strconv.Itoa(len(ps.starts))))
+	}
+}
+
+// offset returns the offset of texts including the text of current paragraph.
+func (ps *pageState) offset(paragraph *strings.Builder) int {
+	if paragraph == nil {
+		return ps.texts.Len()
+	}
+	return ps.texts.Len() + paragraph.Len()
+}
+
+// pageBreak tracks a page break.
+//
+// Parameters:
+//   - paragraph: the text of current paragraph, nil in tables.
+//   - explicit: false if the page break is rendered by Word(w:lastRenderedPageBreak).
+func (ps *pageState) pageBreak(paragraph *strings.Builder, explicit bool) {
+	if !explicit && ps.explicit == ps.offset(paragraph) {
+		ps.explicit = -1
+		return
+	}
+
+	if paragraph != nil && paragraph.Len() == 0 {
+		ps.newPage()
+	} else {
+		ps.pending++
+		ps.pendingExplicit = ps.pendingExplicit || explicit
+	}
+	if explicit {
+		ps.explicit = ps.offset(paragraph)
+	}
+}
+
+// paragraphProps tracks the page breaks of the paragraph properties.
+//
+// Parameters:
+//   - paragraph: the text of current paragraph, nil in tables.
+//   - props: the paragraph properties.
+func (ps *pageState) paragraphProps(paragraph *strings.Builder, props paraProps) {
+	if props.pageBreakBefore {
+		ps.pageBreak(paragraph, true)
+	}
+	if props.sectBreak {
+		ps.sectBreak = true
+	}
+}
+
+// flush applies the pending page breaks, it's called after the text of a paragraph or table is written.
+func (ps *pageState) flush() {
+	if ps.sectBreak {
+		ps.sectBreak = false
+		ps.pending++
+		ps.pendingExplicit = true
+	}
+	if ps.pending == 0 {
+		return
+	}
+
+	for ; ps.pending > 0; ps.pending-- {
+		ps.newPage()
+	}
+	if ps.pendingExplicit {
+		ps.explicit = ps.texts.Len()
+		ps.pendingExplicit = false
+	}
+}
+
+// pages splits the texts of document body into pages.
+func (ps *pageState) pages() []string {
+	var (
+		texts = ps.texts.String()
+		pages = make([]string, len(ps.starts))
+	)
+	for i, start := range ps.starts {
+		end := len(texts)
+		if i+1 < len(ps.starts) {
+			end = ps.starts[i+1]
+		}
+		pages[i] = texts[start:end]
+	}
+
+	return pages
+}
+
+// extractPages extracts the texts of document body by the estimated pages.
+// The pages are split once and cached until an option affecting the document text is set.
+//
+// Parameters:
+//   - None
+//
+// Returns:
+//   - []string: the texts of pages.
+//   - error: an error if any.
+func (dp *DocxParser) extractPages() ([]string, error) {
+	if dp.pages != nil {
+		return dp.pages, nil
+	}
+
+	_, ps, err := dp.extractDocument()
+	if err != nil {
+		return nil, err
+	}
+	dp.pages = ps.pages()
+
+	return dp.pages, nil
+}
+
+// NumPages returns the estimated number of pages of document body, see ExtractPageTexts.
+func (dp *DocxParser) NumPages() int {
+	pages, err := dp.extractPages()
+	dp.logWarn(err)
+
+	return len(pages)
+}
+
+// ExtractPageTexts extracts the texts of document body from the specified pages(start 1).
+//
+// Docx has no rendered pages, the pages are estimated by the page breaks rendered by Word,
+// explicit page breaks and section breaks. Headers, footers, comments and notes are not included.
+//
+// Parameters:
+//   - pages: the page numbers to extract the texts from.
+//
+// Returns:
+//   - string: the texts of the specified pages, each page is followed by the page separator.
+//   - error: an error if any.
+func (dp *DocxParser) ExtractPageTexts(pages ...int) (string, error) {
+	texts := new(strings.Builder)
+	pageTexts, err := dp.extractPages()
+	if err != nil {
+		return "", err
+	}
+
+	for _, page := range pages {
+		if page < 1 || page > len(pageTexts) {
+			return texts.String(), types.ErrNoPage
+		}
+		texts.WriteString(pageTexts[page-1])
+		texts.WriteString(dp.pageSep)
+	}
+
+	return texts.String(), nil
+}
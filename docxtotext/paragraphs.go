@@ -0,0 +1,150 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package docxtotext
+
+import (
+	"html"
+	"strings"
+
+	"github.com/young2j/oxmltotext/opc"
+	"github.com/young2j/oxmltotext/types"
+	"github.com/young2j/oxmltotext/utils"
+
+	qxml "github.com/dgrr/quickxml"
+)
+
+// ExtractParagraphs extracts the paragraphs of document body with the spans of their text.
+//
+// The run properties are resolved through the styles part, each span has the language(w:lang) chosen
+// by the script of the text, the direction(w:rtl) and the formatting of the runs, and each paragraph
+// has its direction(w:bidi).
+// The text is in logical order, the bidi marks are not written. The hidden runs are excluded in
+// types.HiddenExclude mode. The paragraphs of text boxes are returned before the paragraph containing them.
+//
+// Parameters:
+//   - None
+//
+// Returns:
+//   - []types.Paragraph: the non-empty paragraphs in document order.
+//   - error: an error if any.
+func (dp *DocxParser) ExtractParagraphs() ([]types.Paragraph, error) {
+	if dp.documentFile == nil {
+		dp.logWarn(types.ErrNoDocument)
+		return nil, nil
+	}
+
+	rc, err := opc.OpenPart(dp.documentFile)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var (
+		paragraphs = make([]types.Paragraph, 0, 16)
+		paras      = make([]*types.Paragraph, 0, 2)
+		texts      = new(strings.Builder)
+		paragraph  = new(strings.Builder)
+		ps         = newPageState(texts, "")
+		rs         = dp.newRunState()
+		w_t        = ""
+	)
+	// the properties are always tracked for the spans.
+	rs.bidi, rs.active, rs.styles = false, true, dp.loadStyles()
+	r := qxml.NewReader(rc)
+
+	// writeSpan appends the text of current run to current paragraph.
+	writeSpan := func(text string, math bool) {
+		if len(paras) == 0 || rs.excluded() {
+			return
+		}
+		span := types.Span{Text: text, Hidden: rs.hidden()}
+		if !math {
+			props := rs.props()
+			span.Lang, span.RTL = props.language(text), props.rtl == onOffOn
+			props.format(&span)
+		}
+		para := paras[len(paras)-1]
+		para.Spans = utils.AppendSpan(para.Spans, span)
+		paragraph.WriteString(text)
+	}
+
+NEXT:
+	for r.Next() {
+		switch e := r.Element().(type) {
+		case *qxml.StartElement:
+			switch e.Name() {
+			case "w:pPr":
+				props := parseParagraphProps(r, e)
+				rs.setParagraphProps(props)
+				if para := rs.paragraph(); para != nil && len(paras) > 0 {
+					paras[len(paras)-1].Style, paras[len(paras)-1].RTL = para.pStyle, para.rtl
+				}
+				ps.paragraphProps(paragraph, props)
+				continue
+
+			// the fallback content duplicates the choice content.
+			case "mc:Fallback":
+				utils.SkipElement(r, e)
+				continue
+			}
+			if rs.start(r, e) {
+				continue
+			}
+			switch e.Name() {
+			case "w:p":
+				if !e.HasEnd() {
+					paras = append(paras, new(types.Paragraph))
+				}
+
+			case "w:t":
+				r.AssignNext(&w_t)
+				if !r.Next() {
+					break NEXT
+				}
+				writeSpan(html.UnescapeString(w_t), false)
+				w_t = ""
+
+			case "w:tab":
+				writeSpan("\t", false)
+
+			case "m:oMathPara", "m:oMath":
+				math := utils.ReadXMLElement(r, e)
+				writeSpan(utils.OMMLToText(math, dp.mathMode), true)
+
+			case "w:lastRenderedPageBreak":
+				ps.pageBreak(paragraph, false)
+
+			case "w:br":
+				if brType := e.Attrs().Get("w:type"); brType != nil && brType.Value() == "page" {
+					ps.pageBreak(paragraph, true)
+				}
+			}
+
+		case *qxml.EndElement:
+			rs.end(e)
+			if e.Name() != "w:p" || len(paras) == 0 {
+				continue
+			}
+			para := paras[len(paras)-1]
+			paras = paras[:len(paras)-1]
+			if len(para.Spans) > 0 {
+				text := new(strings.Builder)
+				for _, span := range para.Spans {
+					text.WriteString(span.Text)
+				}
+				para.Text = text.String()
+				para.Page = len(ps.starts)
+				paragraphs = append(paragraphs, *para)
+				paragraph.WriteString("\n")
+			}
+			texts.WriteString(paragraph.String())
+			paragraph.Reset()
+			ps.flush()
+		}
+	}
+
+	return paragraphs, nil
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package docxtotext
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/young2j/oxmltotext/types"
+	"github.com/young2j/oxmltotext/utils"
+)
+
+// extractAltChunk extracts text content from the imported alternative format content(w:altChunk).
+//
+// The format of the content is determined by the part extension, or sniffed from the content
+// if the extension is unknown, and converted to paragraphs in place.
+//
+// Parameters:
+//   - rId: the reference id of the alternative format content.
+//
+// Returns:
+//   - *strings.Builder: the text of the alternative format content.
+//   - error: any error that occurred during the extraction process.
+func (dp *DocxParser) extractAltChunk(rId string) (*strings.Builder, error) {
+	if rId == "" {
+		return nil, types.ErrEmptyRID
+	}
+
+	fname, ok := dp.docRelsMap[rId]
+	if !ok {
+		return nil, types.ErrNonePart
+	}
+
+	f, ok := dp.chunksFiles[fname]
+	if !ok {
+		return nil, types.ErrNonePart
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+
+	var text string
+	switch altChunkFormat(fname, data) {
+	case types.EXT_DOCX:
+		return dp.extractAltChunkDocx(data)
+	case ".mht":
+		text, err = utils.MHTToText(data)
+		if err != nil {
+			return nil, err
+		}
+	case types.EXT_HTML:
+		text = utils.HTMLToText(utils.BytesToString(data))
+	case types.EXT_RTF:
+		text = utils.RTFToText(data)
+	default:
+		text = utils.BytesToString(data)
+	}
+
+	texts := new(strings.Builder)
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if len(line) > 0 {
+			texts.WriteString(line)
+			texts.WriteString(dp.paragraphSep)
+		}
+	}
+
+	return texts, nil
+}
+
+// extractAltChunkDocx extracts the document text of the imported docx content
+// with the same settings as the DocxParser.
+func (dp *DocxParser) extractAltChunkDocx(data []byte) (*strings.Builder, error) {
+	child, err := OpenReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	child.paragraphSep = dp.paragraphSep
+	child.tableRowSep = dp.tableRowSep
+	child.tableColSep = dp.tableColSep
+	child.parseCharts = dp.parseCharts
+	child.parseImages = dp.parseImages
+	child.parseAltText = dp.parseAltText
+	child.preferAltText = dp.preferAltText
+	child.parseDiagrams = dp.parseDiagrams
+	child.parseEmbeds = dp.parseEmbeds
+	child.drawingsNoFmt = dp.drawingsNoFmt
+	child.embedsMaxDepth = dp.embedsMaxDepth
+	child.hiddenMode = dp.hiddenMode
+	child.mathMode = dp.mathMode
+	child.crossRefFormat = dp.crossRefFormat
+	child.bookmarkFormat = dp.bookmarkFormat
+	child.bidiMarks = dp.bidiMarks
+	child.embed = dp.embed
+	child.ocr = dp.ocr
+	child.disableLogging = dp.disableLogging
+	defer func() {
+		// the ocr client is owned by the parent parser.
+		child.ocr = nil
+		child.Close()
+	}()
+
+	texts, _, err := child.extractDocument()
+	return texts, err
+}
+
+// altChunkFormat returns the format of the alternative format content as a file extension.
+func altChunkFormat(fname string, data []byte) string {
+	switch strings.ToLower(filepath.Ext(fname)) {
+	case types.EXT_DOCX, ".docm", ".dotx", ".dotm":
+		return types.EXT_DOCX
+	case ".mht", ".mhtml":
+		return ".mht"
+	case types.EXT_HTM, types.EXT_HTML, types.EXT_XHTML:
+		return types.EXT_HTML
+	case types.EXT_RTF:
+		return types.EXT_RTF
+	case types.EXT_TXT:
+		return types.EXT_TXT
+	}
+
+	head := bytes.TrimSpace(data[:min(len(data), 512)])
+	switch {
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")):
+		return types.EXT_DOCX
+	case bytes.HasPrefix(head, []byte(`{\rtf`)):
+		return types.EXT_RTF
+	case bytes.Contains(bytes.ToLower(head), []byte("mime-version:")):
+		return ".mht"
+	case bytes.HasPrefix(head, []byte("<")):
+		return types.EXT_HTML
+	}
+
+	return types.EXT_TXT
+}
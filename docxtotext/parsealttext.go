@@ -0,0 +1,185 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package docxtotext
+
+import (
+	"html"
+	"strings"
+
+	"github.com/young2j/oxmltotext/opc"
+	"github.com/young2j/oxmltotext/types"
+	"github.com/young2j/oxmltotext/utils"
+
+	qxml "github.com/dgrr/quickxml"
+)
+
+// captionState is the parsing state of a paragraph which may be a caption or contain images.
+type captionState struct {
+	caption bool
+	text    strings.Builder
+	images  []*types.Image
+}
+
+// parseImageInfos parses the alt text and captions of the images referenced by the document part.
+//
+// The alt text is read from the first drawing(wp:docPr or pic:cNvPr) with alt text referencing the image.
+// A caption is a paragraph of the Caption style or containing a SEQ field, which labels the
+// images of the paragraph right above or below it, empty paragraphs between are ignored.
+//
+// Parameters:
+//   - None
+//
+// Returns:
+//   - map[string]*types.Image: the images with alt text and caption, keyed by the image part name.
+//   - error: an error if any.
+func (dp *DocxParser) parseImageInfos() (map[string]*types.Image, error) {
+	infos := make(map[string]*types.Image, len(dp.imagesFiles))
+	if dp.documentFile == nil {
+		return infos, nil
+	}
+
+	rc, err := opc.OpenPart(dp.documentFile)
+	if err != nil {
+		return infos, err
+	}
+	defer rc.Close()
+
+	var (
+		paras   = make([]*captionState, 0, 2)
+		prev    []*types.Image // images of the previous paragraph
+		pending = ""           // caption of the previous paragraph
+		title   = ""
+		descr   = ""
+		text    = ""
+	)
+	r := qxml.NewReader(rc)
+
+NEXT:
+	for r.Next() {
+		switch e := r.Element().(type) {
+		case *qxml.StartElement:
+			switch e.Name() {
+			case "w:p":
+				if !e.HasEnd() {
+					paras = append(paras, new(captionState))
+				}
+
+			case "w:pStyle":
+				val := e.Attrs().Get("w:val")
+				if val != nil && strings.EqualFold(val.Value(), "Caption") && len(paras) > 0 {
+					paras[len(paras)-1].caption = true
+				}
+
+			case "w:fldSimple":
+				instr := e.Attrs().Get("w:instr")
+				if instr != nil && isSeqField(instr.Value()) && len(paras) > 0 {
+					paras[len(paras)-1].caption = true
+				}
+
+			case "w:instrText", "w:t":
+				r.AssignNext(&text)
+				if !r.Next() {
+					break NEXT
+				}
+				if len(paras) == 0 {
+					continue
+				}
+				para := paras[len(paras)-1]
+				if e.Name() == "w:t" {
+					para.text.WriteString(html.UnescapeString(text))
+				} else if isSeqField(text) {
+					para.caption = true
+				}
+				text = ""
+
+			case "wp:docPr":
+				title, descr = utils.DrawingAltText(e)
+
+			case "pic:cNvPr":
+				if t, d := utils.DrawingAltText(e); t != "" || d != "" {
+					title, descr = t, d
+				}
+
+			case "a:blip":
+				rId := e.Attrs().Get("r:embed")
+				if rId == nil || len(paras) == 0 {
+					continue
+				}
+				name, ok := dp.docRelsMap[rId.Value()]
+				if !ok {
+					continue
+				}
+				info, ok := infos[name]
+				if !ok {
+					info = &types.Image{Name: name}
+					infos[name] = info
+				}
+				if info.Title == "" && info.AltText == "" {
+					info.Title, info.AltText = title, descr
+				}
+				para := paras[len(paras)-1]
+				para.images = append(para.images, info)
+			}
+
+		case *qxml.EndElement:
+			if e.Name() != "w:p" || len(paras) == 0 {
+				continue
+			}
+			para := paras[len(paras)-1]
+			paras = paras[:len(paras)-1]
+			caption := strings.TrimSpace(para.text.String())
+
+			switch {
+			case para.caption && caption != "" && len(para.images) > 0:
+				setCaption(para.images, caption)
+				prev, pending = nil, ""
+			case para.caption && caption != "":
+				if setCaption(prev, caption) {
+					pending = ""
+				} else {
+					pending = caption
+				}
+				prev = nil
+			case len(para.images) > 0:
+				setCaption(para.images, pending)
+				prev, pending = para.images, ""
+			case caption != "":
+				prev, pending = nil, ""
+			}
+		}
+	}
+
+	return infos, nil
+}
+
+// setCaption sets the caption of the images which have no caption yet.
+//
+// Parameters:
+//   - images: the images to label.
+//   - caption: the caption text.
+//
+// Returns:
+//   - bool: true if any image is labeled.
+func setCaption(images []*types.Image, caption string) bool {
+	labeled := false
+	if caption == "" {
+		return labeled
+	}
+	for _, img := range images {
+		if img.Caption == "" {
+			img.Caption = caption
+			labeled = true
+		}
+	}
+
+	return labeled
+}
+
+// isSeqField reports whether the field instruction is a SEQ field, like " SEQ Figure \* ARABIC ".
+func isSeqField(instr string) bool {
+	fields := strings.Fields(instr)
+	return len(fields) > 0 && strings.EqualFold(fields[0], "SEQ")
+}
@@ -10,6 +10,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/young2j/oxmltotext/opc"
 	"github.com/young2j/oxmltotext/types"
 	"github.com/young2j/oxmltotext/utils"
 
@@ -20,6 +21,7 @@ import (
 //
 // The function iterates through the XML elements of the reader and
 // extracts the drawings(charts, images, and diagrams) if the corresponding flags are set.
+// The alt text of images(wp:docPr and pic:cNvPr) is written alongside them if enabled.
 //
 // Parameters:
 //
@@ -28,7 +30,10 @@ import (
 // Returns:
 //   - *strings.Builder: a strings.Builder containing the extracted drawings text.
 func (dp *DocxParser) extractDrawings(r *qxml.Reader) *strings.Builder {
-	var texts = new(strings.Builder)
+	var (
+		texts   = new(strings.Builder)
+		altText = ""
+	)
 
 NEXT:
 	for r.Next() {
@@ -51,7 +56,18 @@ NEXT:
 					}
 				}
 
-			case e.Name() == "a:blip" && dp.parseImages:
+			case e.Name() == "wp:docPr" || e.Name() == "pic:cNvPr":
+				if title, descr := utils.DrawingAltText(e); title != "" || descr != "" {
+					altText = utils.JoinAltText(title, descr)
+				}
+
+			case e.Name() == "a:blip":
+				if altText != "" && (dp.parseAltText || dp.parseImages && dp.preferAltText) {
+					texts.WriteString(utils.FormatBox(altText, "alt text", dp.drawingsNoFmt).String())
+				}
+				if !dp.parseImages || dp.preferAltText && altText != "" {
+					continue
+				}
 				attrs := e.Attrs()
 				if attrs.Len() > 0 {
 					rIdKV := attrs.Get("r:embed")
@@ -102,7 +118,7 @@ func (dp *DocxParser) extractChart(rId string) (*strings.Builder, error) {
 		return nil, types.ErrNonePart
 	}
 
-	rc, err := f.Open()
+	rc, err := opc.OpenPart(f)
 	if err != nil {
 		return nil, err
 	}
@@ -261,7 +277,7 @@ func (dp *DocxParser) extractDiagram(rId string) (*strings.Builder, error) {
 		return nil, types.ErrNonePart
 	}
 
-	rc, err := f.Open()
+	rc, err := opc.OpenPart(f)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,337 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package docxtotext
+
+import (
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/young2j/oxmltotext/opc"
+	"github.com/young2j/oxmltotext/types"
+	"github.com/young2j/oxmltotext/utils"
+
+	qxml "github.com/dgrr/quickxml"
+)
+
+// sdtTypes maps the type elements of content control properties to form field types.
+var sdtTypes = map[string]string{
+	"w:text":         types.FormFieldText,
+	"w:richText":     types.FormFieldRichText,
+	"w:date":         types.FormFieldDate,
+	"w:dropDownList": types.FormFieldDropDown,
+	"w:comboBox":     types.FormFieldComboBox,
+	"w14:checkbox":   types.FormFieldCheckBox,
+	"w:picture":      types.FormFieldPicture,
+	"w:docPartObj":   types.FormFieldDocPart,
+	"w:docPartList":  types.FormFieldDocPart,
+	"w:group":        types.FormFieldGroup,
+}
+
+// formState is the parsing state of a form field.
+type formState struct {
+	index       int // index of the field in the results
+	depth       int // field nesting depth of legacy form field
+	inResult    bool
+	placeholder bool
+	fullDate    string
+	checked     string
+	storeID     string
+	text        strings.Builder
+}
+
+// FormFields extracts the form fields of the document part, including content controls(w:sdt)
+// and legacy form fields(FORMTEXT, FORMCHECKBOX and FORMDROPDOWN).
+//
+// The value of a content control is its text content, the ISO date(w:fullDate) of a date picker,
+// "true" or "false" of a checkbox, and empty if the placeholder text is showing.
+// The value of a content control bound to custom XML data(w:dataBinding) is read from the customXml part.
+//
+// Parameters:
+//   - None
+//
+// Returns:
+//   - []types.FormField: the form fields in document order.
+//   - error: an error if any.
+func (dp *DocxParser) FormFields() ([]types.FormField, error) {
+	if dp.documentFile == nil {
+		dp.logWarn(types.ErrNoDocument)
+		return nil, nil
+	}
+
+	rc, err := opc.OpenPart(dp.documentFile)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var (
+		fields   = make([]types.FormField, 0, 8)
+		sdts     = make([]*formState, 0, 4)
+		legacy   *formState
+		bindings = make(map[int]string)
+		fldDepth = 0
+		w_t      = ""
+	)
+	r := qxml.NewReader(rc)
+
+	// writeText writes the text to the open content controls and the result of legacy form field.
+	writeText := func(text string) {
+		for _, sdt := range sdts {
+			sdt.text.WriteString(text)
+		}
+		if legacy != nil && legacy.inResult {
+			legacy.text.WriteString(text)
+		}
+	}
+
+NEXT:
+	for r.Next() {
+		switch e := r.Element().(type) {
+		case *qxml.StartElement:
+			switch e.Name() {
+			case "w:sdt":
+				if e.HasEnd() {
+					continue
+				}
+				fields = append(fields, types.FormField{})
+				sdts = append(sdts, &formState{index: len(fields) - 1})
+
+			case "w:sdtPr":
+				if e.HasEnd() || len(sdts) == 0 {
+					continue
+				}
+				sdt := sdts[len(sdts)-1]
+				parseSdtPr(r, &fields[sdt.index], sdt)
+				if sdt.storeID != "" {
+					bindings[sdt.index] = sdt.storeID
+				}
+
+			case "w:t":
+				r.AssignNext(&w_t)
+				if !r.Next() {
+					break NEXT
+				}
+				writeText(html.UnescapeString(w_t))
+				w_t = ""
+
+			case "w:tab":
+				writeText("\t")
+
+			case "w:br", "w:cr":
+				writeText("\n")
+
+			case "w:fldChar":
+				fldCharType := e.Attrs().Get("w:fldCharType")
+				if fldCharType == nil {
+					continue
+				}
+				switch fldCharType.Value() {
+				case "begin":
+					fldDepth++
+					if e.HasEnd() || legacy != nil {
+						continue
+					}
+					if field, ok := parseFFData(r); ok {
+						fields = append(fields, field)
+						legacy = &formState{index: len(fields) - 1, depth: fldDepth}
+					}
+				case "separate":
+					if legacy != nil && legacy.depth == fldDepth {
+						legacy.inResult = true
+					}
+				case "end":
+					if legacy != nil && legacy.depth == fldDepth {
+						field := &fields[legacy.index]
+						if field.Type == types.FormFieldText {
+							field.Value = strings.TrimSpace(legacy.text.String())
+						}
+						legacy = nil
+					}
+					fldDepth--
+				}
+			}
+
+		case *qxml.EndElement:
+			switch e.Name() {
+			case "w:p":
+				writeText("\n")
+
+			case "w:sdt":
+				if len(sdts) == 0 {
+					continue
+				}
+				sdt := sdts[len(sdts)-1]
+				sdts = sdts[:len(sdts)-1]
+				field := &fields[sdt.index]
+				if field.Type == "" {
+					field.Type = types.FormFieldRichText
+				}
+				switch {
+				case field.Type == types.FormFieldCheckBox:
+					field.Value = sdt.checked
+				case sdt.placeholder:
+				case field.Type == types.FormFieldDate && sdt.fullDate != "":
+					field.Value = sdt.fullDate
+				default:
+					field.Value = strings.TrimRight(sdt.text.String(), "\n")
+				}
+			}
+		}
+	}
+
+	if len(bindings) > 0 {
+		stores := dp.loadCustomXml()
+		for i, storeID := range bindings {
+			store, ok := stores[strings.ToUpper(storeID)]
+			if !ok {
+				continue
+			}
+			if v, ok := evalXPath(store, fields[i].Binding); ok {
+				fields[i].Value = v
+			}
+		}
+	}
+
+	return fields, nil
+}
+
+// parseSdtPr parses the content control properties until the end of w:sdtPr.
+func parseSdtPr(r *qxml.Reader, field *types.FormField, sdt *formState) {
+	sdt.checked = "false"
+
+	for r.Next() {
+		switch e := r.Element().(type) {
+		case *qxml.StartElement:
+			name := e.Name()
+			if typ, ok := sdtTypes[name]; ok {
+				field.Type = typ
+			}
+			attrs := e.Attrs()
+			switch name {
+			case "w:tag":
+				if val := attrs.Get("w:val"); val != nil {
+					field.Tag = html.UnescapeString(val.Value())
+				}
+			case "w:alias":
+				if val := attrs.Get("w:val"); val != nil {
+					field.Alias = html.UnescapeString(val.Value())
+				}
+			case "w:showingPlcHdr":
+				sdt.placeholder = parseOnOff(e) == onOffOn
+			case "w:date":
+				if fullDate := attrs.Get("w:fullDate"); fullDate != nil {
+					sdt.fullDate = fullDate.Value()
+				}
+			case "w:listItem":
+				item := attrs.Get("w:displayText")
+				if item == nil {
+					item = attrs.Get("w:value")
+				}
+				if item != nil {
+					field.Options = append(field.Options, html.UnescapeString(item.Value()))
+				}
+			case "w14:checked":
+				if val := attrs.Get("w14:val"); val != nil && parseOnOffValue(val.Value()) {
+					sdt.checked = "true"
+				}
+			case "w:dataBinding":
+				if xpath := attrs.Get("w:xpath"); xpath != nil {
+					field.Binding = html.UnescapeString(xpath.Value())
+				}
+				if storeID := attrs.Get("w:storeItemID"); storeID != nil {
+					sdt.storeID = storeID.Value()
+				}
+			case "w:rPr", "w:placeholder":
+				utils.SkipElement(r, e)
+			}
+
+		case *qxml.EndElement:
+			if e.Name() == "w:sdtPr" {
+				return
+			}
+		}
+	}
+}
+
+// parseFFData parses the legacy form field data(w:ffData) until the end of the begin w:fldChar.
+//
+// Parameters:
+//   - r: the qxml.Reader whose begin w:fldChar start element has been read.
+//
+// Returns:
+//   - types.FormField: the legacy form field, whose text value is filled by the field result later.
+//   - bool: true if the w:fldChar contains form field data.
+func parseFFData(r *qxml.Reader) (types.FormField, bool) {
+	var (
+		field    = types.FormField{Legacy: true}
+		found    = false
+		dflt     = ""
+		checked  = ""
+		result   = -1
+		inDdList = false
+	)
+
+	for r.Next() {
+		switch e := r.Element().(type) {
+		case *qxml.StartElement:
+			val := e.Attrs().Get("w:val")
+			switch e.Name() {
+			case "w:ffData":
+				found = true
+			case "w:name":
+				if val != nil {
+					field.Tag = html.UnescapeString(val.Value())
+				}
+			case "w:textInput":
+				field.Type = types.FormFieldText
+			case "w:checkBox":
+				field.Type = types.FormFieldCheckBox
+			case "w:ddList":
+				field.Type = types.FormFieldDropDown
+				inDdList = !e.HasEnd()
+			case "w:default":
+				if val != nil {
+					dflt = val.Value()
+				}
+			case "w:checked":
+				checked = strconv.FormatBool(parseOnOff(e) == onOffOn)
+			case "w:result":
+				if val != nil && inDdList {
+					result, _ = strconv.Atoi(val.Value())
+				}
+			case "w:listEntry":
+				if val != nil {
+					field.Options = append(field.Options, html.UnescapeString(val.Value()))
+				}
+			}
+
+		case *qxml.EndElement:
+			switch e.Name() {
+			case "w:ddList":
+				inDdList = false
+			case "w:fldChar":
+				switch field.Type {
+				case types.FormFieldCheckBox:
+					if checked == "" {
+						checked = strconv.FormatBool(parseOnOffValue(dflt))
+					}
+					field.Value = checked
+				case types.FormFieldDropDown:
+					if result < 0 {
+						result, _ = strconv.Atoi(dflt)
+					}
+					if result >= 0 && result < len(field.Options) {
+						field.Value = field.Options[result]
+					}
+				}
+				return field, found && field.Type != ""
+			}
+		}
+	}
+
+	return field, false
+}
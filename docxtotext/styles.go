@@ -0,0 +1,678 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package docxtotext
+
+import (
+	"archive/zip"
+	"strings"
+
+	"github.com/young2j/oxmltotext/opc"
+	"github.com/young2j/oxmltotext/types"
+	"github.com/young2j/oxmltotext/utils"
+
+	qxml "github.com/dgrr/quickxml"
+)
+
+// maxStylesDepth limits the basedOn chain of styles to guard against cycles.
+const maxStylesDepth = 32
+
+// onOff is a tri-state on/off property, the zero value means the property is not specified.
+type onOff int8
+
+const (
+	onOffUnset onOff = iota
+	onOffOn
+	onOffOff
+)
+
+// parseOnOff parses the on/off property element, an element without w:val is on.
+func parseOnOff(e *qxml.StartElement) onOff {
+	val := e.Attrs().Get("w:val")
+	if val == nil {
+		return onOffOn
+	}
+	switch val.Value() {
+	case "0", "false", "off":
+		return onOffOff
+	}
+
+	return onOffOn
+}
+
+// runProps is the run properties(w:rPr) concerned by the parser.
+type runProps struct {
+	rStyle string
+	vanish onOff
+	rtl    onOff
+	cs     onOff // complex script formatting
+	// bold and italic of latin/east asian and complex script text
+	b, bCs, i, iCs  onOff
+	strike, dstrike onOff
+	u               string // the underline type
+	vertAlign       string // baseline, superscript or subscript
+	// languages(w:lang) of latin, east asian and complex script(bidi) text
+	lang, langEastAsia, langBidi string
+}
+
+// merge overrides the properties of rp with the specified properties of o.
+func (rp *runProps) merge(o runProps) {
+	if o.vanish != onOffUnset {
+		rp.vanish = o.vanish
+	}
+	if o.rtl != onOffUnset {
+		rp.rtl = o.rtl
+	}
+	if o.cs != onOffUnset {
+		rp.cs = o.cs
+	}
+	if o.b != onOffUnset {
+		rp.b = o.b
+	}
+	if o.bCs != onOffUnset {
+		rp.bCs = o.bCs
+	}
+	if o.i != onOffUnset {
+		rp.i = o.i
+	}
+	if o.iCs != onOffUnset {
+		rp.iCs = o.iCs
+	}
+	if o.strike != onOffUnset {
+		rp.strike = o.strike
+	}
+	if o.dstrike != onOffUnset {
+		rp.dstrike = o.dstrike
+	}
+	if o.u != "" {
+		rp.u = o.u
+	}
+	if o.vertAlign != "" {
+		rp.vertAlign = o.vertAlign
+	}
+	if o.lang != "" {
+		rp.lang = o.lang
+	}
+	if o.langEastAsia != "" {
+		rp.langEastAsia = o.langEastAsia
+	}
+	if o.langBidi != "" {
+		rp.langBidi = o.langBidi
+	}
+}
+
+// language returns the language of the run text, which is chosen by the script of the text:
+// the bidi language for right-to-left or complex script runs, the east asian language for
+// the text containing CJK characters, otherwise the latin language.
+func (rp runProps) language(text string) string {
+	lang := rp.lang
+	switch {
+	case rp.rtl == onOffOn || rp.cs == onOffOn:
+		if rp.langBidi != "" {
+			lang = rp.langBidi
+		}
+	case rp.langEastAsia != "" && utils.HasEastAsian(text):
+		lang = rp.langEastAsia
+	}
+
+	return lang
+}
+
+// format sets the formatting of the span by the run properties,
+// the complex script bold and italic(w:bCs and w:iCs) are used for right-to-left or complex script runs.
+func (rp runProps) format(span *types.Span) {
+	b, i := rp.b, rp.i
+	if rp.rtl == onOffOn || rp.cs == onOffOn {
+		b, i = rp.bCs, rp.iCs
+	}
+	span.Bold = b == onOffOn
+	span.Italic = i == onOffOn
+	span.Underline = rp.u != "" && rp.u != "none"
+	span.Strike = rp.strike == onOffOn || rp.dstrike == onOffOn
+	span.Superscript = rp.vertAlign == "superscript"
+	span.Subscript = rp.vertAlign == "subscript"
+}
+
+// hidden reports whether the run is hidden(w:vanish or w:specVanish).
+func (rp runProps) hidden() bool {
+	return rp.vanish == onOffOn
+}
+
+// parseRunProps parses the run properties until the end of w:rPr.
+// The start element w:rPr must have been read, and the revised properties(w:rPrChange) are skipped.
+func parseRunProps(r *qxml.Reader) runProps {
+	var rp runProps
+
+	for r.Next() {
+		switch e := r.Element().(type) {
+		case *qxml.StartElement:
+			switch e.Name() {
+			case "w:rStyle":
+				if val := e.Attrs().Get("w:val"); val != nil {
+					rp.rStyle = val.Value()
+				}
+			case "w:vanish":
+				rp.vanish = parseOnOff(e)
+			case "w:specVanish":
+				if parseOnOff(e) == onOffOn {
+					rp.vanish = onOffOn
+				}
+			case "w:b":
+				rp.b = parseOnOff(e)
+			case "w:bCs":
+				rp.bCs = parseOnOff(e)
+			case "w:i":
+				rp.i = parseOnOff(e)
+			case "w:iCs":
+				rp.iCs = parseOnOff(e)
+			case "w:strike":
+				rp.strike = parseOnOff(e)
+			case "w:dstrike":
+				rp.dstrike = parseOnOff(e)
+			case "w:u":
+				rp.u = "single"
+				if val := e.Attrs().Get("w:val"); val != nil {
+					rp.u = val.Value()
+				}
+			case "w:vertAlign":
+				if val := e.Attrs().Get("w:val"); val != nil {
+					rp.vertAlign = val.Value()
+				}
+			case "w:rtl":
+				rp.rtl = parseOnOff(e)
+			case "w:cs":
+				rp.cs = parseOnOff(e)
+			case "w:lang":
+				attrs := e.Attrs()
+				if val := attrs.Get("w:val"); val != nil {
+					rp.lang = val.Value()
+				}
+				if val := attrs.Get("w:eastAsia"); val != nil {
+					rp.langEastAsia = val.Value()
+				}
+				if val := attrs.Get("w:bidi"); val != nil {
+					rp.langBidi = val.Value()
+				}
+			case "w:rPrChange":
+				utils.SkipElement(r, e)
+			}
+
+		case *qxml.EndElement:
+			if e.Name() == "w:rPr" {
+				return rp
+			}
+		}
+	}
+
+	return rp
+}
+
+// docxStyle is a style definition(w:style) of styles part.
+type docxStyle struct {
+	basedOn string
+	rPr     runProps
+	bidi    onOff
+}
+
+// docxStyles is the style definitions of styles part.
+type docxStyles struct {
+	defaults  runProps
+	bidi      onOff // the default paragraph direction
+	styles    map[string]*docxStyle
+	paragraph string // id of the default paragraph style
+}
+
+// parseStyles parses the document defaults and style definitions from the styles part.
+//
+// Parameters:
+//   - f: the styles part file.
+//
+// Returns:
+//   - *docxStyles: the parsed styles.
+//   - error: an error if any.
+func parseStyles(f *zip.File) (*docxStyles, error) {
+	ds := &docxStyles{styles: make(map[string]*docxStyle)}
+	if f == nil {
+		return ds, nil
+	}
+
+	rc, err := opc.OpenPart(f)
+	if err != nil {
+		return ds, err
+	}
+	defer rc.Close()
+
+	var (
+		style      *docxStyle
+		rPrDefault bool
+		pPrDefault bool
+	)
+	r := qxml.NewReader(rc)
+
+	for r.Next() {
+		switch e := r.Element().(type) {
+		case *qxml.StartElement:
+			switch e.Name() {
+			case "w:rPrDefault":
+				rPrDefault = !e.HasEnd()
+
+			case "w:pPrDefault":
+				pPrDefault = !e.HasEnd()
+
+			case "w:style":
+				attrs := e.Attrs()
+				id := attrs.Get("w:styleId")
+				if id == nil {
+					continue
+				}
+				style = new(docxStyle)
+				ds.styles[id.Value()] = style
+				typ, dflt := attrs.Get("w:type"), attrs.Get("w:default")
+				if typ != nil && typ.Value() == "paragraph" && dflt != nil && parseOnOffValue(dflt.Value()) {
+					ds.paragraph = id.Value()
+				}
+
+			case "w:basedOn":
+				if val := e.Attrs().Get("w:val"); style != nil && val != nil {
+					style.basedOn = val.Value()
+				}
+
+			case "w:rPr":
+				if e.HasEnd() {
+					continue
+				}
+				switch {
+				case rPrDefault:
+					ds.defaults = parseRunProps(r)
+				case style != nil:
+					style.rPr = parseRunProps(r)
+				}
+
+			case "w:pPr":
+				bidi := parseParagraphProps(r, e).bidi
+				switch {
+				case pPrDefault:
+					ds.bidi = bidi
+				case style != nil:
+					style.bidi = bidi
+				}
+
+			// conditional formatting of table styles
+			case "w:tblStylePr":
+				utils.SkipElement(r, e)
+			}
+
+		case *qxml.EndElement:
+			switch e.Name() {
+			case "w:rPrDefault":
+				rPrDefault = false
+			case "w:pPrDefault":
+				pPrDefault = false
+			case "w:style":
+				style = nil
+			}
+		}
+	}
+
+	return ds, nil
+}
+
+// parseOnOffValue parses the value of an on/off attribute.
+func parseOnOffValue(v string) bool {
+	switch v {
+	case "1", "true", "on":
+		return true
+	}
+	return false
+}
+
+// resolve resolves the effective run properties by the style hierarchy:
+// document defaults, paragraph style, run style and direct formatting.
+//
+// Parameters:
+//   - pStyle: the paragraph style id, the default paragraph style is used if it is empty.
+//   - direct: the direct formatting run properties.
+//
+// Returns:
+//   - runProps: the effective run properties.
+func (ds *docxStyles) resolve(pStyle string, direct runProps) runProps {
+	if ds == nil {
+		return direct
+	}
+
+	rp := ds.defaults
+	if pStyle == "" {
+		pStyle = ds.paragraph
+	}
+	ds.mergeStyle(&rp, pStyle, 0)
+	ds.mergeStyle(&rp, direct.rStyle, 0)
+	rp.merge(direct)
+
+	return rp
+}
+
+// mergeStyle merges the run properties of the style and its base styles into rp.
+func (ds *docxStyles) mergeStyle(rp *runProps, id string, depth int) {
+	style, ok := ds.styles[id]
+	if !ok || depth > maxStylesDepth {
+		return
+	}
+	ds.mergeStyle(rp, style.basedOn, depth+1)
+	rp.merge(style.rPr)
+}
+
+// resolveBidi resolves the paragraph direction by the style hierarchy:
+// document defaults, paragraph style and direct formatting.
+//
+// Parameters:
+//   - pStyle: the paragraph style id, the default paragraph style is used if it is empty.
+//   - direct: the direct formatting w:bidi property.
+//
+// Returns:
+//   - bool: true if the paragraph is right-to-left.
+func (ds *docxStyles) resolveBidi(pStyle string, direct onOff) bool {
+	if direct != onOffUnset || ds == nil {
+		return direct == onOffOn
+	}
+
+	if pStyle == "" {
+		pStyle = ds.paragraph
+	}
+	bidi := ds.bidi
+	for depth := 0; depth <= maxStylesDepth; depth++ {
+		style, ok := ds.styles[pStyle]
+		if !ok {
+			break
+		}
+		if style.bidi != onOffUnset {
+			bidi = style.bidi
+			break
+		}
+		pStyle = style.basedOn
+	}
+
+	return bidi == onOffOn
+}
+
+// loadStyles parses the styles part once and returns the parsed styles.
+func (dp *DocxParser) loadStyles() *docxStyles {
+	if dp.styles == nil {
+		styles, err := parseStyles(dp.stylesFile)
+		dp.logWarn(err)
+		dp.styles = styles
+	}
+
+	return dp.styles
+}
+
+// The unicode bidi marks written for right-to-left text.
+const (
+	bidiRLM = "\u200f" // right-to-left mark
+	bidiLRI = "\u2066" // left-to-right isolate
+	bidiRLI = "\u2067" // right-to-left isolate
+	bidiPDI = "\u2069" // pop directional isolate
+)
+
+// paraState is the state of a paragraph tracked by runState.
+type paraState struct {
+	pStyle string
+	rtl    bool
+	rlm    bool // the right-to-left mark is written
+}
+
+// runState tracks the properties of paragraphs and runs while iterating a part,
+// and writes the text of runs according to the hidden mode and the bidi marks setting.
+type runState struct {
+	styles   *docxStyles
+	mode     types.HiddenMode
+	bidi     bool // write bidi marks for right-to-left text
+	active   bool // the properties are tracked
+	paras    []paraState
+	runs     []runProps
+	marked   bool
+	isolated bool
+}
+
+// newRunState returns a new runState with the hidden mode and the bidi marks setting of the DocxParser.
+func (dp *DocxParser) newRunState() *runState {
+	rs := &runState{mode: dp.hiddenMode, bidi: dp.bidiMarks}
+	rs.active = rs.mode != types.HiddenInclude || rs.bidi
+	if rs.active {
+		rs.styles = dp.loadStyles()
+	}
+
+	return rs
+}
+
+// start tracks the start element of paragraphs, runs and their properties.
+//
+// Parameters:
+//   - r: the qxml.Reader of the part.
+//   - e: the start element that has been read.
+//
+// Returns:
+//   - bool: true if the element and its children are consumed.
+func (rs *runState) start(r *qxml.Reader, e *qxml.StartElement) bool {
+	if !rs.active {
+		return false
+	}
+
+	switch e.Name() {
+	case "w:p":
+		if !e.HasEnd() {
+			rs.paras = append(rs.paras, paraState{})
+		}
+
+	case "w:pPr":
+		rs.setParagraphProps(parseParagraphProps(r, e))
+		return true
+
+	case "w:r":
+		if !e.HasEnd() {
+			rs.runs = append(rs.runs, runProps{})
+		}
+
+	case "w:rPr":
+		if e.HasEnd() {
+			return true
+		}
+		rp := parseRunProps(r)
+		if len(rs.runs) > 0 {
+			rs.runs[len(rs.runs)-1] = rp
+		}
+		return true
+	}
+
+	return false
+}
+
+// paraProps is the paragraph properties(w:pPr) concerned by the parser.
+type paraProps struct {
+	pStyle          string
+	bidi            onOff
+	pageBreakBefore bool
+	sectBreak       bool // the paragraph ends a section whose next section starts on a new page
+}
+
+// parseParagraphProps parses the paragraph properties until the end of w:pPr.
+// The run properties of paragraph mark and the revised properties(w:pPrChange) are skipped.
+//
+// Parameters:
+//   - r: the qxml.Reader of the part.
+//   - e: the w:pPr start element that has been read.
+//
+// Returns:
+//   - paraProps: the paragraph properties.
+func parseParagraphProps(r *qxml.Reader, e *qxml.StartElement) paraProps {
+	var props paraProps
+	if e.HasEnd() {
+		return props
+	}
+
+	for r.Next() {
+		switch e := r.Element().(type) {
+		case *qxml.StartElement:
+			switch e.Name() {
+			case "w:pStyle":
+				if val := e.Attrs().Get("w:val"); val != nil {
+					props.pStyle = val.Value()
+				}
+			case "w:bidi":
+				props.bidi = parseOnOff(e)
+			case "w:pageBreakBefore":
+				props.pageBreakBefore = parseOnOff(e) == onOffOn
+			case "w:sectPr":
+				// a section break without type starts a new page.
+				props.sectBreak = true
+			case "w:type":
+				if val := e.Attrs().Get("w:val"); val != nil {
+					props.sectBreak = val.Value() != "continuous" && val.Value() != "nextColumn"
+				}
+			case "w:rPr", "w:pPrChange", "w:sectPrChange":
+				utils.SkipElement(r, e)
+			}
+
+		case *qxml.EndElement:
+			if e.Name() == "w:pPr" {
+				return props
+			}
+		}
+	}
+
+	return props
+}
+
+// setParagraphProps sets the properties of current paragraph.
+func (rs *runState) setParagraphProps(props paraProps) {
+	if len(rs.paras) > 0 {
+		para := &rs.paras[len(rs.paras)-1]
+		para.pStyle = props.pStyle
+		para.rtl = rs.styles.resolveBidi(props.pStyle, props.bidi)
+	}
+}
+
+// end tracks the end element of paragraphs and runs.
+func (rs *runState) end(e *qxml.EndElement) {
+	if !rs.active {
+		return
+	}
+
+	switch e.Name() {
+	case "w:p":
+		if len(rs.paras) > 0 {
+			rs.paras = rs.paras[:len(rs.paras)-1]
+		}
+	case "w:r":
+		if len(rs.runs) > 0 {
+			rs.runs = rs.runs[:len(rs.runs)-1]
+		}
+	}
+}
+
+// paragraph returns the state of current paragraph, or nil if not in a paragraph.
+func (rs *runState) paragraph() *paraState {
+	if len(rs.paras) == 0 {
+		return nil
+	}
+	return &rs.paras[len(rs.paras)-1]
+}
+
+// props resolves the effective properties of current run by the styles.
+func (rs *runState) props() runProps {
+	var direct runProps
+	if len(rs.runs) > 0 {
+		direct = rs.runs[len(rs.runs)-1]
+	}
+	pStyle := ""
+	if para := rs.paragraph(); para != nil {
+		pStyle = para.pStyle
+	}
+
+	return rs.styles.resolve(pStyle, direct)
+}
+
+// hidden reports whether the current run is hidden after resolving the styles.
+func (rs *runState) hidden() bool {
+	if rs.mode == types.HiddenInclude || len(rs.runs) == 0 {
+		return false
+	}
+
+	return rs.props().hidden()
+}
+
+// excluded reports whether the content of current run should be excluded.
+func (rs *runState) excluded() bool {
+	return rs.mode == types.HiddenExclude && rs.hidden()
+}
+
+// writeText writes the text of current run to w according to the hidden mode and the bidi marks setting.
+func (rs *runState) writeText(w *strings.Builder, text string) {
+	if rs.hidden() {
+		switch rs.mode {
+		case types.HiddenExclude:
+			return
+		case types.HiddenMark:
+			if !rs.marked {
+				rs.closeIsolate(w)
+				w.WriteString(types.HiddenMarkStart)
+				rs.marked = true
+			}
+		}
+	} else if rs.marked {
+		rs.closeMark(w)
+	}
+	rs.writeBidi(w, text)
+	w.WriteString(text)
+}
+
+// writeBidi writes the right-to-left mark at the start of right-to-left paragraphs,
+// and isolates the runs whose direction is different from the paragraph.
+// The isolate is kept open across the runs of whitespace only.
+func (rs *runState) writeBidi(w *strings.Builder, text string) {
+	if !rs.bidi {
+		return
+	}
+
+	para := rs.paragraph()
+	if para == nil {
+		return
+	}
+	if para.rtl && !para.rlm {
+		w.WriteString(bidiRLM)
+		para.rlm = true
+	}
+
+	if strings.TrimSpace(text) == "" {
+		return
+	}
+	rtl := rs.props().rtl == onOffOn
+	switch {
+	case rtl == para.rtl:
+		rs.closeIsolate(w)
+	case !rs.isolated && rtl:
+		w.WriteString(bidiRLI)
+		rs.isolated = true
+	case !rs.isolated:
+		w.WriteString(bidiLRI)
+		rs.isolated = true
+	}
+}
+
+// closeIsolate writes the pop directional isolate to w if an isolate is open.
+func (rs *runState) closeIsolate(w *strings.Builder) {
+	if rs.isolated {
+		w.WriteString(bidiPDI)
+		rs.isolated = false
+	}
+}
+
+// closeMark writes the pop directional isolate and the hidden end mark to w if they are open.
+func (rs *runState) closeMark(w *strings.Builder) {
+	rs.closeIsolate(w)
+	if rs.marked {
+		w.WriteString(types.HiddenMarkEnd)
+		rs.marked = false
+	}
+}
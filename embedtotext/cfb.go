@@ -0,0 +1,307 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package embedtotext
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"unicode/utf16"
+)
+
+const (
+	cfbEndOfChain = 0xFFFFFFFE
+	cfbFreeSect   = 0xFFFFFFFF
+	cfbStream     = 2
+	cfbRoot       = 5
+)
+
+var (
+	cfbMagic = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+
+	errBadCfb = errors.New("the compound file is malformed")
+)
+
+// cfbEntry is a directory entry of the compound file.
+type cfbEntry struct {
+	name  string
+	typ   byte
+	start uint32
+	size  uint64
+}
+
+// cfbReader is a minimal reader of compound file binary format(OLE2 structured storage).
+type cfbReader struct {
+	data           []byte
+	sectorSize     int
+	miniSectorSize int
+	miniCutoff     uint64
+	fat            []uint32
+	miniFat        []uint32
+	miniStream     []byte
+	entries        []cfbEntry
+}
+
+// isCfb reports whether the data starts with the compound file signature.
+func isCfb(data []byte) bool {
+	return bytes.HasPrefix(data, cfbMagic)
+}
+
+// newCfbReader parses the header, FAT, mini FAT and directory of the compound file.
+//
+// Parameters:
+//   - data: the whole compound file content.
+//
+// Returns:
+//   - *cfbReader: the compound file reader.
+//   - error: an error if the compound file is malformed.
+func newCfbReader(data []byte) (*cfbReader, error) {
+	if len(data) < 512 || !isCfb(data) {
+		return nil, errBadCfb
+	}
+
+	le := binary.LittleEndian
+	sectorShift := le.Uint16(data[0x1E:])
+	miniSectorShift := le.Uint16(data[0x20:])
+	if sectorShift < 7 || sectorShift > 16 || miniSectorShift > sectorShift {
+		return nil, errBadCfb
+	}
+
+	cr := &cfbReader{
+		data:           data,
+		sectorSize:     1 << sectorShift,
+		miniSectorSize: 1 << miniSectorShift,
+		miniCutoff:     uint64(le.Uint32(data[0x38:])),
+	}
+
+	// the counts of the header can not exceed the number of sectors of the file.
+	var (
+		maxSectors      = uint32(len(data) / cr.sectorSize)
+		numFatSectors   = min(le.Uint32(data[0x2C:]), maxSectors)
+		firstDirSector  = le.Uint32(data[0x30:])
+		firstMiniFat    = le.Uint32(data[0x3C:])
+		firstDifat      = le.Uint32(data[0x44:])
+		numDifatSectors = min(le.Uint32(data[0x48:]), maxSectors)
+		fatSectors      = make([]uint32, 0, numFatSectors)
+		visited         = make(map[uint32]bool, numDifatSectors)
+	)
+
+	// the first 109 FAT sector locations are stored in the header.
+	for i := 0; i < 109 && uint32(len(fatSectors)) < numFatSectors; i++ {
+		fatSectors = append(fatSectors, le.Uint32(data[0x4C+i*4:]))
+	}
+
+	// the rest are stored in the chained DIFAT sectors, a cyclic chain is malformed.
+	perDifat := cr.sectorSize/4 - 1
+	for s, n := firstDifat, uint32(0); n < numDifatSectors && s < cfbEndOfChain; n++ {
+		sector := cr.sector(s)
+		if sector == nil || visited[s] {
+			return nil, errBadCfb
+		}
+		visited[s] = true
+		for i := 0; i < perDifat && uint32(len(fatSectors)) < numFatSectors; i++ {
+			fatSectors = append(fatSectors, le.Uint32(sector[i*4:]))
+		}
+		s = le.Uint32(sector[perDifat*4:])
+	}
+
+	cr.fat = make([]uint32, 0, len(fatSectors)*cr.sectorSize/4)
+	for _, s := range fatSectors {
+		sector := cr.sector(s)
+		if sector == nil {
+			return nil, errBadCfb
+		}
+		for i := 0; i < cr.sectorSize; i += 4 {
+			cr.fat = append(cr.fat, le.Uint32(sector[i:]))
+		}
+	}
+
+	dir, err := cr.readChain(firstDirSector, -1)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i+128 <= len(dir); i += 128 {
+		raw := dir[i : i+128]
+		nameLen := int(le.Uint16(raw[64:]))
+		if nameLen > 64 {
+			nameLen = 64
+		}
+		u16 := make([]uint16, 0, nameLen/2)
+		for j := 0; j+1 < nameLen; j += 2 {
+			c := le.Uint16(raw[j:])
+			if c == 0 {
+				break
+			}
+			u16 = append(u16, c)
+		}
+		cr.entries = append(cr.entries, cfbEntry{
+			name:  string(utf16.Decode(u16)),
+			typ:   raw[66],
+			start: le.Uint32(raw[116:]),
+			size:  le.Uint64(raw[120:]),
+		})
+	}
+	if len(cr.entries) == 0 || cr.entries[0].typ != cfbRoot {
+		return nil, errBadCfb
+	}
+
+	if firstMiniFat < cfbEndOfChain {
+		miniFat, err := cr.readChain(firstMiniFat, -1)
+		if err != nil {
+			return nil, err
+		}
+		cr.miniFat = make([]uint32, 0, len(miniFat)/4)
+		for i := 0; i+4 <= len(miniFat); i += 4 {
+			cr.miniFat = append(cr.miniFat, le.Uint32(miniFat[i:]))
+		}
+	}
+
+	root := cr.entries[0]
+	if root.start < cfbEndOfChain {
+		cr.miniStream, err = cr.readChain(root.start, int64(root.size))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return cr, nil
+}
+
+// sector returns the content of the sector with the given id, or nil if out of range.
+func (cr *cfbReader) sector(id uint32) []byte {
+	offset := (int64(id) + 1) * int64(cr.sectorSize)
+	if id >= cfbEndOfChain || offset+int64(cr.sectorSize) > int64(len(cr.data)) {
+		return nil
+	}
+	return cr.data[offset : offset+int64(cr.sectorSize)]
+}
+
+// readChain reads a sector chain starting at start through FAT. If size is not negative,
+// the result is truncated to size.
+func (cr *cfbReader) readChain(start uint32, size int64) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	for s, n := start, 0; s < cfbEndOfChain; n++ {
+		if n > len(cr.fat) {
+			return nil, errBadCfb
+		}
+		sector := cr.sector(s)
+		if sector == nil || int(s) >= len(cr.fat) {
+			return nil, errBadCfb
+		}
+		buf.Write(sector)
+		if size >= 0 && int64(buf.Len()) >= size {
+			break
+		}
+		s = cr.fat[s]
+	}
+	if size >= 0 && int64(buf.Len()) > size {
+		buf.Truncate(int(size))
+	}
+
+	return buf.Bytes(), nil
+}
+
+// readMiniChain reads a mini sector chain starting at start through mini FAT.
+func (cr *cfbReader) readMiniChain(start uint32, size int64) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	for s, n := start, 0; s < cfbEndOfChain && int64(buf.Len()) < size; n++ {
+		offset := int(s) * cr.miniSectorSize
+		if n > len(cr.miniFat) || int(s) >= len(cr.miniFat) || offset+cr.miniSectorSize > len(cr.miniStream) {
+			return nil, errBadCfb
+		}
+		buf.Write(cr.miniStream[offset : offset+cr.miniSectorSize])
+		s = cr.miniFat[s]
+	}
+	if int64(buf.Len()) > size {
+		buf.Truncate(int(size))
+	}
+
+	return buf.Bytes(), nil
+}
+
+// has reports whether a stream with the given name exists.
+func (cr *cfbReader) has(name string) bool {
+	for _, e := range cr.entries {
+		if e.typ == cfbStream && e.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// stream returns the content of the stream with the given name.
+//
+// Parameters:
+//   - name: the name of the stream.
+//
+// Returns:
+//   - []byte: the content of the stream.
+//   - error: io.EOF if the stream is not found, or an error if the stream is malformed.
+func (cr *cfbReader) stream(name string) ([]byte, error) {
+	for _, e := range cr.entries {
+		if e.typ != cfbStream || e.name != name {
+			continue
+		}
+		if e.size < cr.miniCutoff {
+			return cr.readMiniChain(e.start, int64(e.size))
+		}
+		return cr.readChain(e.start, int64(e.size))
+	}
+
+	return nil, io.EOF
+}
+
+// parseOle10Native extracts the file name and the data of an embedded file
+// from the "\x01Ole10Native" stream written by the OLE Packager.
+//
+// Parameters:
+//   - data: the content of the "\x01Ole10Native" stream.
+//
+// Returns:
+//   - string: the file name of the embedded file.
+//   - []byte: the content of the embedded file.
+//   - error: an error if the stream is malformed.
+func parseOle10Native(data []byte) (string, []byte, error) {
+	le := binary.LittleEndian
+	// total size(4) and header(2)
+	pos := 6
+	cstring := func() (string, bool) {
+		i := bytes.IndexByte(data[min(pos, len(data)):], 0)
+		if i < 0 {
+			return "", false
+		}
+		s := string(data[pos : pos+i])
+		pos += i + 1
+		return s, true
+	}
+
+	label, ok := cstring()
+	if !ok {
+		return "", nil, errBadCfb
+	}
+	// original path
+	if _, ok = cstring(); !ok {
+		return "", nil, errBadCfb
+	}
+	// type(4), data path length(4) and data path
+	if pos+8 > len(data) {
+		return "", nil, errBadCfb
+	}
+	pos += 4
+	pathLen := int(le.Uint32(data[pos:]))
+	pos += 4 + pathLen
+	if pos+4 > len(data) {
+		return "", nil, errBadCfb
+	}
+	size := int(le.Uint32(data[pos:]))
+	pos += 4
+	if size < 0 || pos+size > len(data) {
+		return "", nil, errBadCfb
+	}
+
+	return label, data[pos : pos+size], nil
+}
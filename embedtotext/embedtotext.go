@@ -0,0 +1,201 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+/*
+Package embedtotext provides default embed interface implementation for extracting text
+from the embedded objects(OLE objects and packages) of docx/xlsx/pptx files.
+
+Embedded docx/xlsx/pptx packages are parsed recursively with the corresponding parser,
+embedded pdf files are parsed by go-fitz, and legacy doc/xls/ppt objects are parsed by
+the antiword cmd, the xlstotext cmd and the tika server respectively.
+*/
+package embedtotext
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/young2j/oxmltotext/doctotext"
+	"github.com/young2j/oxmltotext/docxtotext"
+	"github.com/young2j/oxmltotext/pdftotext"
+	"github.com/young2j/oxmltotext/ppttotext"
+	"github.com/young2j/oxmltotext/pptxtotext"
+	"github.com/young2j/oxmltotext/types"
+	"github.com/young2j/oxmltotext/xlstotext"
+	"github.com/young2j/oxmltotext/xlsxtotext"
+)
+
+var (
+	zipMagic = []byte("PK\x03\x04")
+	pdfMagic = []byte("%PDF")
+)
+
+// DefaultEmbed is the default implementation of types.Embed.
+type DefaultEmbed struct {
+	tikaServerURL string
+}
+
+// NewDefaultEmbed initializes and returns a new instance of the default embed implementation.
+func NewDefaultEmbed() *DefaultEmbed {
+	return &DefaultEmbed{}
+}
+
+// SetTikaServerURL sets the tika server URL used for extracting text from embedded ppt objects.
+func (d *DefaultEmbed) SetTikaServerURL(u string) {
+	d.tikaServerURL = u
+}
+
+// Run extracts text from the embedded object.
+//
+// Parameters:
+//   - r: the reader of the embedded object.
+//   - n: the size of the embedded object.
+//   - name: the part name of the embedded object.
+//   - maxDepth: the remaining depth of recursively parsing the embedded objects of the embedded object.
+//
+// Returns:
+//   - string: the extracted text.
+//   - error: types.ErrUnknownEmbed if the format is not supported, or any error occurred during the extraction.
+func (d *DefaultEmbed) Run(r io.ReaderAt, n int64, name string, maxDepth int) (string, error) {
+	data := make([]byte, n)
+	if _, err := r.ReadAt(data, 0); err != nil && err != io.EOF {
+		return "", err
+	}
+
+	return d.run(data, name, maxDepth)
+}
+
+// run dispatches the data to the corresponding parser by its signature.
+func (d *DefaultEmbed) run(data []byte, name string, maxDepth int) (string, error) {
+	switch {
+	case bytes.HasPrefix(data, zipMagic):
+		return d.runPackage(data, maxDepth)
+
+	case bytes.HasPrefix(data, pdfMagic):
+		pp, err := pdftotext.OpenReader(bytes.NewReader(data))
+		if err != nil {
+			return "", err
+		}
+		defer pp.Close()
+		return pp.ExtractTexts()
+
+	case isCfb(data):
+		return d.runCfb(data, name, maxDepth)
+	}
+
+	switch strings.ToLower(filepath.Ext(name)) {
+	case types.EXT_TXT, types.EXT_CSV, types.EXT_MD:
+		return string(data), nil
+	}
+
+	return "", types.ErrUnknownEmbed
+}
+
+// runPackage extracts text from the embedded docx/xlsx/pptx package.
+func (d *DefaultEmbed) runPackage(data []byte, maxDepth int) (string, error) {
+	var (
+		r    = bytes.NewReader(data)
+		size = int64(len(data))
+	)
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return "", err
+	}
+
+	for _, f := range zr.File {
+		switch {
+		case strings.HasPrefix(f.Name, "word/"):
+			dp, err := docxtotext.OpenReader(r, size)
+			if err != nil {
+				return "", err
+			}
+			defer dp.Close()
+			dp.SetParseEmbeddings(maxDepth > 0)
+			dp.SetEmbeddingsMaxDepth(maxDepth)
+			dp.SetEmbedInterface(d)
+			return dp.ExtractTexts()
+
+		case strings.HasPrefix(f.Name, "xl/"):
+			xp, err := xlsxtotext.OpenReader(r, size)
+			if err != nil {
+				return "", err
+			}
+			defer xp.Close()
+			xp.SetParseEmbeddings(maxDepth > 0)
+			xp.SetEmbeddingsMaxDepth(maxDepth)
+			xp.SetEmbedInterface(d)
+			return xp.ExtractTexts()
+
+		case strings.HasPrefix(f.Name, "ppt/"):
+			pp, err := pptxtotext.OpenReader(r, size)
+			if err != nil {
+				return "", err
+			}
+			defer pp.Close()
+			pp.SetParseEmbeddings(maxDepth > 0)
+			pp.SetEmbeddingsMaxDepth(maxDepth)
+			pp.SetEmbedInterface(d)
+			return pp.ExtractTexts()
+		}
+	}
+
+	return "", types.ErrUnknownEmbed
+}
+
+// runCfb extracts text from the embedded OLE object stored in compound file binary format.
+func (d *DefaultEmbed) runCfb(data []byte, name string, maxDepth int) (string, error) {
+	cr, err := newCfbReader(data)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	// docx/xlsx/pptx package embedded as OLE object
+	case cr.has("Package"):
+		pkg, err := cr.stream("Package")
+		if err != nil {
+			return "", err
+		}
+		return d.run(pkg, name, maxDepth)
+
+	// pdf embedded as OLE object by Acrobat
+	case cr.has("CONTENTS"):
+		contents, err := cr.stream("CONTENTS")
+		if err != nil {
+			return "", err
+		}
+		return d.run(contents, name, maxDepth)
+
+	// any file embedded by OLE Packager
+	case cr.has("\x01Ole10Native"):
+		native, err := cr.stream("\x01Ole10Native")
+		if err != nil {
+			return "", err
+		}
+		label, payload, err := parseOle10Native(native)
+		if err != nil {
+			return "", err
+		}
+		return d.run(payload, label, maxDepth)
+
+	case cr.has("WordDocument"):
+		return doctotext.ExtractFromReader(bytes.NewReader(data))
+
+	case cr.has("Workbook"), cr.has("Book"):
+		return xlstotext.ExtractFromReader(bytes.NewReader(data))
+
+	case cr.has("PowerPoint Document"):
+		if d.tikaServerURL == "" {
+			return "", types.ErrNoTikaServer
+		}
+		text, _, err := ppttotext.ExtractFromReaderByTika(bytes.NewReader(data), len(data), d.tikaServerURL)
+		return text, err
+	}
+
+	return "", types.ErrUnknownEmbed
+}
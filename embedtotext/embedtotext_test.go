@@ -0,0 +1,107 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package embedtotext
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/young2j/oxmltotext/docxtotext"
+)
+
+var (
+	docxPath = "../filesamples/file-sample_embeddings.docx"
+)
+
+func TestParseEmbeddings(t *testing.T) {
+	dp, err := docxtotext.Open(docxPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dp.Close()
+
+	dp.SetParseEmbeddings(true)
+	dp.SetEmbedInterface(NewDefaultEmbed())
+
+	texts, err := dp.ExtractTexts()
+	if err != nil {
+		t.Error(err)
+	}
+	if !strings.Contains(texts, "系列 1") {
+		t.Error("embedded package is not extracted")
+	}
+	if !strings.Contains(texts, "Meeting notes embedded by OLE Packager.") {
+		t.Error("embedded OLE object is not extracted")
+	}
+
+	// the depth of 0 does not parse the embedded objects
+	dp.SetEmbeddingsMaxDepth(0)
+	texts0, err := dp.ExtractTexts()
+	if err != nil {
+		t.Error(err)
+	}
+	if strings.Contains(texts0, "系列 1") || strings.Contains(texts0, "Meeting notes embedded by OLE Packager.") {
+		t.Error("embedded objects are extracted with the depth of 0")
+	}
+
+	t.Log(texts)
+}
+
+func TestCfbReader(t *testing.T) {
+	zr, err := zip.OpenReader(docxPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+
+	rc, err := zr.Open("word/embeddings/oleObject1.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cr, err := newCfbReader(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	native, err := cr.stream("\x01Ole10Native")
+	if err != nil {
+		t.Fatal(err)
+	}
+	label, payload, err := parseOle10Native(native)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if label != "notes.txt" {
+		t.Error(label)
+	}
+	t.Log(string(payload))
+}
+
+func TestCfbReaderMalformed(t *testing.T) {
+	// a header claiming 4G FAT sectors with a DIFAT sector chained to itself
+	data := make([]byte, 1024)
+	copy(data, cfbMagic)
+	le := binary.LittleEndian
+	le.PutUint16(data[0x1E:], 9)
+	le.PutUint16(data[0x20:], 6)
+	le.PutUint32(data[0x2C:], 0xFFFFFFFF)
+	le.PutUint32(data[0x44:], 0)
+	le.PutUint32(data[0x48:], 0xFFFFFFFF)
+	le.PutUint32(data[512+508:], 0)
+
+	if _, err := newCfbReader(data); err != errBadCfb {
+		t.Errorf("want errBadCfb, got %v", err)
+	}
+}
@@ -8,6 +8,7 @@ require (
 	github.com/otiai10/gosseract/v2 v2.4.1
 	github.com/valyala/fasthttp v1.51.0
 	go.uber.org/zap v1.26.0
+	golang.org/x/text v0.13.0
 )
 
 require (
@@ -0,0 +1,357 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package opc
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// canonicalPrefixes maps the Transitional and Strict(ISO/IEC 29500 Strict) namespace URIs to the
+// conventional prefixes matched by the parsers, the elements of SpreadsheetML are unprefixed.
+var canonicalPrefixes = map[string]string{
+	// Transitional
+	"http://schemas.openxmlformats.org/wordprocessingml/2006/main":           "w",
+	"http://schemas.openxmlformats.org/officeDocument/2006/relationships":    "r",
+	"http://schemas.openxmlformats.org/officeDocument/2006/math":             "m",
+	"http://schemas.openxmlformats.org/drawingml/2006/main":                  "a",
+	"http://schemas.openxmlformats.org/drawingml/2006/picture":               "pic",
+	"http://schemas.openxmlformats.org/drawingml/2006/wordprocessingDrawing": "wp",
+	"http://schemas.openxmlformats.org/drawingml/2006/spreadsheetDrawing":    "xdr",
+	"http://schemas.openxmlformats.org/drawingml/2006/chart":                 "c",
+	"http://schemas.openxmlformats.org/drawingml/2006/diagram":               "dgm",
+	"http://schemas.openxmlformats.org/presentationml/2006/main":             "p",
+	"http://schemas.openxmlformats.org/spreadsheetml/2006/main":              "",
+	"http://schemas.openxmlformats.org/markup-compatibility/2006":            "mc",
+	"urn:schemas-microsoft-com:vml":                                          "v",
+	"urn:schemas-microsoft-com:office:office":                                "o",
+	// Strict
+	"http://purl.oclc.org/ooxml/wordprocessingml/main":           "w",
+	"http://purl.oclc.org/ooxml/officeDocument/relationships":    "r",
+	"http://purl.oclc.org/ooxml/officeDocument/math":             "m",
+	"http://purl.oclc.org/ooxml/drawingml/main":                  "a",
+	"http://purl.oclc.org/ooxml/drawingml/picture":               "pic",
+	"http://purl.oclc.org/ooxml/drawingml/wordprocessingDrawing": "wp",
+	"http://purl.oclc.org/ooxml/drawingml/spreadsheetDrawing":    "xdr",
+	"http://purl.oclc.org/ooxml/drawingml/chart":                 "c",
+	"http://purl.oclc.org/ooxml/drawingml/diagram":               "dgm",
+	"http://purl.oclc.org/ooxml/presentationml/main":             "p",
+	"http://purl.oclc.org/ooxml/spreadsheetml/main":              "",
+}
+
+// CanonicalPrefix returns the conventional prefix of the Transitional or Strict namespace URI.
+//
+// Parameters:
+//   - uri: the namespace URI.
+//
+// Returns:
+//   - string: the conventional prefix, empty for SpreadsheetML.
+//   - bool: false if the namespace is not an OOXML namespace.
+func CanonicalPrefix(uri string) (string, bool) {
+	prefix, ok := canonicalPrefixes[uri]
+	return prefix, ok
+}
+
+// OpenPart opens the XML part and normalizes the prefixes of OOXML elements and attributes, see NewNamespaceReader.
+//
+// Parameters:
+//   - f: the zip file of the part.
+//
+// Returns:
+//   - io.ReadCloser: the normalized XML of the part.
+//   - error: an error if the part can not be opened.
+func OpenPart(f *zip.File) (io.ReadCloser, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+
+	return &partReader{Reader: NewNamespaceReader(rc), closer: rc}, nil
+}
+
+// partReader is the normalized reader of a part which closes the underlying zip file reader.
+type partReader struct {
+	io.Reader
+	closer io.Closer
+}
+
+func (pr *partReader) Close() error {
+	return pr.closer.Close()
+}
+
+// nsDecl is a namespace declaration in scope, the canonical prefix is used for the declared prefix.
+type nsDecl struct {
+	prefix    string
+	canonical string
+	known     bool // the namespace is an OOXML namespace
+}
+
+// nsReader rewrites the prefixes of OOXML elements and attributes to the conventional prefixes by their
+// namespace URIs, so that the parsers matching qualified names like "w:t" work with any declared prefix.
+type nsReader struct {
+	r      *bufio.Reader
+	out    []byte
+	decls  []nsDecl // the declarations in scope
+	scopes []int    // the number of declarations of each open element
+	raw    bool     // the rest is passed through
+	err    error
+}
+
+// NewNamespaceReader returns a reader of the XML whose OOXML elements and attributes use the conventional
+// prefixes, such as "w" for WordprocessingML, "a" for DrawingML and no prefix for SpreadsheetML,
+// for both Transitional and Strict namespaces.
+//
+// The XML is passed through unchanged if the declarations of the root element use the conventional
+// prefixes, which is the case of the files saved by Office in both Transitional and Strict formats.
+//
+// Parameters:
+//   - r: the reader of the XML.
+//
+// Returns:
+//   - io.Reader: the reader of the normalized XML.
+func NewNamespaceReader(r io.Reader) io.Reader {
+	return &nsReader{r: bufio.NewReaderSize(r, 32*1024)}
+}
+
+func (nr *nsReader) Read(p []byte) (int, error) {
+	for len(nr.out) == 0 {
+		if nr.err != nil {
+			return 0, nr.err
+		}
+		if nr.raw {
+			return nr.r.Read(p)
+		}
+		nr.next()
+	}
+
+	n := copy(p, nr.out)
+	nr.out = nr.out[n:]
+
+	return n, nil
+}
+
+// next reads the text up to the next markup and the markup, and appends them to the output.
+func (nr *nsReader) next() {
+	text, err := nr.r.ReadSlice('<')
+	for err == bufio.ErrBufferFull {
+		nr.out = append(nr.out, text...)
+		text, err = nr.r.ReadSlice('<')
+	}
+	nr.out = append(nr.out, text...)
+	if err != nil {
+		nr.err = err
+		return
+	}
+
+	peek, _ := nr.r.Peek(8)
+	switch {
+	case bytes.HasPrefix(peek, []byte("!--")):
+		nr.copyUntil("-->")
+	case bytes.HasPrefix(peek, []byte("![CDATA[")):
+		nr.copyUntil("]]>")
+	case bytes.HasPrefix(peek, []byte("?")):
+		nr.copyUntil("?>")
+	case bytes.HasPrefix(peek, []byte("!")):
+		nr.copyUntil(">")
+	default:
+		tag, err := nr.readTag()
+		if err != nil {
+			nr.out = append(nr.out, tag...)
+			nr.err = err
+			return
+		}
+		nr.out = nr.rewriteTag(nr.out, tag)
+	}
+}
+
+// copyUntil appends the markup up to and including the delimiter to the output.
+func (nr *nsReader) copyUntil(delim string) {
+	last := delim[len(delim)-1]
+	for {
+		chunk, err := nr.r.ReadSlice(last)
+		nr.out = append(nr.out, chunk...)
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err != nil {
+			nr.err = err
+			return
+		}
+		if bytes.HasSuffix(nr.out, []byte(delim)) {
+			return
+		}
+	}
+}
+
+// readTag reads the tag after "<" up to and including ">", the quoted attribute values may contain ">".
+func (nr *nsReader) readTag() ([]byte, error) {
+	var (
+		tag   []byte
+		quote byte
+	)
+	for {
+		c, err := nr.r.ReadByte()
+		if err != nil {
+			return tag, err
+		}
+		tag = append(tag, c)
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '>':
+			return tag, nil
+		}
+	}
+}
+
+// lookup returns the declaration of the prefix in scope.
+func (nr *nsReader) lookup(prefix string) (nsDecl, bool) {
+	for i := len(nr.decls) - 1; i >= 0; i-- {
+		if nr.decls[i].prefix == prefix {
+			return nr.decls[i], true
+		}
+	}
+	return nsDecl{}, false
+}
+
+// rename returns the qualified name with the conventional prefix.
+func (nr *nsReader) rename(name []byte, attr bool) []byte {
+	prefix, local := "", name
+	if i := bytes.IndexByte(name, ':'); i >= 0 {
+		prefix, local = string(name[:i]), name[i+1:]
+	} else if attr {
+		// unprefixed attributes have no namespace
+		return name
+	}
+	if prefix == "xmlns" || prefix == "xml" {
+		return name
+	}
+
+	decl, ok := nr.lookup(prefix)
+	if !ok || !decl.known || decl.canonical == prefix {
+		return name
+	}
+	if decl.canonical == "" {
+		return local
+	}
+
+	renamed := make([]byte, 0, len(decl.canonical)+1+len(local))
+	renamed = append(renamed, decl.canonical...)
+	renamed = append(renamed, ':')
+
+	return append(renamed, local...)
+}
+
+// rewriteTag appends the tag with the conventional prefixes to out.
+func (nr *nsReader) rewriteTag(out, tag []byte) []byte {
+	if len(tag) > 0 && tag[0] == '/' {
+		// end tag
+		name := bytes.TrimRight(tag[1:len(tag)-1], " \t\r\n")
+		out = append(out, '/')
+		out = append(out, nr.rename(name, false)...)
+		out = append(out, tag[1+len(name):]...)
+		if n := len(nr.scopes); n > 0 {
+			nr.decls = nr.decls[:len(nr.decls)-nr.scopes[n-1]]
+			nr.scopes = nr.scopes[:n-1]
+		}
+		return out
+	}
+
+	var (
+		root        = len(nr.scopes) == 0 && len(nr.decls) == 0
+		selfClosing = bytes.HasSuffix(tag, []byte("/>"))
+		attrs       = parseAttrs(tag)
+		declared    = 0
+		known       = false
+		rewrite     = false
+	)
+	for _, attr := range attrs {
+		var prefix string
+		switch {
+		case bytes.Equal(attr.name, []byte("xmlns")):
+		case bytes.HasPrefix(attr.name, []byte("xmlns:")):
+			prefix = string(attr.name[len("xmlns:"):])
+		default:
+			continue
+		}
+		canonical, ok := canonicalPrefixes[string(attr.value)]
+		nr.decls = append(nr.decls, nsDecl{prefix: prefix, canonical: canonical, known: ok})
+		declared++
+		known = known || ok
+		rewrite = rewrite || ok && canonical != prefix
+	}
+
+	nameEnd := bytes.IndexAny(tag, " \t\r\n/>")
+	out = append(out, nr.rename(tag[:nameEnd], false)...)
+	prev := nameEnd
+	for _, attr := range attrs {
+		out = append(out, tag[prev:attr.start]...)
+		out = append(out, nr.rename(attr.name, true)...)
+		prev = attr.start + len(attr.name)
+	}
+	out = append(out, tag[prev:]...)
+
+	if selfClosing {
+		nr.decls = nr.decls[:len(nr.decls)-declared]
+	} else {
+		nr.scopes = append(nr.scopes, declared)
+	}
+	if root && known && !rewrite {
+		nr.raw = true
+	}
+
+	return out
+}
+
+// tagAttr is an attribute of a tag.
+type tagAttr struct {
+	start int // the offset of the name in the tag
+	name  []byte
+	value []byte
+}
+
+// parseAttrs parses the attributes of the start tag without "<".
+func parseAttrs(tag []byte) []tagAttr {
+	var (
+		attrs = make([]tagAttr, 0, 4)
+		i     = bytes.IndexAny(tag, " \t\r\n/>")
+	)
+	for i >= 0 && i < len(tag) {
+		// skip whitespace
+		for i < len(tag) && (tag[i] == ' ' || tag[i] == '\t' || tag[i] == '\r' || tag[i] == '\n') {
+			i++
+		}
+		if i >= len(tag) || tag[i] == '/' || tag[i] == '>' {
+			break
+		}
+		start := i
+		for i < len(tag) && tag[i] != '=' && tag[i] != ' ' && tag[i] != '\t' && tag[i] != '\r' && tag[i] != '\n' {
+			i++
+		}
+		name := tag[start:i]
+		for i < len(tag) && tag[i] != '"' && tag[i] != '\'' {
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+		quote := tag[i]
+		end := bytes.IndexByte(tag[i+1:], quote)
+		if end < 0 {
+			break
+		}
+		attrs = append(attrs, tagAttr{start: start, name: name, value: tag[i+1 : i+1+end]})
+		i += end + 2
+	}
+
+	return attrs
+}
@@ -0,0 +1,373 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// Package opc reads the Open Packaging Conventions(ECMA-376 Part 2) structure of OOXML packages,
+// the content types([Content_Types].xml) and relationships(_rels/*.rels) of parts, so that the parts
+// can be discovered by relationships instead of their conventional names.
+package opc
+
+import (
+	"archive/zip"
+	"html"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	qxml "github.com/dgrr/quickxml"
+)
+
+// Relationship is a relationship from a source part(or the package) to a target part or an external resource.
+type Relationship struct {
+	ID   string
+	Type string // the relationship type URI, like "http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles"
+	// the resolved part name without leading "/", like "word/styles.xml", or the URI of an external resource
+	Target   string
+	External bool // the target is an external resource(TargetMode="External")
+}
+
+// Kind returns the last segment of the relationship type, like "styles" and "officeDocument",
+// which is the same for Transitional and Strict relationship types.
+func (rel Relationship) Kind() string {
+	return rel.Type[strings.LastIndex(rel.Type, "/")+1:]
+}
+
+// Package is an OPC package read from a zip archive.
+type Package struct {
+	files     map[string]*zip.File // keyed by the part name without leading "/"
+	folded    map[string]*zip.File // keyed by the lower case part name, part names are case-insensitive
+	defaults  map[string]string    // content types keyed by the lower case extension
+	overrides map[string]string    // content types keyed by the lower case part name
+	rels      map[string][]Relationship
+	main      string // the main part found by MainPart, also walked by Walk
+}
+
+// Open reads the content types of the package in the zip archive.
+//
+// Parameters:
+//   - r: the zip.Reader of the package.
+//
+// Returns:
+//   - *Package: the package.
+//   - error: an error if the content types part is malformed.
+func Open(r *zip.Reader) (*Package, error) {
+	p := &Package{
+		files:     make(map[string]*zip.File, len(r.File)),
+		folded:    make(map[string]*zip.File, len(r.File)),
+		defaults:  make(map[string]string, 8),
+		overrides: make(map[string]string, len(r.File)),
+		rels:      make(map[string][]Relationship, 8),
+	}
+	for _, file := range r.File {
+		name := strings.TrimPrefix(file.Name, "/")
+		p.files[name] = file
+		p.folded[strings.ToLower(name)] = file
+	}
+
+	if err := p.parseContentTypes(); err != nil {
+		return p, err
+	}
+
+	return p, nil
+}
+
+// parseContentTypes parses the default and override content types of [Content_Types].xml.
+func (p *Package) parseContentTypes() error {
+	file := p.File("[Content_Types].xml")
+	if file == nil {
+		return nil
+	}
+
+	rc, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	r := qxml.NewReader(rc)
+	for r.Next() {
+		e, ok := r.Element().(*qxml.StartElement)
+		if !ok {
+			continue
+		}
+		attrs := e.Attrs()
+		contentType := attrs.Get("ContentType")
+		if contentType == nil {
+			continue
+		}
+		switch localName(e.Name()) {
+		case "Default":
+			if ext := attrs.Get("Extension"); ext != nil {
+				p.defaults[strings.ToLower(ext.Value())] = contentType.Value()
+			}
+		case "Override":
+			if name := attrs.Get("PartName"); name != nil {
+				p.overrides[strings.ToLower(strings.TrimPrefix(name.Value(), "/"))] = contentType.Value()
+			}
+		}
+	}
+
+	return nil
+}
+
+// File returns the zip file of the part, or nil if the part does not exist.
+// The part name is matched case-insensitively, and percent-encoded names are decoded.
+//
+// Parameters:
+//   - name: the part name with or without leading "/".
+//
+// Returns:
+//   - *zip.File: the zip file of the part.
+func (p *Package) File(name string) *zip.File {
+	name = strings.TrimPrefix(name, "/")
+	if file, ok := p.files[name]; ok {
+		return file
+	}
+	if file, ok := p.folded[strings.ToLower(name)]; ok {
+		return file
+	}
+	if unescaped, err := url.PathUnescape(name); err == nil && unescaped != name {
+		return p.File(unescaped)
+	}
+
+	return nil
+}
+
+// Name returns the actual name of the part in the zip archive, or "" if the part does not exist.
+func (p *Package) Name(name string) string {
+	if file := p.File(name); file != nil {
+		return strings.TrimPrefix(file.Name, "/")
+	}
+	return ""
+}
+
+// ContentType returns the content type of the part by the override or the default of its extension.
+//
+// Parameters:
+//   - name: the part name with or without leading "/".
+//
+// Returns:
+//   - string: the content type, empty if not declared.
+func (p *Package) ContentType(name string) string {
+	name = strings.ToLower(strings.TrimPrefix(name, "/"))
+	if contentType, ok := p.overrides[name]; ok {
+		return contentType
+	}
+
+	return p.defaults[strings.TrimPrefix(path.Ext(name), ".")]
+}
+
+// Relationships returns the relationships of the source part, the targets of internal relationships are resolved.
+// The parsed relationships are cached.
+//
+// Parameters:
+//   - source: the source part name, "" for the package relationships(_rels/.rels).
+//
+// Returns:
+//   - []Relationship: the relationships in document order, nil if the part has no relationships part.
+//   - error: an error if the relationships part can not be read.
+func (p *Package) Relationships(source string) ([]Relationship, error) {
+	source = strings.TrimPrefix(source, "/")
+	if rels, ok := p.rels[source]; ok {
+		return rels, nil
+	}
+
+	file := p.File(RelsName(source))
+	if file == nil {
+		p.rels[source] = nil
+		return nil, nil
+	}
+
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var rels []Relationship
+	r := qxml.NewReader(rc)
+	for r.Next() {
+		e, ok := r.Element().(*qxml.StartElement)
+		if !ok || localName(e.Name()) != "Relationship" {
+			continue
+		}
+		attrs := e.Attrs()
+		id, typ, target := attrs.Get("Id"), attrs.Get("Type"), attrs.Get("Target")
+		if id == nil || typ == nil || target == nil {
+			continue
+		}
+		rel := Relationship{
+			ID:     id.Value(),
+			Type:   typ.Value(),
+			Target: html.UnescapeString(target.Value()),
+		}
+		if mode := attrs.Get("TargetMode"); mode != nil && mode.Value() == "External" {
+			rel.External = true
+		} else {
+			rel.Target = p.resolve(source, rel.Target)
+		}
+		rels = append(rels, rel)
+	}
+	p.rels[source] = rels
+
+	return rels, nil
+}
+
+// resolve resolves the target against the source part, and returns the actual part name if it exists.
+func (p *Package) resolve(source, target string) string {
+	name := ResolveTarget(source, target)
+	if actual := p.Name(name); actual != "" {
+		return actual
+	}
+	return name
+}
+
+// RelsMap returns the targets of the internal relationships of the source part keyed by the relationship ids.
+//
+// Parameters:
+//   - source: the source part name, "" for the package relationships.
+//
+// Returns:
+//   - map[string]string: the resolved part names keyed by the relationship ids.
+//   - error: an error if the relationships part can not be read.
+func (p *Package) RelsMap(source string) (map[string]string, error) {
+	rels, err := p.Relationships(source)
+	m := make(map[string]string, len(rels))
+	for _, rel := range rels {
+		if !rel.External {
+			m[rel.ID] = rel.Target
+		}
+	}
+
+	return m, err
+}
+
+// Related returns the internal targets of the source part with the relationship kind, see Relationship.Kind.
+//
+// Parameters:
+//   - source: the source part name, "" for the package relationships.
+//   - kind: the relationship kind, like "officeDocument", "styles" and "slide".
+//
+// Returns:
+//   - []string: the resolved part names which exist in the package, in document order.
+func (p *Package) Related(source, kind string) []string {
+	rels, _ := p.Relationships(source)
+	targets := make([]string, 0, 1)
+	for _, rel := range rels {
+		if !rel.External && rel.Kind() == kind && p.File(rel.Target) != nil {
+			targets = append(targets, rel.Target)
+		}
+	}
+
+	return targets
+}
+
+// MainPart returns the main part(officeDocument) of the package, like "word/document.xml".
+//
+// The main part is the target of the officeDocument relationship of the package. If the package relationships
+// are missing or broken, the part overridden with a main content type(like
+// "application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml") in [Content_Types].xml
+// is the main part, or the part of the conventional name if it exists.
+//
+// Parameters:
+//   - name: the conventional name of the main part, like "word/document.xml".
+//
+// Returns:
+//   - string: the main part name, empty if not found.
+func (p *Package) MainPart(name string) string {
+	if targets := p.Related("", "officeDocument"); len(targets) > 0 {
+		p.main = targets[0]
+		return p.main
+	}
+
+	var overridden []string
+	for part, contentType := range p.overrides {
+		if strings.HasSuffix(contentType, ".main+xml") && p.File(part) != nil {
+			overridden = append(overridden, p.Name(part))
+		}
+	}
+	if len(overridden) > 0 {
+		sort.Strings(overridden)
+		p.main = overridden[0]
+		return p.main
+	}
+
+	p.main = p.Name(name)
+	return p.main
+}
+
+// Walk calls fn with each internal relationship of the parts reachable from the package relationships
+// and the main part found by MainPart, the relationships of each source part are visited once.
+//
+// Parameters:
+//   - fn: the function called with the source part name and the relationship.
+//
+// Returns:
+//   - error: the first error of reading relationships parts.
+func (p *Package) Walk(fn func(source string, rel Relationship)) error {
+	var (
+		firstErr error
+		visited  = map[string]bool{"": true}
+		queue    = []string{""}
+	)
+	if p.main != "" {
+		// the main part may be not related by the package relationships
+		visited[p.main] = true
+		queue = append(queue, p.main)
+	}
+	for len(queue) > 0 {
+		source := queue[0]
+		queue = queue[1:]
+		rels, err := p.Relationships(source)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		for _, rel := range rels {
+			if rel.External || p.File(rel.Target) == nil {
+				continue
+			}
+			fn(source, rel)
+			if !visited[rel.Target] {
+				visited[rel.Target] = true
+				queue = append(queue, rel.Target)
+			}
+		}
+	}
+
+	return firstErr
+}
+
+// RelsName returns the relationships part name of the source part,
+// like "word/_rels/document.xml.rels" for "word/document.xml", and "_rels/.rels" for the package.
+func RelsName(source string) string {
+	source = strings.TrimPrefix(source, "/")
+	dir, name := path.Split(source)
+	return dir + "_rels/" + name + ".rels"
+}
+
+// ResolveTarget resolves the relationship target against the source part.
+//
+// Parameters:
+//   - source: the source part name, "" for the package relationships.
+//   - target: the target URI, relative to the folder of source part, or absolute starting with "/".
+//
+// Returns:
+//   - string: the part name without leading "/", like "word/media/image1.png".
+func ResolveTarget(source, target string) string {
+	if i := strings.IndexAny(target, "#?"); i >= 0 {
+		target = target[:i]
+	}
+	target = strings.ReplaceAll(target, "\\", "/")
+	if !strings.HasPrefix(target, "/") {
+		target = path.Join("/", path.Dir("/"+strings.TrimPrefix(source, "/")), target)
+	}
+
+	return strings.TrimPrefix(path.Clean(target), "/")
+}
+
+// localName returns the local name of a qualified name.
+func localName(name string) string {
+	return name[strings.IndexByte(name, ':')+1:]
+}
@@ -0,0 +1,198 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package opc
+
+import (
+	"archive/zip"
+	"io"
+	"strings"
+	"testing"
+)
+
+var renamedDocmPath = "../filesamples/file-sample_renamed.docm"
+
+func TestResolveTarget(t *testing.T) {
+	for _, c := range []struct {
+		source, target, want string
+	}{
+		{"", "word/document.xml", "word/document.xml"},
+		{"", "/word/document.xml", "word/document.xml"},
+		{"word/document.xml", "media/image1.png", "word/media/image1.png"},
+		{"word/document.xml", "../customXml/item1.xml", "customXml/item1.xml"},
+		{"ppt/slides/slide1.xml", "../media/image1.png", "ppt/media/image1.png"},
+		{"xl/workbook.xml", "/xl/worksheets/sheet1.xml", "xl/worksheets/sheet1.xml"},
+		{"word/document.xml", "./styles.xml#frag", "word/styles.xml"},
+	} {
+		got := ResolveTarget(c.source, c.target)
+		t.Logf("%q + %q => %q", c.source, c.target, got)
+		if got != c.want {
+			t.Errorf("want: %q", c.want)
+		}
+	}
+
+	if name := RelsName("word/document.xml"); name != "word/_rels/document.xml.rels" {
+		t.Errorf("unexpected rels name: %q", name)
+	}
+	if name := RelsName(""); name != "_rels/.rels" {
+		t.Errorf("unexpected package rels name: %q", name)
+	}
+}
+
+func TestPackage(t *testing.T) {
+	zr, err := zip.OpenReader(renamedDocmPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+
+	pkg, err := Open(&zr.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	main := pkg.MainPart("word/document.xml")
+	t.Logf("main part: %q content type: %q", main, pkg.ContentType(main))
+	if main != "word/main-document.xml" || pkg.File(main) == nil {
+		t.Error("unexpected main part")
+	}
+	if pkg.ContentType(main) != "application/vnd.ms-word.document.macroEnabled.main+xml" {
+		t.Error("unexpected override content type")
+	}
+	if pkg.ContentType("/word/media/image1.PNG") != pkg.ContentType("word/media/image1.png") {
+		t.Error("extensions should be matched case-insensitively")
+	}
+	if pkg.File("/WORD/Main-Document.xml") == nil {
+		t.Error("part names should be matched case-insensitively")
+	}
+
+	styles := pkg.Related(main, "styles")
+	t.Logf("styles: %v", styles)
+	if len(styles) != 1 || styles[0] != "word/styles.xml" {
+		t.Error("unexpected styles part")
+	}
+
+	kinds := make(map[string]int)
+	err = pkg.Walk(func(source string, rel Relationship) {
+		kinds[rel.Kind()]++
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	t.Logf("relationship kinds: %v", kinds)
+	if kinds["officeDocument"] != 1 || kinds["image"] == 0 {
+		t.Error("unexpected relationships")
+	}
+}
+
+func TestMainPartFallback(t *testing.T) {
+	// openPackage opens the package of the parts without the package relationships
+	openPackage := func(parts map[string]string) *Package {
+		b := new(strings.Builder)
+		zw := zip.NewWriter(b)
+		for name, content := range parts {
+			w, err := zw.Create(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			io.WriteString(w, content)
+		}
+		if err := zw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		zr, err := zip.NewReader(strings.NewReader(b.String()), int64(b.Len()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		pkg, err := Open(zr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pkg
+	}
+
+	contentTypes := `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Override PartName="/xl/book.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/></Types>`
+	pkg := openPackage(map[string]string{"[Content_Types].xml": contentTypes, "xl/book.xml": "<workbook/>", "xl/workbook.xml": "<workbook/>"})
+	if main := pkg.MainPart("xl/workbook.xml"); main != "xl/book.xml" {
+		t.Errorf("unexpected main part by content type: %q", main)
+	}
+
+	pkg = openPackage(map[string]string{"xl/workbook.xml": "<workbook/>", "xl/_rels/workbook.xml.rels": `<Relationships><Relationship Id="rId1" ` +
+		`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/></Relationships>`,
+		"xl/styles.xml": "<styleSheet/>"})
+	if main := pkg.MainPart("xl/workbook.xml"); main != "xl/workbook.xml" {
+		t.Errorf("unexpected main part by conventional name: %q", main)
+	}
+	// the main part is walked without the package relationships
+	var walked []string
+	pkg.Walk(func(source string, rel Relationship) {
+		walked = append(walked, source+" -> "+rel.Target)
+	})
+	t.Log(walked)
+	if len(walked) != 1 || walked[0] != "xl/workbook.xml -> xl/styles.xml" {
+		t.Error("unexpected walked relationships")
+	}
+
+	pkg = openPackage(map[string]string{"xl/other.xml": "<workbook/>"})
+	if main := pkg.MainPart("xl/workbook.xml"); main != "" {
+		t.Errorf("unexpected main part: %q", main)
+	}
+}
+
+func TestNamespaceReader(t *testing.T) {
+	for _, c := range []struct {
+		name, xml, want string
+	}{
+		{
+			"transitional",
+			`<?xml version="1.0"?><w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:t>a&gt;b</w:t></w:document>`,
+			`<?xml version="1.0"?><w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:t>a&gt;b</w:t></w:document>`,
+		},
+		{
+			"strict",
+			`<w:document xmlns:w="http://purl.oclc.org/ooxml/wordprocessingml/main"><w:p><w:r><w:t>strict</w:t></w:r></w:p></w:document>`,
+			`<w:document xmlns:w="http://purl.oclc.org/ooxml/wordprocessingml/main"><w:p><w:r><w:t>strict</w:t></w:r></w:p></w:document>`,
+		},
+		{
+			"prefix",
+			`<ns0:document xmlns:ns0="http://purl.oclc.org/ooxml/wordprocessingml/main" xmlns:ns1="http://purl.oclc.org/ooxml/officeDocument/relationships"><ns0:p><ns0:hyperlink ns1:id="rId1" ns0:history="1"><!-- <ns0:t> --><ns0:t xml:space="preserve">a>b </ns0:t></ns0:hyperlink></ns0:p><ns0:p/></ns0:document>`,
+			`<w:document xmlns:ns0="http://purl.oclc.org/ooxml/wordprocessingml/main" xmlns:ns1="http://purl.oclc.org/ooxml/officeDocument/relationships"><w:p><w:hyperlink r:id="rId1" w:history="1"><!-- <ns0:t> --><w:t xml:space="preserve">a>b </w:t></w:hyperlink></w:p><w:p/></w:document>`,
+		},
+		{
+			"default",
+			`<document xmlns="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><body><p><r><t val="x">default</t></r></p></body></document>`,
+			`<w:document xmlns="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body><w:p><w:r><w:t val="x">default</w:t></w:r></w:p></w:body></w:document>`,
+		},
+		{
+			"spreadsheetml",
+			`<x:sst xmlns:x="http://purl.oclc.org/ooxml/spreadsheetml/main"><x:si><x:t>cell</x:t></x:si></x:sst>`,
+			`<sst xmlns:x="http://purl.oclc.org/ooxml/spreadsheetml/main"><si><t>cell</t></si></sst>`,
+		},
+		{
+			"nested",
+			`<root><a:p xmlns:a="http://purl.oclc.org/ooxml/drawingml/main"><a:t>in</a:t></a:p><a:t>out</a:t></root>`,
+			`<root><a:p xmlns:a="http://purl.oclc.org/ooxml/drawingml/main"><a:t>in</a:t></a:p><a:t>out</a:t></root>`,
+		},
+		{
+			"scope",
+			`<root><d:p xmlns:d="http://purl.oclc.org/ooxml/drawingml/main"><d:t>in</d:t></d:p><d:t>out</d:t></root>`,
+			`<root><a:p xmlns:d="http://purl.oclc.org/ooxml/drawingml/main"><a:t>in</a:t></a:p><d:t>out</d:t></root>`,
+		},
+	} {
+		data, err := io.ReadAll(NewNamespaceReader(strings.NewReader(c.xml)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Logf("%s: %s", c.name, data)
+		if string(data) != c.want {
+			t.Errorf("want: %s", c.want)
+		}
+	}
+
+	if prefix, ok := CanonicalPrefix("http://purl.oclc.org/ooxml/presentationml/main"); !ok || prefix != "p" {
+		t.Errorf("unexpected canonical prefix: %q", prefix)
+	}
+}
@@ -10,19 +10,16 @@ import (
 	"bytes"
 	"io"
 	"regexp"
-	"strconv"
 
+	"github.com/young2j/oxmltotext/opc"
+	"github.com/young2j/oxmltotext/types"
 	"github.com/young2j/oxmltotext/utils"
-)
 
-var (
-	re_SLIDE      = regexp.MustCompile(`ppt/slides/slide(\d+)\.xml`)
-	re_SLIDE_RELS = regexp.MustCompile(`ppt/slides/_rels/slide(\d+)\.xml\.rels`)
-	re_CHARTS     = regexp.MustCompile(`ppt/charts/chart\d+\.xml`)
-	re_IMAGES     = regexp.MustCompile(`ppt/media/image\d+\.(?:png|gif|jpg|jpeg)`)
-	re_DIAGRAMS   = regexp.MustCompile(`ppt/diagrams/data\d+\.xml`)
+	qxml "github.com/dgrr/quickxml"
 )
 
+var re_IMAGES = regexp.MustCompile(`\.(?i:png|gif|jpg|jpeg)$`)
+
 // Open opens the specified pptx file path and returns a new PptxParser instance and an error, if any.
 //
 // Parameters:
@@ -93,8 +90,13 @@ func OpenURL(u string) (*PptxParser, int, error) {
 	return pp, statusCode, err
 }
 
-// matchZipFile is a function that matches the files in a zip.Reader to specific categories such as slides, charts,
-// images, and diagrams. It populates the relevant maps in the PptxParser struct with the matched files.
+// matchZipFile matches the files in a zip.Reader to specific categories such as slides, charts,
+// images, diagrams and embeddings. It populates the relevant maps in the PptxParser struct with the matched files.
+//
+// The parts are discovered by the relationships of the package: the presentation part is the target of
+// officeDocument relationship(so .pptm/.potx/.ppsx and renamed parts are supported), the slides are numbered
+// by the slide list(p:sldIdLst) of the presentation, and the charts, images, diagrams and embeddings are the
+// targets of the relationships of any part reachable from the package.
 //
 // Parameters:
 //   - pp: a pointer to the PptxParser struct that holds the maps for slideFiles, chartsFiles, imagesFiles,
@@ -104,49 +106,101 @@ func OpenURL(u string) (*PptxParser, int, error) {
 // Return:
 //   - error: an error if there was a problem parsing the files or populating the maps, otherwise nil.
 func matchZipFile(pp *PptxParser, r *zip.Reader) error {
-	slidesNum := max((len(r.File)-16)/3, 1)
-	pp.slideFiles = make(map[int]*zip.File, slidesNum)
+	pkg, err := opc.Open(r)
+	if err != nil {
+		return err
+	}
+
+	pp.pkg = pkg
 	pp.chartsFiles = make(map[string]*zip.File, 4)
 	pp.imagesFiles = make(map[string]*zip.File, 4)
 	pp.diagramsFiles = make(map[string]*zip.File, 4)
-	pp.slideRelsMap = make(map[int]map[string]string, slidesNum)
-
-	for _, file := range r.File {
-		switch {
-		case re_CHARTS.MatchString(file.Name):
-			pp.chartsFiles[file.Name] = file
-		case re_IMAGES.MatchString(file.Name):
-			pp.imagesFiles[file.Name] = file
-		case re_DIAGRAMS.MatchString(file.Name):
-			pp.diagramsFiles[file.Name] = file
-		default:
-			matches := re_SLIDE.FindStringSubmatch(file.Name)
-			if len(matches) > 1 {
-				i, err := strconv.Atoi(matches[1])
-				if err != nil {
-					pp.logWarn(err)
-					continue
-				}
-				pp.slideFiles[i] = file
-				continue
+	pp.embedsFiles = make(map[string]*zip.File, 4)
+
+	main := pkg.MainPart("ppt/presentation.xml")
+	if main == "" {
+		return types.ErrNonePart
+	}
+	pp.presentationFile = pkg.File(main)
+	slides, err := parseSlideList(pkg, main)
+	if err != nil {
+		return err
+	}
+	pp.slideFiles = make(map[int]*zip.File, len(slides))
+	pp.slideRelsMap = make(map[int]map[string]string, len(slides))
+	for i, slide := range slides {
+		pp.slideFiles[i+1] = pkg.File(slide)
+		if pp.slideRelsMap[i+1], err = pkg.RelsMap(slide); err != nil {
+			return err
+		}
+	}
+
+	return pkg.Walk(func(source string, rel opc.Relationship) {
+		file := pkg.File(rel.Target)
+		switch rel.Kind() {
+		case "chart":
+			pp.chartsFiles[rel.Target] = file
+		case "image":
+			if re_IMAGES.MatchString(rel.Target) {
+				pp.imagesFiles[rel.Target] = file
 			}
+		case "diagramData":
+			pp.diagramsFiles[rel.Target] = file
+		case "oleObject", "package":
+			pp.embedsFiles[rel.Target] = file
+		}
+	})
+}
 
-			matches = re_SLIDE_RELS.FindStringSubmatch(file.Name)
-			if len(matches) > 1 {
-				i, err := strconv.Atoi(matches[1])
-				if err != nil {
-					pp.logWarn(err)
-					continue
-				}
-				relsMap, err := utils.ParseRelsMap(file, "ppt/")
-				if err != nil {
-					return err
-				}
-				pp.slideRelsMap[i] = relsMap
+// parseSlideList parses the slide parts in the order of the slide list(p:sldIdLst) of the presentation.
+//
+// Parameters:
+//   - pkg: the package of the pptx file.
+//   - main: the presentation part name.
+//
+// Returns:
+//   - []string: the slide part names, the slides not in the list are appended in the order of relationships.
+//   - error: an error if the presentation part can not be read.
+func parseSlideList(pkg *opc.Package, main string) ([]string, error) {
+	var (
+		slides = make([]string, 0, 8)
+		listed = make(map[string]bool, 8)
+	)
+	relsMap, err := pkg.RelsMap(main)
+	if err != nil {
+		return nil, err
+	}
+
+	if file := pkg.File(main); file != nil {
+		rc, err := opc.OpenPart(file)
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+
+		r := qxml.NewReader(rc)
+		for r.Next() {
+			e, ok := r.Element().(*qxml.StartElement)
+			if !ok || e.Name() != "p:sldId" {
+				continue
+			}
+			rId := e.Attrs().Get("r:id")
+			if rId == nil {
 				continue
 			}
+			if slide, ok := relsMap[rId.Value()]; ok && pkg.File(slide) != nil && !listed[slide] {
+				slides = append(slides, slide)
+				listed[slide] = true
+			}
+		}
+	}
+
+	for _, slide := range pkg.Related(main, "slide") {
+		if !listed[slide] {
+			slides = append(slides, slide)
+			listed[slide] = true
 		}
 	}
 
-	return nil
+	return slides, nil
 }
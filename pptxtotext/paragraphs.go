@@ -0,0 +1,221 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package pptxtotext
+
+import (
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/young2j/oxmltotext/opc"
+	"github.com/young2j/oxmltotext/types"
+	"github.com/young2j/oxmltotext/utils"
+
+	qxml "github.com/dgrr/quickxml"
+)
+
+// ExtractParagraphs extracts the paragraphs of all slides with the spans of their text.
+//
+// Each span has the language(a:rPr lang), the direction(a:rtl) and the formatting of the runs,
+// and each paragraph has its direction(a:pPr rtl). The text is in logical order. The run properties
+// are inherited from the presentation defaults, the text styles of slide master and the list style of the shape,
+// the placeholders of slide layouts are not resolved.
+// The hidden slides and shapes are excluded in types.HiddenExclude mode.
+//
+// Parameters:
+//   - None
+//
+// Returns:
+//   - []types.Paragraph: the non-empty paragraphs, Page is the slide number.
+//   - error: an error if any.
+func (pp *PptxParser) ExtractParagraphs() ([]types.Paragraph, error) {
+	paragraphs := make([]types.Paragraph, 0, 16)
+	for i := 1; i <= pp.NumSlides(); i++ {
+		slide, err := pp.parseSlideParagraphs(i)
+		if err != nil {
+			return paragraphs, err
+		}
+		paragraphs = append(paragraphs, slide...)
+	}
+
+	return paragraphs, nil
+}
+
+// parseSlideParagraphs parses the paragraphs of the slide at the given index.
+//
+// Parameters:
+//   - i: the index of the slide to parse.
+//
+// Returns:
+//   - []types.Paragraph: the non-empty paragraphs of the slide.
+//   - error: an error if the slide does not exist or if there was an error opening the slide file.
+func (pp *PptxParser) parseSlideParagraphs(i int) ([]types.Paragraph, error) {
+	slideFile, ok := pp.slideFiles[i]
+	if !ok {
+		return nil, types.ErrNoSlide
+	}
+
+	rc, err := opc.OpenPart(slideFile)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var (
+		paragraphs = make([]types.Paragraph, 0, 8)
+		para       *types.Paragraph
+		ts         = pp.loadTextStyles()
+		ms         = pp.masterStyles(i)
+		ss         = newShapeStyle(ts, ms, "-")
+		lvl        = 0       // the level of current paragraph
+		pPr        textProps // the default run properties of current paragraph
+		rPr        textProps // the run properties of current run
+		rtl        = false   // current run is right-to-left
+		shapes     = make([]string, 0, 4)
+		hidden     = 0 // depth of the hidden shape, -1 for hidden slide
+		a_t        = ""
+		alt        utils.AltContent // the handled states of the alternate contents
+	)
+	r := qxml.NewReader(rc)
+
+NEXT:
+	for r.Next() {
+		switch e := r.Element().(type) {
+		case *qxml.StartElement:
+			switch e.Name() {
+			case "p:sld":
+				show := e.Attrs().Get("show")
+				if show == nil || show.Value() != "0" || pp.hiddenMode == types.HiddenInclude {
+					continue
+				}
+				if pp.hiddenMode == types.HiddenExclude {
+					return paragraphs, nil
+				}
+				hidden = -1
+
+			case "mc:AlternateContent":
+				alt.Start(e)
+
+			// the fallback content duplicates the handled choice content.
+			case "mc:Fallback":
+				alt.SkipFallback(r, e)
+
+			case "p:sp", "p:pic", "p:graphicFrame", "p:grpSp", "p:cxnSp":
+				if !e.HasEnd() {
+					shapes = append(shapes, e.Name())
+				}
+				ss = newShapeStyle(ts, ms, "-")
+
+			case "p:ph":
+				ph := "obj"
+				if attr := e.Attrs().Get("type"); attr != nil {
+					ph = attr.Value()
+				}
+				ss = newShapeStyle(ts, ms, ph)
+
+			case "a:lstStyle":
+				ss.shape = parseListStyle(r, e)
+
+			case "p:cNvPr":
+				attr := e.Attrs().Get("hidden")
+				if attr == nil || (attr.Value() != "1" && attr.Value() != "true") ||
+					len(shapes) == 0 || pp.hiddenMode == types.HiddenInclude {
+					continue
+				}
+				if pp.hiddenMode == types.HiddenExclude {
+					utils.SkipToEnd(r, shapes[len(shapes)-1])
+					shapes = shapes[:len(shapes)-1]
+				} else if hidden == 0 {
+					hidden = len(shapes)
+				}
+
+			case "a:p":
+				if !e.HasEnd() {
+					para = &types.Paragraph{Page: i}
+				}
+				lvl, pPr = 0, textProps{}
+
+			case "a:pPr":
+				attrs := e.Attrs()
+				if attr := attrs.Get("rtl"); attr != nil && para != nil {
+					para.RTL = attr.Value() == "1" || attr.Value() == "true"
+				}
+				if attr := attrs.Get("lvl"); attr != nil {
+					lvl, _ = strconv.Atoi(attr.Value())
+				}
+
+			case "a:defRPr":
+				pPr = parseTextProps(e)
+
+			case "a:r", "a:fld":
+				rPr, rtl = textProps{}, false
+
+			case "a:rPr":
+				rPr = parseTextProps(e)
+
+			case "a:rtl":
+				val := e.Attrs().Get("val")
+				rtl = val == nil || val.Value() == "1" || val.Value() == "true"
+
+			case "a:br":
+				if para != nil && len(para.Spans) > 0 {
+					span := para.Spans[len(para.Spans)-1]
+					span.Text = "\n"
+					para.Spans = utils.AppendSpan(para.Spans, span)
+				}
+				utils.SkipElement(r, e)
+
+			case "a:endParaRPr":
+				utils.SkipElement(r, e)
+
+			case "a:t":
+				r.AssignNext(&a_t)
+				if !r.Next() {
+					break NEXT
+				}
+				if para != nil {
+					span := ss.resolve(lvl, pPr, rPr).span()
+					span.Text, span.RTL, span.Hidden = html.UnescapeString(a_t), rtl, hidden != 0
+					para.Spans = utils.AppendSpan(para.Spans, span)
+				}
+				a_t = ""
+
+			case "m:oMathPara", "m:oMath":
+				math := utils.ReadXMLElement(r, e)
+				if para != nil {
+					span := types.Span{Text: utils.OMMLToText(math, pp.mathMode), Hidden: hidden != 0}
+					para.Spans = utils.AppendSpan(para.Spans, span)
+				}
+				alt.Handled()
+			}
+
+		case *qxml.EndElement:
+			switch e.Name() {
+			case "mc:AlternateContent":
+				alt.End()
+			case "a:p":
+				if para != nil && len(para.Spans) > 0 {
+					text := new(strings.Builder)
+					for _, span := range para.Spans {
+						text.WriteString(span.Text)
+					}
+					para.Text = text.String()
+					paragraphs = append(paragraphs, *para)
+				}
+				para = nil
+			case "p:sp", "p:pic", "p:graphicFrame", "p:grpSp", "p:cxnSp":
+				if hidden > 0 && hidden == len(shapes) {
+					hidden = 0
+				}
+				if len(shapes) > 0 {
+					shapes = shapes[:len(shapes)-1]
+				}
+			}
+		}
+	}
+
+	return paragraphs, nil
+}
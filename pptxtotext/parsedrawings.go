@@ -10,6 +10,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/young2j/oxmltotext/opc"
 	"github.com/young2j/oxmltotext/types"
 	"github.com/young2j/oxmltotext/utils"
 
@@ -45,7 +46,7 @@ func (pp *PptxParser) extractChart(i int, rId string) (*strings.Builder, error)
 		return nil, types.ErrNonePart
 	}
 
-	rc, err := f.Open()
+	rc, err := opc.OpenPart(f)
 	if err != nil {
 		return nil, err
 	}
@@ -211,7 +212,7 @@ func (pp *PptxParser) extractDiagram(i int, rId string) (*strings.Builder, error
 		return nil, types.ErrNonePart
 	}
 
-	rc, err := f.Open()
+	rc, err := opc.OpenPart(f)
 	if err != nil {
 		return nil, err
 	}
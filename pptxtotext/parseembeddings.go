@@ -0,0 +1,70 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package pptxtotext
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/young2j/oxmltotext/types"
+	"github.com/young2j/oxmltotext/utils"
+)
+
+// extractEmbedding extracts text content from the embedded object by the embed interface.
+//
+// Parameters:
+//   - i: the index of the slide.
+//   - rId: the reference id of the embedded object.
+//
+// Returns:
+//   - *strings.Builder: the formatted text of the embedded object.
+//   - error: any error that occurred during the extraction process.
+func (pp *PptxParser) extractEmbedding(i int, rId string) (*strings.Builder, error) {
+	if pp.embedsMaxDepth <= 0 {
+		return nil, nil
+	}
+
+	if rId == "" {
+		return nil, types.ErrEmptyRID
+	}
+
+	if pp.embed == nil {
+		return nil, types.ErrNilEmbed
+	}
+
+	slideRels, ok := pp.slideRelsMap[i]
+	if !ok {
+		return nil, types.ErrNonePart
+	}
+
+	fname, ok := slideRels[rId]
+	if !ok {
+		return nil, types.ErrNonePart
+	}
+
+	f, ok := pp.embedsFiles[fname]
+	if !ok {
+		return nil, types.ErrNonePart
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := pp.embed.Run(bytes.NewReader(data), int64(len(data)), fname, pp.embedsMaxDepth-1)
+	if err != nil {
+		return nil, err
+	}
+	return utils.FormatBox(text, "embedding", pp.drawingsNoFmt), nil
+}
@@ -9,6 +9,7 @@ import (
 	"archive/zip"
 	"strings"
 
+	"github.com/young2j/oxmltotext/opc"
 	"github.com/young2j/oxmltotext/types"
 
 	"go.uber.org/zap"
@@ -16,18 +17,30 @@ import (
 
 // PptxParser represents the XML file structure and settings for parsing a pptx file.
 type PptxParser struct {
-	zipReadCloser *zip.ReadCloser
-	slideFiles    map[int]*zip.File
-	chartsFiles   map[string]*zip.File
-	imagesFiles   map[string]*zip.File
-	diagramsFiles map[string]*zip.File
-	slideRelsMap  map[int]map[string]string
-
-	parseCharts   bool
-	parseImages   bool
-	parseDiagrams bool
-	drawingsNoFmt bool
-	ocr           types.OCR
+	zipReadCloser    *zip.ReadCloser
+	slideFiles       map[int]*zip.File
+	chartsFiles      map[string]*zip.File
+	imagesFiles      map[string]*zip.File
+	diagramsFiles    map[string]*zip.File
+	embedsFiles      map[string]*zip.File
+	slideRelsMap     map[int]map[string]string
+	pkg              *opc.Package
+	presentationFile *zip.File
+	textStyles       *textStyles
+
+	parseCharts    bool
+	parseImages    bool
+	parseAltText   bool
+	preferAltText  bool
+	parseDiagrams  bool
+	parseEmbeds    bool
+	drawingsNoFmt  bool
+	embedsMaxDepth int
+	hiddenMode     types.HiddenMode
+	mathMode       types.MathMode
+	bidiMarks      bool
+	ocr            types.OCR
+	embed          types.Embed
 
 	slideSep     string
 	paragraphSep string
@@ -49,6 +62,7 @@ func newPptxParser() *PptxParser {
 		tableRowSep:  "\n",
 		tableColSep:  "\t",
 
-		logger: logger,
+		embedsMaxDepth: 1,
+		logger:         logger,
 	}
 }
@@ -10,7 +10,9 @@ import (
 	"strings"
 
 	"github.com/young2j/oxmltotext/ocr"
+	"github.com/young2j/oxmltotext/opc"
 	"github.com/young2j/oxmltotext/types"
+	"github.com/young2j/oxmltotext/utils"
 
 	qxml "github.com/dgrr/quickxml"
 )
@@ -55,6 +57,51 @@ func (pp *PptxParser) SetParseImages(v bool) {
 	}
 }
 
+// SetParseAltText writes the alt text(title and description) of images alongside them or not. Default is false.
+func (pp *PptxParser) SetParseAltText(v bool) {
+	pp.parseAltText = v
+}
+
+// SetPreferAltText uses the alt text of images instead of ocr when present or not. Default is false.
+// It takes effect only when images are parsed, see SetParseImages.
+func (pp *PptxParser) SetPreferAltText(v bool) {
+	pp.preferAltText = v
+}
+
+// SetParseEmbeddings parses embedded objects(OLE objects and packages) or not. Default is false.
+// The embed interface must be set by SetEmbedInterface, see package embedtotext.
+func (pp *PptxParser) SetParseEmbeddings(v bool) {
+	pp.parseEmbeds = v
+}
+
+// SetEmbeddingsMaxDepth sets the max depth of recursively parsing embedded objects. Default is 1.
+// The depth of 0 or less does not parse the embedded objects.
+func (pp *PptxParser) SetEmbeddingsMaxDepth(depth int) {
+	pp.embedsMaxDepth = depth
+}
+
+// SetEmbedInterface sets the embed interface used to extract text from embedded objects.
+func (pp *PptxParser) SetEmbedInterface(embed types.Embed) {
+	pp.embed = embed
+}
+
+// SetHiddenMode sets the mode of handling hidden slides(show="0") and hidden shapes(hidden="1").
+// Default is types.HiddenInclude.
+func (pp *PptxParser) SetHiddenMode(mode types.HiddenMode) {
+	pp.hiddenMode = mode
+}
+
+// SetMathMode sets the mode of converting Office Math(OMML) equations to text. Default is types.MathLaTeX.
+func (pp *PptxParser) SetMathMode(mode types.MathMode) {
+	pp.mathMode = mode
+}
+
+// SetBidiMarks sets whether to write a right-to-left mark(U+200F) at the start of right-to-left paragraphs(a:pPr rtl),
+// so that the logically ordered text is displayed correctly by bidi-aware renderers. Default is false.
+func (pp *PptxParser) SetBidiMarks(v bool) {
+	pp.bidiMarks = v
+}
+
 // SetDrawingsNoFmt sets drawings text no outline format.
 func (pp *PptxParser) SetDrawingsNoFmt(v bool) {
 	pp.drawingsNoFmt = v
@@ -94,7 +141,7 @@ func (pp *PptxParser) Close() (err error) {
 	return nil
 }
 
-// ExtractImages extracts images from the pptx file.
+// ExtractImages extracts images from the pptx file, along with the alt text of the drawings referencing them.
 //
 // Parameters:
 //   - None
@@ -103,6 +150,9 @@ func (pp *PptxParser) Close() (err error) {
 //   - []types.Image: a slice of images extracted from the pptx file.
 //   - error: an error if any occurred during the extraction process.
 func (pp *PptxParser) ExtractImages() ([]types.Image, error) {
+	infos, err := pp.parseImageInfos()
+	pp.logWarn(err)
+
 	images := make([]types.Image, 0, len(pp.imagesFiles))
 	for name, f := range pp.imagesFiles {
 		r, err := f.Open()
@@ -116,11 +166,15 @@ func (pp *PptxParser) ExtractImages() ([]types.Image, error) {
 		}
 		r.Close()
 
-		images = append(images, types.Image{
+		info := types.Image{
 			Raw:    img,
 			Name:   name,
 			Format: format,
-		})
+		}
+		if alt, ok := infos[name]; ok {
+			info.AltText, info.Title, info.Caption = alt.AltText, alt.Title, alt.Caption
+		}
+		images = append(images, info)
 	}
 
 	return images, nil
@@ -195,15 +249,21 @@ func (pp *PptxParser) parseSlide(i int) (*strings.Builder, error) {
 		return nil, types.ErrNoSlide
 	}
 
-	rc, err := slideFile.Open()
+	rc, err := opc.OpenPart(slideFile)
 	if err != nil {
 		return nil, err
 	}
 	defer rc.Close()
 
 	var (
-		texts  = new(strings.Builder)
-		phrase = ""
+		texts    = new(strings.Builder)
+		phrase   = ""
+		embedded = make(map[string]bool)
+		shapes   = make([]string, 0, 4)
+		marked   = 0 // depth of the marked hidden shape
+		altText  = ""
+		rtl      = false          // current paragraph is right-to-left
+		alt      utils.AltContent // the handled states of the alternate contents
 	)
 	r := qxml.NewReader(rc)
 
@@ -211,23 +271,101 @@ NEXT:
 	for r.Next() {
 		switch e := r.Element().(type) {
 		case *qxml.EndElement:
-			if e.Name() == "a:p" {
+			switch e.Name() {
+			case "a:p":
 				texts.WriteString(pp.paragraphSep)
+				rtl = false
+			case "p:sp", "p:pic", "p:graphicFrame", "p:grpSp", "p:cxnSp":
+				if marked > 0 && marked == len(shapes) {
+					texts.WriteString(types.HiddenMarkEnd)
+					texts.WriteString(pp.paragraphSep)
+					marked = 0
+				}
+				if len(shapes) > 0 {
+					shapes = shapes[:len(shapes)-1]
+				}
+			case "mc:AlternateContent":
+				alt.End()
+			case "p:sld":
+				if marked < 0 {
+					texts.WriteString(types.HiddenMarkEnd)
+					texts.WriteString(pp.paragraphSep)
+				}
 			}
 
 		case *qxml.StartElement:
 			switch e.Name() {
+			case "p:sld":
+				show := e.Attrs().Get("show")
+				if show == nil || show.Value() != "0" {
+					continue
+				}
+				switch pp.hiddenMode {
+				case types.HiddenExclude:
+					return texts, nil
+				case types.HiddenMark:
+					texts.WriteString(types.HiddenMarkStart)
+					texts.WriteString(pp.paragraphSep)
+					marked = -1
+				}
+
+			case "mc:AlternateContent":
+				alt.Start(e)
+
+			// the fallback content duplicates the handled choice content.
+			case "mc:Fallback":
+				alt.SkipFallback(r, e)
+
+			case "p:sp", "p:pic", "p:graphicFrame", "p:grpSp", "p:cxnSp":
+				if !e.HasEnd() {
+					shapes = append(shapes, e.Name())
+				}
+
+			case "p:cNvPr":
+				altText = utils.JoinAltText(utils.DrawingAltText(e))
+				hidden := e.Attrs().Get("hidden")
+				if hidden == nil || (hidden.Value() != "1" && hidden.Value() != "true") || len(shapes) == 0 {
+					continue
+				}
+				switch pp.hiddenMode {
+				case types.HiddenExclude:
+					utils.SkipToEnd(r, shapes[len(shapes)-1])
+					shapes = shapes[:len(shapes)-1]
+				case types.HiddenMark:
+					if marked == 0 {
+						texts.WriteString(types.HiddenMarkStart)
+						texts.WriteString(pp.paragraphSep)
+						marked = len(shapes)
+					}
+				}
+
+			case "a:pPr":
+				if v := e.Attrs().Get("rtl"); v != nil && pp.bidiMarks {
+					rtl = v.Value() == "1" || v.Value() == "true"
+				}
+
 			case "a:t":
 				r.AssignNext(&phrase)
 				if !r.Next() {
 					break NEXT
 				}
 				if len(phrase) > 0 {
+					if rtl {
+						// the right-to-left mark is written once before the first phrase
+						texts.WriteString("\u200f")
+						rtl = false
+					}
 					texts.WriteString(phrase)
 					texts.WriteString(pp.phraseSep)
 					phrase = ""
 				}
 
+			case "m:oMathPara", "m:oMath":
+				math := utils.ReadXMLElement(r, e)
+				texts.WriteString(utils.OMMLToText(math, pp.mathMode))
+				texts.WriteString(pp.phraseSep)
+				alt.Handled()
+
 			case "a:tbl":
 				table := pp.extractTable(r)
 				if table != nil {
@@ -263,7 +401,10 @@ NEXT:
 				}
 
 			case "a:blip":
-				if !pp.parseImages {
+				if altText != "" && (pp.parseAltText || pp.parseImages && pp.preferAltText) {
+					texts.WriteString(utils.FormatBox(altText, "alt text", pp.drawingsNoFmt).String())
+				}
+				if !pp.parseImages || pp.preferAltText && altText != "" {
 					continue
 				}
 				attrs := e.Attrs()
@@ -275,6 +416,24 @@ NEXT:
 						texts.WriteString(image.String())
 					}
 				}
+
+			case "p:oleObj":
+				if !pp.parseEmbeds {
+					continue
+				}
+				attrs := e.Attrs()
+				if attrs.Len() > 0 {
+					rIdKV := attrs.Get("r:id")
+					if rIdKV == nil || embedded[rIdKV.Value()] {
+						continue
+					}
+					embedded[rIdKV.Value()] = true
+					embedding, err := pp.extractEmbedding(i, rIdKV.Value())
+					pp.logWarn(err)
+					if embedding != nil {
+						texts.WriteString(embedding.String())
+					}
+				}
 			}
 		}
 	}
@@ -318,7 +477,7 @@ NEXT:
 					row.Reset()
 					a_t = ""
 				}
-			case "w:tbl":
+			case "a:tbl":
 				break NEXT
 			}
 		}
@@ -8,13 +8,25 @@ package pptxtotext
 import (
 	"bytes"
 	"image/jpeg"
+	"io"
 	"os"
+	"strings"
 	"testing"
+
+	"github.com/young2j/oxmltotext/types"
 )
 
 var (
-	pptxPath = "../filesamples/file-sample_500kb.pptx"
-	pptxURL  = "https://zcc.czu.cn/_upload/article/files/06/b5/8a64cb854694bcd2265ad0b96c99/65ba8668-56f7-4cd6-ab51-b55349964a17.pptx"
+	pptxPath        = "../filesamples/file-sample_500kb.pptx"
+	pptxHiddenPath  = "../filesamples/file-sample_hidden.pptx"
+	pptxMathPath    = "../filesamples/file-sample_math.pptx"
+	pptxAltTextPath = "../filesamples/file-sample_alttext.pptx"
+	pptxLangPath    = "../filesamples/file-sample_lang.pptx"
+	pptxFormatPath  = "../filesamples/file-sample_format.pptx"
+	pptxRenamedPath = "../filesamples/file-sample_renamed.pptm"
+	pptxStrictPath  = "../filesamples/file-sample_strict.pptx"
+	pptxTablesPath  = "../filesamples/file-sample_tables.pptx"
+	pptxURL         = "https://zcc.czu.cn/_upload/article/files/06/b5/8a64cb854694bcd2265ad0b96c99/65ba8668-56f7-4cd6-ab51-b55349964a17.pptx"
 )
 
 func TestOpen(t *testing.T) {
@@ -188,3 +200,287 @@ func TestExtractImages(t *testing.T) {
 	}
 
 }
+
+func TestHiddenMode(t *testing.T) {
+	pp, err := Open(pptxHiddenPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pp.Close()
+
+	texts, err := pp.ExtractTexts()
+	if err != nil {
+		t.Error(err)
+	}
+	if !strings.Contains(texts, "Chart") || !strings.Contains(texts, "Image") {
+		t.Error("hidden content should be included by default")
+	}
+
+	pp.SetHiddenMode(types.HiddenExclude)
+	texts, err = pp.ExtractTexts()
+	if err != nil {
+		t.Error(err)
+	}
+	if strings.Contains(texts, "Chart") {
+		t.Error("hidden shape should be excluded")
+	}
+	if strings.Contains(texts, "Image") {
+		t.Error("hidden slide should be excluded")
+	}
+
+	pp.SetHiddenMode(types.HiddenMark)
+	texts, err = pp.ExtractTexts()
+	if err != nil {
+		t.Error(err)
+	}
+	if strings.Count(texts, types.HiddenMarkStart) != 2 || strings.Count(texts, types.HiddenMarkEnd) != 2 {
+		t.Error("hidden shape and slide should be marked")
+	}
+
+	t.Log(texts)
+}
+
+func TestMathMode(t *testing.T) {
+	pp, err := Open(pptxMathPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pp.Close()
+
+	texts, err := pp.ExtractSlideTexts(4)
+	if err != nil {
+		t.Error(err)
+	}
+	if !strings.Contains(texts, `$$x=\frac{-b\pm\sqrt{b^{2}-4ac}}{2a}$$`) {
+		t.Error("missing LaTeX equation")
+	}
+	// the fallback of the equation(mc:Fallback) is skipped
+	if strings.Contains(texts, "Equation fallback") {
+		t.Error("unexpected fallback content")
+	}
+	// the fallback of the unhandled choice is kept
+	if !strings.Contains(texts, "Media fallback") {
+		t.Error("missing fallback content")
+	}
+
+	pp.SetMathMode(types.MathLinear)
+	texts, err = pp.ExtractSlideTexts(4)
+	if err != nil {
+		t.Error(err)
+	}
+	if !strings.Contains(texts, "x=(−b±√(b^2−4ac))/(2a)") {
+		t.Error("missing linear equation")
+	}
+
+	paragraphs, err := pp.ExtractParagraphs()
+	if err != nil {
+		t.Error(err)
+	}
+	media := false
+	for _, p := range paragraphs {
+		if strings.Contains(p.Text, "Equation fallback") {
+			t.Error("unexpected fallback paragraph")
+		}
+		media = media || p.Text == "Media fallback"
+	}
+	if !media {
+		t.Error("missing fallback paragraph")
+	}
+
+	t.Log(texts)
+}
+
+// textOcr is an ocr interface returning the fixed text.
+type textOcr string
+
+func (o textOcr) Run(r io.Reader) (string, error) { return string(o), nil }
+func (o textOcr) Close() error                    { return nil }
+
+func TestAltText(t *testing.T) {
+	pp, err := Open(pptxAltTextPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pp.Close()
+
+	imgs, err := pp.ExtractImages()
+	if err != nil {
+		t.Error(err)
+	}
+	found := false
+	for _, img := range imgs {
+		t.Logf("img: %v title: %q alt text: %q", img.Name, img.Title, img.AltText)
+		if img.Title == "Sales" && img.AltText == "Bar chart of quarterly sales" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("missing image alt text")
+	}
+
+	pp.SetOcrInterface(textOcr("ocr text"))
+	pp.SetParseImages(true)
+	pp.SetPreferAltText(true)
+	texts, err := pp.ExtractTexts()
+	if err != nil {
+		t.Error(err)
+	}
+	if !strings.Contains(texts, " Bar chart of quarterly sales\n") {
+		t.Error("missing alt text")
+	}
+	if strings.Count(texts, "ocr text") != 1 {
+		t.Error("ocr should be used only for the image without alt text")
+	}
+
+	t.Log(texts)
+}
+
+func TestParagraphs(t *testing.T) {
+	pp, err := Open(pptxLangPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pp.Close()
+
+	paragraphs, err := pp.ExtractParagraphs()
+	if err != nil {
+		t.Error(err)
+	}
+	for _, p := range paragraphs {
+		t.Logf("slide: %d paragraph: %q rtl: %v spans: %+v", p.Page, p.Text, p.RTL, p.Spans)
+	}
+	if len(paragraphs) < 2 {
+		t.Fatal("missing paragraphs")
+	}
+	if p := paragraphs[0]; !p.RTL || p.Page != 1 || len(p.Spans) != 2 ||
+		p.Spans[0] != (types.Span{Text: "مرحبا ", Lang: "ar-SA", RTL: true}) ||
+		p.Spans[1] != (types.Span{Text: "PowerPoint", Lang: "en-US"}) {
+		t.Error("unexpected rtl paragraph")
+	}
+	if p := paragraphs[1]; p.RTL || len(p.Spans) != 1 || p.Spans[0] != (types.Span{Text: "你好世界", Lang: "zh-CN"}) {
+		t.Error("adjacent runs with the same language should be merged")
+	}
+
+	pp.SetBidiMarks(true)
+	texts, err := pp.ExtractSlideTexts(1)
+	if err != nil {
+		t.Error(err)
+	}
+	if !strings.HasPrefix(texts, "\u200fمرحبا ") {
+		t.Error("missing right-to-left mark")
+	}
+}
+
+func TestFormatting(t *testing.T) {
+	pp, err := Open(pptxFormatPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pp.Close()
+
+	paragraphs, err := pp.ExtractParagraphs()
+	if err != nil {
+		t.Error(err)
+	}
+	if len(paragraphs) < 2 {
+		t.Fatal("missing paragraphs")
+	}
+	for _, p := range paragraphs[:2] {
+		t.Logf("paragraph: %q spans: %+v", p.Text, p.Spans)
+	}
+
+	// italic is inherited from the list style of the shape, adjacent runs are merged
+	spans := paragraphs[0].Spans
+	if len(spans) != 3 ||
+		spans[0] != (types.Span{Text: "Bold italic", Lang: "en-US", Bold: true, Italic: true}) ||
+		spans[1] != (types.Span{Text: "plain", Lang: "en-US", Underline: true, Strike: true}) ||
+		spans[2] != (types.Span{Text: "2", Lang: "en-US", Superscript: true}) {
+		t.Error("unexpected formatting")
+	}
+	// bold is inherited from the title style of slide master
+	if spans := paragraphs[1].Spans; len(spans) != 1 || !spans[0].Bold {
+		t.Error("missing formatting of slide master")
+	}
+}
+
+func TestRenamedParts(t *testing.T) {
+	want, err := Open(pptxPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer want.Close()
+	pp, err := Open(pptxRenamedPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pp.Close()
+
+	// the parts are discovered by relationships instead of their conventional names
+	want.SetParseCharts(true)
+	pp.SetParseCharts(true)
+	wantTexts, _ := want.ExtractTexts()
+	texts, err := pp.ExtractTexts()
+	if err != nil {
+		t.Error(err)
+	}
+	if texts == "" || texts != wantTexts {
+		t.Error("unexpected texts of renamed parts")
+	}
+	imgs, err := pp.ExtractImages()
+	if err != nil {
+		t.Error(err)
+	}
+	t.Logf("images: %d", len(imgs))
+	if len(imgs) == 0 {
+		t.Error("missing images")
+	}
+
+	t.Log(texts)
+}
+
+func TestStrict(t *testing.T) {
+	want, err := Open(pptxPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer want.Close()
+	pp, err := Open(pptxStrictPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pp.Close()
+
+	// the parts use the Strict namespaces and non-default prefixes
+	want.SetParseCharts(true)
+	pp.SetParseCharts(true)
+	wantTexts, _ := want.ExtractTexts()
+	texts, err := pp.ExtractTexts()
+	if err != nil {
+		t.Error(err)
+	}
+	if texts == "" || texts != wantTexts {
+		t.Error("unexpected texts of strict parts")
+	}
+
+	t.Log(texts)
+}
+
+func TestTables(t *testing.T) {
+	pp, err := Open(pptxTablesPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pp.Close()
+
+	// the table ends at a:tbl, the shape after the table is written as normal text
+	pp.SetTableColSep("|")
+	texts, err := pp.ExtractSlideTexts(3)
+	if err != nil {
+		t.Error(err)
+	}
+	t.Logf("%q", texts)
+	if !strings.Contains(texts, "Column 1|Column 2|Column 3|Column 4|Column 5|\nC1|C2|C3|C4|C5|\n") ||
+		!strings.Contains(texts, "C1|C2|C3|C4|C5|\nNote after the table") {
+		t.Error("unexpected table texts")
+	}
+}
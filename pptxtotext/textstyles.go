@@ -0,0 +1,298 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package pptxtotext
+
+import (
+	"archive/zip"
+	"strconv"
+	"strings"
+
+	"github.com/young2j/oxmltotext/opc"
+	"github.com/young2j/oxmltotext/types"
+
+	qxml "github.com/dgrr/quickxml"
+)
+
+// textProps is the text run properties(a:rPr and a:defRPr) concerned by the parser,
+// the empty properties are not specified and inherited.
+type textProps struct {
+	lang     string
+	b        string
+	i        string
+	u        string
+	strike   string
+	baseline string
+}
+
+// parseTextProps parses the attributes of the text run properties.
+func parseTextProps(e *qxml.StartElement) textProps {
+	var (
+		tp    textProps
+		attrs = e.Attrs()
+	)
+	if attr := attrs.Get("lang"); attr != nil {
+		tp.lang = attr.Value()
+	}
+	if attr := attrs.Get("b"); attr != nil {
+		tp.b = attr.Value()
+	}
+	if attr := attrs.Get("i"); attr != nil {
+		tp.i = attr.Value()
+	}
+	if attr := attrs.Get("u"); attr != nil {
+		tp.u = attr.Value()
+	}
+	if attr := attrs.Get("strike"); attr != nil {
+		tp.strike = attr.Value()
+	}
+	if attr := attrs.Get("baseline"); attr != nil {
+		tp.baseline = attr.Value()
+	}
+
+	return tp
+}
+
+// merge overrides the properties of tp with the specified properties of o.
+func (tp *textProps) merge(o textProps) {
+	if o.lang != "" {
+		tp.lang = o.lang
+	}
+	if o.b != "" {
+		tp.b = o.b
+	}
+	if o.i != "" {
+		tp.i = o.i
+	}
+	if o.u != "" {
+		tp.u = o.u
+	}
+	if o.strike != "" {
+		tp.strike = o.strike
+	}
+	if o.baseline != "" {
+		tp.baseline = o.baseline
+	}
+}
+
+// span returns a span with the language and formatting of the properties.
+func (tp textProps) span() types.Span {
+	baseline, _ := strconv.Atoi(tp.baseline)
+	return types.Span{
+		Lang:        tp.lang,
+		Bold:        tp.b == "1" || tp.b == "true",
+		Italic:      tp.i == "1" || tp.i == "true",
+		Underline:   tp.u != "" && tp.u != "none",
+		Strike:      tp.strike != "" && tp.strike != "noStrike",
+		Superscript: baseline > 0,
+		Subscript:   baseline < 0,
+	}
+}
+
+// listStyle is the default run properties of a list style, such as a:lstStyle, p:titleStyle and
+// p:defaultTextStyle, the first is of a:defPPr and the others are of a:lvl1pPr ~ a:lvl9pPr.
+type listStyle [10]textProps
+
+// parseListStyle parses the list style until the end of e.
+//
+// Parameters:
+//   - r: the qxml.Reader of the part.
+//   - e: the start element of the list style that has been read.
+//
+// Returns:
+//   - listStyle: the default run properties of the levels.
+func parseListStyle(r *qxml.Reader, e *qxml.StartElement) listStyle {
+	var (
+		ls  listStyle
+		end = e.Name()
+		lvl = -1
+	)
+	if e.HasEnd() {
+		return ls
+	}
+
+	for r.Next() {
+		switch e := r.Element().(type) {
+		case *qxml.StartElement:
+			name := e.Name()
+			switch {
+			case name == "a:defPPr":
+				lvl = 0
+			case strings.HasPrefix(name, "a:lvl") && strings.HasSuffix(name, "pPr"):
+				lvl, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, "a:lvl"), "pPr"))
+			case name == "a:defRPr" && lvl >= 0 && lvl < len(ls):
+				ls[lvl] = parseTextProps(e)
+			}
+
+		case *qxml.EndElement:
+			if e.Name() == end {
+				return ls
+			}
+		}
+	}
+
+	return ls
+}
+
+// resolve returns the default run properties of the paragraph level(start 0).
+func (ls *listStyle) resolve(lvl int) textProps {
+	tp := ls[0]
+	if lvl >= 0 && lvl+1 < len(ls) {
+		tp.merge(ls[lvl+1])
+	}
+	return tp
+}
+
+// masterStyles is the text styles(p:txStyles) of a slide master.
+type masterStyles struct {
+	title listStyle
+	body  listStyle
+	other listStyle
+}
+
+// textStyles is the text styles inherited by slides, from the presentation defaults and slide masters.
+type textStyles struct {
+	defaults listStyle
+	masters  map[string]*masterStyles
+}
+
+// loadTextStyles parses the default text style of the presentation once and returns the text styles.
+func (pp *PptxParser) loadTextStyles() *textStyles {
+	if pp.textStyles != nil {
+		return pp.textStyles
+	}
+
+	pp.textStyles = &textStyles{masters: make(map[string]*masterStyles, 1)}
+	err := parseStylesPart(pp.presentationFile, func(r *qxml.Reader, e *qxml.StartElement) {
+		if e.Name() == "p:defaultTextStyle" {
+			pp.textStyles.defaults = parseListStyle(r, e)
+		}
+	})
+	pp.logWarn(err)
+
+	return pp.textStyles
+}
+
+// masterStyles returns the text styles of the slide master used by the slide, or nil if not found.
+//
+// Parameters:
+//   - i: the index of the slide.
+//
+// Returns:
+//   - *masterStyles: the text styles of the slide master.
+func (pp *PptxParser) masterStyles(i int) *masterStyles {
+	slide, ok := pp.slideFiles[i]
+	if !ok {
+		return nil
+	}
+	var master string
+	for _, layout := range pp.pkg.Related(slide.Name, "slideLayout") {
+		for _, target := range pp.pkg.Related(layout, "slideMaster") {
+			master = target
+		}
+	}
+	file := pp.pkg.File(master)
+	if file == nil {
+		return nil
+	}
+
+	ts := pp.loadTextStyles()
+	if ms, ok := ts.masters[master]; ok {
+		return ms
+	}
+	ms := new(masterStyles)
+	err := parseStylesPart(file, func(r *qxml.Reader, e *qxml.StartElement) {
+		switch e.Name() {
+		case "p:titleStyle":
+			ms.title = parseListStyle(r, e)
+		case "p:bodyStyle":
+			ms.body = parseListStyle(r, e)
+		case "p:otherStyle":
+			ms.other = parseListStyle(r, e)
+		}
+	})
+	pp.logWarn(err)
+	ts.masters[master] = ms
+
+	return ms
+}
+
+// parseStylesPart iterates the start elements of the part and calls fn with each of them.
+func parseStylesPart(f *zip.File, fn func(r *qxml.Reader, e *qxml.StartElement)) error {
+	if f == nil {
+		return nil
+	}
+
+	rc, err := opc.OpenPart(f)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	r := qxml.NewReader(rc)
+	for r.Next() {
+		if e, ok := r.Element().(*qxml.StartElement); ok {
+			fn(r, e)
+		}
+	}
+
+	return nil
+}
+
+// shapeStyle resolves the text run properties of the paragraphs of a shape by the style hierarchy:
+// presentation defaults, the text style of slide master by placeholder type and the list style of the shape.
+type shapeStyle struct {
+	defaults *listStyle
+	master   *listStyle
+	shape    listStyle
+}
+
+// newShapeStyle returns the shapeStyle of a shape.
+//
+// Parameters:
+//   - ts: the text styles of the presentation.
+//   - ms: the text styles of slide master, nil if not found.
+//   - ph: the placeholder type of the shape, "-" for the shapes which are not placeholders.
+//
+// Returns:
+//   - *shapeStyle: the shapeStyle.
+func newShapeStyle(ts *textStyles, ms *masterStyles, ph string) *shapeStyle {
+	ss := &shapeStyle{defaults: &ts.defaults}
+	if ms == nil {
+		return ss
+	}
+
+	switch ph {
+	case "title", "ctrTitle":
+		ss.master = &ms.title
+	case "-", "dt", "ftr", "sldNum":
+		ss.master = &ms.other
+	default:
+		ss.master = &ms.body
+	}
+
+	return ss
+}
+
+// resolve resolves the effective run properties of the paragraph level.
+//
+// Parameters:
+//   - lvl: the paragraph level(start 0).
+//   - para: the default run properties of the paragraph(a:pPr/a:defRPr).
+//   - direct: the run properties(a:rPr).
+//
+// Returns:
+//   - textProps: the effective run properties.
+func (ss *shapeStyle) resolve(lvl int, para, direct textProps) textProps {
+	tp := ss.defaults.resolve(lvl)
+	if ss.master != nil {
+		tp.merge(ss.master.resolve(lvl))
+	}
+	tp.merge(ss.shape.resolve(lvl))
+	tp.merge(para)
+	tp.merge(direct)
+
+	return tp
+}
@@ -0,0 +1,13 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package types
+
+// Bookmark is a bookmarked range of a document, such as w:bookmarkStart and w:bookmarkEnd of docx.
+type Bookmark struct {
+	Name string // the bookmark name, names starting with "_" are hidden, like "_Ref" and "_Toc" bookmarks
+	Text string // the text of the bookmarked range, paragraphs are separated by "\n"
+	Page int    // the estimated page(start 1) where the bookmark starts
+}
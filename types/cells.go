@@ -0,0 +1,43 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package types
+
+import "time"
+
+// CellType is the type of the cell value.
+type CellType int
+
+const (
+	// CellEmpty is the cell without value.
+	CellEmpty CellType = iota
+	// CellString is the cell of shared strings, inline strings and formula strings.
+	CellString
+	// CellNumber is the cell of numbers, Number is set.
+	CellNumber
+	// CellBool is the cell of booleans, Bool is set.
+	CellBool
+	// CellDate is the cell of dates, which are the numbers with date formats or ISO 8601 dates,
+	// Time and Number(the serial date) are set.
+	CellDate
+	// CellError is the cell of errors, like #N/A and #DIV/0!.
+	CellError
+)
+
+// Cell is a cell of a worksheet with its position, such as c of xlsx.
+type Cell struct {
+	Ref     string // the A1 style reference of the cell, like "B3"
+	Col     int    // the column number(start 1)
+	Row     int    // the row number(start 1)
+	Value   string // the text of the cell as written in the sheet texts
+	ColSpan int    // the number of columns of the merged cell, 1 if the cell is not merged
+	RowSpan int    // the number of rows of the merged cell, 1 if the cell is not merged
+
+	Type    CellType
+	Number  float64
+	Bool    bool
+	Time    time.Time
+	Formula string // the formula of the cell, like "=SUM(A1:A3)", empty if none
+}
@@ -0,0 +1,29 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package types
+
+import "time"
+
+// Comment is a comment of a cell, such as the notes(legacy comments) and threaded comments of xlsx.
+type Comment struct {
+	ID       string    // the id of the threaded comment, empty for notes
+	Ref      string    // the A1 style reference of the annotated cell, like "B3"
+	Col      int       // the column number(start 1)
+	Row      int       // the row number(start 1)
+	Author   string    // the author of the note, or the display name of the person of the threaded comment
+	Text     string    // the text of the comment
+	Time     time.Time // the time of the threaded comment, zero for notes
+	Threaded bool      // the comment is a threaded comment, otherwise a note
+	Done     bool      // the thread of the threaded comment is resolved
+	Replies  []Comment // the replies of the threaded comment in the thread order
+}
+
+const (
+	CommentMarkStart = "[comment]"
+	CommentMarkEnd   = "[/comment]"
+	// CommentReplySep separates the comment and its replies written inline.
+	CommentReplySep = " | "
+)
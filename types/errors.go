@@ -5,17 +5,47 @@
 
 package types
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	ErrNilZipFile      = errors.New("the input zip file is nil")
 	ErrEmptyRID        = errors.New("the rId is empty")
 	ErrNonePart        = errors.New("the document part resolves failed or not exists")
 	ErrNoSlide         = errors.New("the specified slide is not found")
+	ErrNoPage          = errors.New("the specified page is not found")
 	ErrNoSheet         = errors.New("the specified sheet is not found")
 	ErrNoSharedStrings = errors.New("the sharedStrings.xml file is not found")
 	ErrNoDocument      = errors.New("the document.xml file is not found")
 	ErrNoComments      = errors.New("the comments.xml file is not found")
 	ErrNoEndnotes      = errors.New("the endnotes.xml file is not found")
 	ErrNoFootnotes     = errors.New("the footnotes.xml file is not found")
+	ErrNilEmbed        = errors.New("the embed interface is not set")
+	ErrUnknownEmbed    = errors.New("the embedded object format is not supported")
+	ErrNoTikaServer    = errors.New("the tika server url is not set")
+	ErrBadDelimiter    = errors.New("the delimiter of CSV is invalid")
+	ErrBadRange        = errors.New("the cell range is invalid")
+	ErrNoDefinedName   = errors.New("the specified defined name is not found")
+	ErrNoTable         = errors.New("the specified table is not found")
+	ErrNoHeader        = errors.New("the header row is not found")
+	ErrBadDecodeTarget = errors.New("the decode target must be a non-nil pointer to a struct")
 )
+
+// RowError is the error of decoding a cell of a row, such as the records of xlsx.
+type RowError struct {
+	Row    int    // the row number(start 1)
+	Column string // the column name of the header
+	Field  string // the name of the struct field
+	Value  string // the text of the cell
+	Err    error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("row %d, column %q, field %s: can not decode %q: %v", e.Row, e.Column, e.Field, e.Value, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,31 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package types
+
+// The types of form fields.
+const (
+	FormFieldText     = "text"
+	FormFieldRichText = "richText"
+	FormFieldDate     = "date"
+	FormFieldDropDown = "dropDown"
+	FormFieldComboBox = "comboBox"
+	FormFieldCheckBox = "checkbox"
+	FormFieldPicture  = "picture"
+	FormFieldDocPart  = "docPart"
+	FormFieldGroup    = "group"
+)
+
+// FormField is a form field of a document,
+// such as a content control(w:sdt) or a legacy form field(FORMTEXT, FORMCHECKBOX, FORMDROPDOWN) of docx.
+type FormField struct {
+	Tag     string   // the tag(w:tag) of content control, or the name of legacy form field
+	Alias   string   // the friendly name(w:alias) of content control
+	Type    string   // one of the FormField* types
+	Value   string   // the current value, "true" or "false" for checkbox
+	Options []string // the list items of drop-down list and combo box
+	Binding string   // the XPath of the bound custom XML data(w:dataBinding)
+	Legacy  bool     // whether it is a legacy form field
+}
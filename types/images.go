@@ -11,4 +11,11 @@ type Image struct {
 	Raw    image.Image
 	Name   string
 	Format string
+
+	// AltText is the accessibility description(descr) of the first drawing referencing the image.
+	AltText string
+	// Title is the accessibility title of the first drawing referencing the image.
+	Title string
+	// Caption is the text of the Word caption(Caption style or SEQ field) labeling the image, docx only.
+	Caption string
 }
@@ -11,3 +11,11 @@ type OCR interface {
 	Run(r io.Reader) (string, error)
 	Close() error
 }
+
+// Embed extracts text from an embedded object(OLE object or package) of a document.
+//
+// The name is the part name of the embedded object, maxDepth is the remaining depth
+// that the embedded object is allowed to recursively extract its own embedded objects.
+type Embed interface {
+	Run(r io.ReaderAt, n int64, name string, maxDepth int) (string, error)
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package types
+
+// HiddenMode is the mode of handling hidden content,
+// such as vanished runs of docx and hidden shapes or slides of pptx.
+type HiddenMode int
+
+const (
+	// HiddenInclude includes hidden content as normal content.
+	HiddenInclude HiddenMode = iota
+	// HiddenExclude excludes hidden content.
+	HiddenExclude
+	// HiddenMark includes hidden content surrounded by HiddenMarkStart and HiddenMarkEnd.
+	HiddenMark
+)
+
+const (
+	HiddenMarkStart = "[hidden]"
+	HiddenMarkEnd   = "[/hidden]"
+)
+
+// MathMode is the mode of converting Office Math(OMML) equations to text.
+type MathMode int
+
+const (
+	// MathLaTeX converts equations to LaTeX, inline math is surrounded by "$" and display math by "$$".
+	MathLaTeX MathMode = iota
+	// MathLinear converts equations to the linear format of UnicodeMath.
+	MathLinear
+	// MathPlain concatenates the text of math runs.
+	MathPlain
+)
+
+// FormulaMode is the mode of writing the cells with formulas of xlsx.
+type FormulaMode int
+
+const (
+	// FormulaValue writes the cached values of formulas.
+	FormulaValue FormulaMode = iota
+	// FormulaText writes the formulas, like "=SUM(A1:A3)", and "{=A1:A3*B1:B3}" for array formulas.
+	FormulaText
+	// FormulaBoth writes the formulas followed by FormulaValueSep and the cached values.
+	FormulaBoth
+)
+
+const (
+	FormulaValueSep = " => "
+	// FormulaNoValue is written in FormulaBoth mode for the formulas without cached values(never calculated),
+	// the values of them are empty in FormulaValue mode.
+	FormulaNoValue = "[no cached value]"
+)
+
+// MergeMode is the mode of writing the merged cells(mergeCells) of xlsx.
+type MergeMode int
+
+const (
+	// MergeBlank writes the value in the top left cell of the merged cells, and leaves the covered cells blank.
+	MergeBlank MergeMode = iota
+	// MergeRepeat repeats the value of the top left cell into every covered cell.
+	MergeRepeat
+	// MergeSpan spans the top left cell over the covered cells by ColSpan and RowSpan of the structured cells,
+	// and omits the covered cells. The texts are written as MergeBlank.
+	MergeSpan
+)
+
+// QuoteMode is the mode of quoting the fields of CSV.
+type QuoteMode int
+
+const (
+	// QuoteMinimal quotes the fields containing the delimiter, quotes or line breaks as RFC 4180.
+	QuoteMinimal QuoteMode = iota
+	// QuoteAll quotes all the fields.
+	QuoteAll
+	// QuoteNone writes the fields without quoting, the delimiters and line breaks in fields are replaced by spaces,
+	// such as the TSV(text/tab-separated-values) format.
+	QuoteNone
+)
+
+// Encoding is the character encoding of the text output.
+type Encoding int
+
+const (
+	// EncodingUTF8 is UTF-8 without BOM.
+	EncodingUTF8 Encoding = iota
+	// EncodingUTF8BOM is UTF-8 with BOM(EF BB BF), which is recognized by Excel.
+	EncodingUTF8BOM
+	// EncodingGBK is GBK of simplified Chinese, the characters not in GBK are replaced.
+	EncodingGBK
+)
@@ -0,0 +1,32 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package types
+
+// Paragraph is a paragraph of a document or slide with the spans of its text,
+// such as w:p of docx and a:p of pptx.
+type Paragraph struct {
+	Text  string // the text of the paragraph in logical order
+	Style string // the paragraph style id, empty for the default style
+	RTL   bool   // the paragraph is right-to-left
+	Page  int    // the estimated page of docx or the slide of pptx(start 1)
+	Spans []Span
+}
+
+// Span is a range of the paragraph text with the same properties, adjacent runs with the same properties are merged.
+// The properties are resolved through the style hierarchy, such as styles part of docx and slide masters of pptx.
+type Span struct {
+	Text   string
+	Lang   string // the language tag of the text, like "en-US", "zh-CN" and "ar-SA", empty if unknown
+	RTL    bool   // the text is right-to-left
+	Hidden bool   // the text is hidden, always false in types.HiddenInclude mode
+
+	Bold        bool
+	Italic      bool
+	Underline   bool // any underline except none
+	Strike      bool // single or double strikethrough
+	Superscript bool
+	Subscript   bool
+}
@@ -0,0 +1,16 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package types
+
+// Table is a structured table of a worksheet, such as the tables(ListObjects) of xlsx.
+type Table struct {
+	Name    string     // the name of the table shown by Excel(table@displayName), like "Sales"
+	Sheet   string     // the name of the sheet of the table
+	Ref     string     // the range of the table including the header and totals rows, like "A1:D10"
+	Columns []string   // the column names of the table
+	Rows    [][]string // the cell values of the data rows, each row has a value for every column
+	Totals  []string   // the cell values of the totals row, nil if the table has no totals row
+}
@@ -0,0 +1,214 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package utils
+
+import (
+	"bytes"
+	"encoding/base64"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+)
+
+var (
+	// htmlBlockTags are the tags that start a new line.
+	htmlBlockTags = map[string]bool{
+		"p": true, "div": true, "br": true, "li": true, "tr": true, "table": true,
+		"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+		"ul": true, "ol": true, "dl": true, "dt": true, "dd": true, "pre": true,
+		"blockquote": true, "hr": true, "section": true, "article": true,
+		"header": true, "footer": true, "title": true, "caption": true,
+	}
+	// htmlCellTags are the tags that end a table cell.
+	htmlCellTags = map[string]bool{
+		"td": true, "th": true,
+	}
+	// htmlSkipTags are the tags whose content is not text.
+	htmlSkipTags = map[string]bool{
+		"script": true, "style": true, "head": true, "xml": true, "object": true,
+	}
+)
+
+// HTMLToText converts html to plain text lines.
+//
+// Block level tags start new lines, table cells are separated by "\t",
+// the content of script, style and head tags is dropped and html entities are unescaped.
+//
+// Parameters:
+//   - s: the html string.
+//
+// Returns:
+//   - string: the plain text with lines separated by "\n".
+func HTMLToText(s string) string {
+	var (
+		texts = new(strings.Builder)
+		line  = new(strings.Builder)
+		skip  = ""
+	)
+
+	flushLine := func() {
+		l := strings.TrimSpace(line.String())
+		if len(l) > 0 {
+			texts.WriteString(l)
+			texts.WriteByte('\n')
+		}
+		line.Reset()
+	}
+
+	for len(s) > 0 {
+		lt := strings.IndexByte(s, '<')
+		if lt < 0 {
+			lt = len(s)
+		}
+		if skip == "" && lt > 0 {
+			text := html.UnescapeString(s[:lt])
+			if isHTMLSpace(text[0]) {
+				writeHTMLSpace(line)
+			}
+			for i, field := range strings.Fields(text) {
+				if i > 0 {
+					line.WriteByte(' ')
+				}
+				line.WriteString(field)
+			}
+			if isHTMLSpace(text[len(text)-1]) {
+				writeHTMLSpace(line)
+			}
+		}
+		s = s[lt:]
+		if len(s) == 0 {
+			break
+		}
+
+		// comments
+		if strings.HasPrefix(s, "<!--") {
+			end := strings.Index(s, "-->")
+			if end < 0 {
+				break
+			}
+			s = s[end+3:]
+			continue
+		}
+
+		gt := strings.IndexByte(s, '>')
+		if gt < 0 {
+			break
+		}
+		tag := s[1:gt]
+		s = s[gt+1:]
+
+		closing := strings.HasPrefix(tag, "/")
+		name := strings.ToLower(strings.TrimLeft(tag, "/"))
+		if i := strings.IndexAny(name, " \t\r\n/"); i >= 0 {
+			name = name[:i]
+		}
+		if i := strings.IndexByte(name, ':'); i >= 0 {
+			name = name[i+1:]
+		}
+
+		switch {
+		case skip != "":
+			if closing && name == skip {
+				skip = ""
+			}
+		case htmlSkipTags[name]:
+			if !closing && !strings.HasSuffix(tag, "/") {
+				skip = name
+			}
+		case htmlBlockTags[name]:
+			flushLine()
+		case htmlCellTags[name] && closing:
+			l := strings.TrimSpace(line.String())
+			line.Reset()
+			line.WriteString(l)
+			line.WriteByte('\t')
+		}
+	}
+	flushLine()
+
+	return texts.String()
+}
+
+// writeHTMLSpace writes a collapsed space to the line unless the line is empty or ends with a space.
+func writeHTMLSpace(line *strings.Builder) {
+	l := line.String()
+	if len(l) > 0 && !isHTMLSpace(l[len(l)-1]) {
+		line.WriteByte(' ')
+	}
+}
+
+func isHTMLSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+// MHTToText converts a MIME html archive(mht) to plain text lines.
+//
+// The first text/html part of the archive is converted by HTMLToText.
+//
+// Parameters:
+//   - data: the mht content.
+//
+// Returns:
+//   - string: the plain text with lines separated by "\n".
+//   - error: an error if the archive is malformed.
+func MHTToText(data []byte) (string, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		return "", err
+	}
+
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		body, err := decodeTransfer(msg.Body, msg.Header.Get("Content-Transfer-Encoding"))
+		if err != nil {
+			return "", err
+		}
+		return HTMLToText(BytesToString(body)), nil
+	}
+
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		if partType != "text/html" {
+			continue
+		}
+		// quoted-printable parts are decoded by multipart.Reader already.
+		body, err := decodeTransfer(part, part.Header.Get("Content-Transfer-Encoding"))
+		if err != nil {
+			return "", err
+		}
+		return HTMLToText(BytesToString(body)), nil
+	}
+
+	return "", nil
+}
+
+// decodeTransfer reads r and decodes it by the Content-Transfer-Encoding.
+func decodeTransfer(r io.Reader, encoding string) ([]byte, error) {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		r = base64.NewDecoder(base64.StdEncoding, r)
+	case "quoted-printable":
+		r = quotedprintable.NewReader(r)
+	}
+
+	return io.ReadAll(r)
+}
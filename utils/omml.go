@@ -0,0 +1,608 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package utils
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/young2j/oxmltotext/types"
+)
+
+var (
+	// latexSymbols maps the characters of math runs to LaTeX commands.
+	latexSymbols = map[rune]string{
+		'α': `\alpha`, 'β': `\beta`, 'γ': `\gamma`, 'δ': `\delta`, 'ε': `\epsilon`, 'ζ': `\zeta`,
+		'η': `\eta`, 'θ': `\theta`, 'ι': `\iota`, 'κ': `\kappa`, 'λ': `\lambda`, 'μ': `\mu`,
+		'ν': `\nu`, 'ξ': `\xi`, 'π': `\pi`, 'ρ': `\rho`, 'σ': `\sigma`, 'τ': `\tau`,
+		'υ': `\upsilon`, 'φ': `\phi`, 'χ': `\chi`, 'ψ': `\psi`, 'ω': `\omega`, 'ϕ': `\phi`,
+		'ϵ': `\epsilon`, 'ϑ': `\vartheta`, 'ς': `\varsigma`,
+		'Γ': `\Gamma`, 'Δ': `\Delta`, 'Θ': `\Theta`, 'Λ': `\Lambda`, 'Ξ': `\Xi`, 'Π': `\Pi`,
+		'Σ': `\Sigma`, 'Υ': `\Upsilon`, 'Φ': `\Phi`, 'Ψ': `\Psi`, 'Ω': `\Omega`,
+		'∞': `\infty`, '≤': `\leq`, '≥': `\geq`, '≠': `\neq`, '≈': `\approx`, '≡': `\equiv`,
+		'±': `\pm`, '∓': `\mp`, '×': `\times`, '÷': `\div`, '·': `\cdot`, '⋅': `\cdot`,
+		'→': `\rightarrow`, '←': `\leftarrow`, '↔': `\leftrightarrow`, '⇒': `\Rightarrow`,
+		'⇐': `\Leftarrow`, '⇔': `\Leftrightarrow`, '↦': `\mapsto`,
+		'∂': `\partial`, '∇': `\nabla`, '∈': `\in`, '∉': `\notin`, '∋': `\ni`,
+		'⊂': `\subset`, '⊃': `\supset`, '⊆': `\subseteq`, '⊇': `\supseteq`,
+		'∪': `\cup`, '∩': `\cap`, '∀': `\forall`, '∃': `\exists`, '∅': `\emptyset`,
+		'¬': `\neg`, '∧': `\wedge`, '∨': `\vee`, '∝': `\propto`, '∼': `\sim`, '≅': `\cong`,
+		'≪': `\ll`, '≫': `\gg`, '⊥': `\perp`, '∥': `\parallel`, '∠': `\angle`,
+		'⊕': `\oplus`, '⊗': `\otimes`, '∘': `\circ`, '…': `\ldots`, '⋯': `\cdots`,
+		'ℏ': `\hbar`, 'ℓ': `\ell`, '°': `^{\circ}`, '−': `-`, '∗': `*`,
+		'{': `\{`, '}': `\}`, '#': `\#`, '%': `\%`, '&': `\&`, '_': `\_`, '$': `\$`,
+		'\\': `\backslash`,
+	}
+	// latexNary maps the n-ary operator characters to LaTeX commands.
+	latexNary = map[string]string{
+		"∑": `\sum`, "∏": `\prod`, "∐": `\coprod`, "∫": `\int`, "∬": `\iint`, "∭": `\iiint`,
+		"∮": `\oint`, "⋃": `\bigcup`, "⋂": `\bigcap`, "⋁": `\bigvee`, "⋀": `\bigwedge`,
+	}
+	// latexAccents maps the combining accent characters to LaTeX commands.
+	latexAccents = map[string]string{
+		"̂": `\hat`, "̃": `\tilde`, "̄": `\bar`, "̅": `\overline`,
+		"̇": `\dot`, "̈": `\ddot`, "⃗": `\vec`, "́": `\acute`,
+		"̀": `\grave`, "̆": `\breve`, "̌": `\check`,
+	}
+	// latexDelimiters maps the delimiter characters to LaTeX delimiters.
+	latexDelimiters = map[string]string{
+		"": ".", "{": `\{`, "}": `\}`, "⟨": `\langle`, "⟩": `\rangle`, "〈": `\langle`, "〉": `\rangle`,
+		"‖": `\|`, "⌈": `\lceil`, "⌉": `\rceil`, "⌊": `\lfloor`, "⌋": `\rfloor`,
+	}
+	// latexFuncs are the function names that have LaTeX commands.
+	latexFuncs = map[string]bool{
+		"sin": true, "cos": true, "tan": true, "cot": true, "sec": true, "csc": true,
+		"arcsin": true, "arccos": true, "arctan": true, "sinh": true, "cosh": true, "tanh": true,
+		"coth": true, "log": true, "ln": true, "lg": true, "exp": true, "lim": true, "max": true,
+		"min": true, "sup": true, "inf": true, "det": true, "gcd": true, "deg": true, "dim": true,
+		"ker": true, "arg": true, "Pr": true, "hom": true,
+	}
+)
+
+// OMMLToText converts an Office Math element(m:oMathPara or m:oMath) tree to text.
+//
+// In LaTeX mode, inline math is surrounded by "$" and each equation of display math(m:oMathPara)
+// by "$$". In linear mode, the equations are converted to the linear format of UnicodeMath.
+// In plain mode, the text of math runs are concatenated.
+//
+// Parameters:
+//   - n: the math element node, see ReadXMLElement.
+//   - mode: the math mode.
+//
+// Returns:
+//   - string: the converted text.
+func OMMLToText(n *XMLNode, mode types.MathMode) string {
+	if mode == types.MathPlain {
+		return n.TextContent()
+	}
+
+	w := ommlWriter{latex: mode == types.MathLaTeX}
+	if n.Name != "oMathPara" {
+		if w.latex {
+			return "$" + w.render(n) + "$"
+		}
+		return w.render(n)
+	}
+
+	lines := make([]string, 0, len(n.Children))
+	for _, child := range n.Children {
+		if child.Name != "oMath" {
+			continue
+		}
+		if w.latex {
+			lines = append(lines, "$$"+w.render(child)+"$$")
+		} else {
+			lines = append(lines, w.render(child))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// ommlWriter renders the OMML elements to LaTeX or linear format.
+type ommlWriter struct {
+	latex bool
+}
+
+// render renders the OMML element.
+func (w ommlWriter) render(n *XMLNode) string {
+	if n == nil {
+		return ""
+	}
+
+	switch n.Name {
+	case "r":
+		return w.run(n)
+	case "t":
+		return w.text(n.Text)
+	case "f":
+		return w.fraction(n)
+	case "sSup", "sSub", "sSubSup", "sPre":
+		return w.script(n)
+	case "rad":
+		return w.radical(n)
+	case "d":
+		return w.delimiter(n)
+	case "nary":
+		return w.nary(n)
+	case "func":
+		return w.function(n)
+	case "acc":
+		return w.accent(n)
+	case "bar":
+		return w.bar(n)
+	case "groupChr":
+		return w.groupChr(n)
+	case "limLow", "limUpp":
+		return w.limit(n)
+	case "m":
+		return w.matrix(n)
+	case "eqArr":
+		return w.eqArr(n)
+	case "borderBox":
+		if w.latex {
+			return `\boxed{` + w.children(n.Child("e")) + "}"
+		}
+		return "▭(" + w.children(n.Child("e")) + ")"
+	}
+
+	return w.children(n)
+}
+
+// children renders the children of the element except the properties.
+func (w ommlWriter) children(n *XMLNode) string {
+	if n == nil {
+		return ""
+	}
+
+	b := new(strings.Builder)
+	for _, child := range n.Children {
+		if strings.HasSuffix(child.Name, "Pr") {
+			continue
+		}
+		w.append(b, w.render(child))
+	}
+
+	return b.String()
+}
+
+// append appends s to b, a space is inserted between a LaTeX control word and a following letter.
+func (w ommlWriter) append(b *strings.Builder, s string) {
+	if w.latex && len(s) > 0 && isASCIILetter(s[0]) && endsWithControlWord(b.String()) {
+		b.WriteByte(' ')
+	}
+	b.WriteString(s)
+}
+
+// endsWithControlWord reports whether s ends with a LaTeX control word like \alpha.
+func endsWithControlWord(s string) bool {
+	i := len(s)
+	for i > 0 && isASCIILetter(s[i-1]) {
+		i--
+	}
+
+	return i < len(s) && i > 0 && s[i-1] == '\\'
+}
+
+// run renders the math run, normal text(m:nor) is rendered as \text{} in LaTeX.
+func (w ommlWriter) run(n *XMLNode) string {
+	var (
+		text   = new(strings.Builder)
+		normal = false
+	)
+	for _, child := range n.Children {
+		switch child.Name {
+		case "t":
+			text.WriteString(child.Text)
+		case "rPr":
+			// both m:rPr and w:rPr are named rPr, only m:rPr has m:nor.
+			normal = normal || onOffProp(child, "nor")
+		}
+	}
+
+	if w.latex && normal {
+		return `\text{` + text.String() + "}"
+	}
+
+	return w.text(text.String())
+}
+
+// text renders the text of math run.
+func (w ommlWriter) text(s string) string {
+	if !w.latex {
+		return s
+	}
+
+	b := new(strings.Builder)
+	for _, c := range s {
+		if cmd, ok := latexSymbols[c]; ok {
+			w.append(b, cmd)
+			continue
+		}
+		w.append(b, string(c))
+	}
+
+	return b.String()
+}
+
+// fraction renders the fraction(m:f).
+func (w ommlWriter) fraction(n *XMLNode) string {
+	var (
+		num = w.children(n.Child("num"))
+		den = w.children(n.Child("den"))
+		typ = valProp(n.Child("fPr"), "type", "bar")
+	)
+
+	if w.latex {
+		switch typ {
+		case "lin":
+			return latexGroup(num) + "/" + latexGroup(den)
+		case "noBar":
+			return `\genfrac{}{}{0pt}{}{` + num + "}{" + den + "}"
+		}
+		return `\frac{` + num + "}{" + den + "}"
+	}
+
+	if typ == "noBar" {
+		return "(" + num + "¦" + den + ")"
+	}
+	return linearGroup(num) + "/" + linearGroup(den)
+}
+
+// script renders the superscript(m:sSup), subscript(m:sSub), sub-superscript(m:sSubSup)
+// and pre-sub-superscript(m:sPre).
+func (w ommlWriter) script(n *XMLNode) string {
+	var (
+		e   = w.children(n.Child("e"))
+		sub = w.children(n.Child("sub"))
+		sup = w.children(n.Child("sup"))
+		b   = new(strings.Builder)
+	)
+
+	if n.Name == "sPre" {
+		if w.latex {
+			b.WriteString("{}")
+		}
+		w.writeScripts(b, sub, sup, n.Child("sub") != nil, n.Child("sup") != nil)
+		w.append(b, e)
+		return b.String()
+	}
+
+	if w.latex {
+		b.WriteString(latexGroup(e))
+	} else {
+		b.WriteString(linearGroup(e))
+	}
+	w.writeScripts(b, sub, sup, n.Child("sub") != nil, n.Child("sup") != nil)
+
+	return b.String()
+}
+
+// writeScripts writes the subscript and superscript to b.
+func (w ommlWriter) writeScripts(b *strings.Builder, sub, sup string, hasSub, hasSup bool) {
+	if hasSub {
+		b.WriteString("_")
+		if w.latex {
+			b.WriteString("{" + sub + "}")
+		} else {
+			b.WriteString(linearGroup(sub))
+		}
+	}
+	if hasSup {
+		b.WriteString("^")
+		if w.latex {
+			b.WriteString("{" + sup + "}")
+		} else {
+			b.WriteString(linearGroup(sup))
+		}
+	}
+}
+
+// radical renders the radical(m:rad).
+func (w ommlWriter) radical(n *XMLNode) string {
+	var (
+		e   = w.children(n.Child("e"))
+		deg = w.children(n.Child("deg"))
+	)
+	if onOffProp(n.Child("radPr"), "degHide") {
+		deg = ""
+	}
+
+	if w.latex {
+		if deg == "" {
+			return `\sqrt{` + e + "}"
+		}
+		return `\sqrt[` + deg + "]{" + e + "}"
+	}
+
+	switch deg {
+	case "":
+		return "√" + linearGroup(e)
+	case "3":
+		return "∛" + linearGroup(e)
+	case "4":
+		return "∜" + linearGroup(e)
+	}
+	return "√(" + deg + "&" + e + ")"
+}
+
+// delimiter renders the delimiter(m:d).
+func (w ommlWriter) delimiter(n *XMLNode) string {
+	var (
+		pr  = n.Child("dPr")
+		beg = valProp(pr, "begChr", "(")
+		end = valProp(pr, "endChr", ")")
+		sep = valProp(pr, "sepChr", "|")
+		es  = make([]string, 0, 1)
+	)
+	for _, child := range n.Children {
+		if child.Name == "e" {
+			es = append(es, w.children(child))
+		}
+	}
+
+	if w.latex {
+		sep = w.text(sep)
+		return `\left` + latexDelimiter(beg) + strings.Join(es, sep) + `\right` + latexDelimiter(end)
+	}
+	return beg + strings.Join(es, sep) + end
+}
+
+// nary renders the n-ary operator(m:nary) like summation and integral.
+func (w ommlWriter) nary(n *XMLNode) string {
+	var (
+		pr  = n.Child("naryPr")
+		chr = valProp(pr, "chr", "∫")
+		sub = w.children(n.Child("sub"))
+		sup = w.children(n.Child("sup"))
+		e   = w.children(n.Child("e"))
+		b   = new(strings.Builder)
+	)
+	hasSub := sub != "" && !onOffProp(pr, "subHide")
+	hasSup := sup != "" && !onOffProp(pr, "supHide")
+
+	if w.latex {
+		if cmd, ok := latexNary[chr]; ok {
+			b.WriteString(cmd)
+		} else {
+			b.WriteString(w.text(chr))
+		}
+		w.writeScripts(b, sub, sup, hasSub, hasSup)
+		b.WriteString("{" + e + "}")
+		return b.String()
+	}
+
+	b.WriteString(chr)
+	w.writeScripts(b, sub, sup, hasSub, hasSup)
+	b.WriteString("▒")
+	b.WriteString(linearGroup(e))
+
+	return b.String()
+}
+
+// function renders the function apply(m:func) like sin x.
+func (w ommlWriter) function(n *XMLNode) string {
+	var (
+		fName = n.Child("fName")
+		name  = w.children(fName)
+		e     = w.children(n.Child("e"))
+	)
+
+	if !w.latex {
+		if strings.HasPrefix(e, "(") {
+			return name + e
+		}
+		return name + " " + e
+	}
+
+	if plain := strings.TrimSpace(fName.TextContent()); plain == name {
+		if latexFuncs[plain] {
+			name = `\` + plain
+		} else {
+			name = `\operatorname{` + plain + "}"
+		}
+	}
+
+	return name + "{" + e + "}"
+}
+
+// accent renders the accent(m:acc).
+func (w ommlWriter) accent(n *XMLNode) string {
+	var (
+		chr = valProp(n.Child("accPr"), "chr", "̂")
+		e   = w.children(n.Child("e"))
+	)
+
+	if w.latex {
+		if cmd, ok := latexAccents[chr]; ok {
+			return cmd + "{" + e + "}"
+		}
+		return `\overset{` + w.text(chr) + "}{" + e + "}"
+	}
+
+	if utf8.RuneCountInString(e) == 1 {
+		return e + chr
+	}
+	return "(" + e + ")" + chr
+}
+
+// bar renders the overbar or underbar(m:bar).
+func (w ommlWriter) bar(n *XMLNode) string {
+	var (
+		pos = valProp(n.Child("barPr"), "pos", "bot")
+		e   = w.children(n.Child("e"))
+	)
+
+	switch {
+	case w.latex && pos == "top":
+		return `\overline{` + e + "}"
+	case w.latex:
+		return `\underline{` + e + "}"
+	case pos == "top":
+		return "¯(" + e + ")"
+	}
+	return "▁(" + e + ")"
+}
+
+// groupChr renders the group character(m:groupChr) like underbrace.
+func (w ommlWriter) groupChr(n *XMLNode) string {
+	var (
+		pr  = n.Child("groupChrPr")
+		chr = valProp(pr, "chr", "⏟")
+		pos = valProp(pr, "pos", "bot")
+		e   = w.children(n.Child("e"))
+	)
+
+	if !w.latex {
+		return chr + "(" + e + ")"
+	}
+
+	switch {
+	case chr == "⏟":
+		return `\underbrace{` + e + "}"
+	case chr == "⏞":
+		return `\overbrace{` + e + "}"
+	case pos == "top":
+		return `\overset{` + w.text(chr) + "}{" + e + "}"
+	}
+	return `\underset{` + w.text(chr) + "}{" + e + "}"
+}
+
+// limit renders the lower limit(m:limLow) and upper limit(m:limUpp).
+func (w ommlWriter) limit(n *XMLNode) string {
+	var (
+		e   = w.children(n.Child("e"))
+		lim = w.children(n.Child("lim"))
+	)
+
+	if !w.latex {
+		if n.Name == "limLow" {
+			return e + "┬" + linearGroup(lim)
+		}
+		return e + "┴" + linearGroup(lim)
+	}
+
+	if n.Name == "limUpp" {
+		return `\overset{` + lim + "}{" + e + "}"
+	}
+	if latexFuncs[e] {
+		return `\` + e + "_{" + lim + "}"
+	}
+	return `\underset{` + lim + "}{" + e + "}"
+}
+
+// matrix renders the matrix(m:m).
+func (w ommlWriter) matrix(n *XMLNode) string {
+	rows := make([]string, 0, len(n.Children))
+	for _, mr := range n.Children {
+		if mr.Name != "mr" {
+			continue
+		}
+		cells := make([]string, 0, len(mr.Children))
+		for _, e := range mr.Children {
+			if e.Name == "e" {
+				cells = append(cells, w.children(e))
+			}
+		}
+		if w.latex {
+			rows = append(rows, strings.Join(cells, " & "))
+		} else {
+			rows = append(rows, strings.Join(cells, "&"))
+		}
+	}
+
+	if w.latex {
+		return `\begin{matrix}` + strings.Join(rows, ` \\ `) + `\end{matrix}`
+	}
+	return "■(" + strings.Join(rows, "@") + ")"
+}
+
+// eqArr renders the equation array(m:eqArr).
+func (w ommlWriter) eqArr(n *XMLNode) string {
+	rows := make([]string, 0, len(n.Children))
+	for _, e := range n.Children {
+		if e.Name == "e" {
+			rows = append(rows, w.children(e))
+		}
+	}
+
+	if w.latex {
+		return `\begin{array}{l}` + strings.Join(rows, ` \\ `) + `\end{array}`
+	}
+	return "█(" + strings.Join(rows, "@") + ")"
+}
+
+// valProp returns the m:val of the property element of pr, or the default value if not specified.
+func valProp(pr *XMLNode, name, dflt string) string {
+	if v, ok := pr.Child(name).Attr("val"); ok {
+		return v
+	}
+	return dflt
+}
+
+// onOffProp reports whether the on/off property element of pr is on.
+func onOffProp(pr *XMLNode, name string) bool {
+	prop := pr.Child(name)
+	if prop == nil {
+		return false
+	}
+	v, ok := prop.Attr("val")
+	return !ok || v == "1" || v == "on" || v == "true"
+}
+
+// latexDelimiter returns the LaTeX delimiter of the character.
+func latexDelimiter(chr string) string {
+	if d, ok := latexDelimiters[chr]; ok {
+		return d
+	}
+	return chr
+}
+
+// latexGroup groups the LaTeX expression by braces unless it is a single character.
+func latexGroup(s string) string {
+	if utf8.RuneCountInString(s) == 1 {
+		return s
+	}
+	return "{" + s + "}"
+}
+
+// linearGroup parenthesizes the linear expression unless it is a single character,
+// a number or already enclosed in brackets.
+func linearGroup(s string) string {
+	if utf8.RuneCountInString(s) <= 1 || isBracketed(s) {
+		return s
+	}
+	for _, c := range s {
+		if !unicode.IsDigit(c) && c != '.' {
+			return "(" + s + ")"
+		}
+	}
+	return s
+}
+
+// isBracketed reports whether the whole expression is enclosed in a pair of brackets.
+func isBracketed(s string) bool {
+	closing := map[byte]byte{'(': ')', '[': ']', '{': '}'}
+	if len(s) < 2 || closing[s[0]] == 0 || s[len(s)-1] != closing[s[0]] {
+		return false
+	}
+
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case s[0]:
+			depth++
+		case closing[s[0]]:
+			depth--
+			if depth == 0 && i < len(s)-1 {
+				return false
+			}
+		}
+	}
+
+	return depth == 0
+}
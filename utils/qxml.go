@@ -7,6 +7,7 @@ package utils
 
 import (
 	"archive/zip"
+	"html"
 	"path"
 	"regexp"
 	"strings"
@@ -136,3 +137,105 @@ func FindNameIterTo(r *qxml.Reader, name string, to string) bool {
 
 	return false
 }
+
+// SkipElement skips the children of the given start element until its matching end element.
+// Nested elements with the same name are taken into account.
+//
+// Parameters:
+//   - r: a pointer to the qxml Reader.
+//   - e: the start element that has been read.
+func SkipElement(r *qxml.Reader, e *qxml.StartElement) {
+	if e.HasEnd() {
+		return
+	}
+	SkipToEnd(r, e.Name())
+}
+
+// SkipToEnd skips the elements until the end element of the given name, whose start element has been read.
+// Nested elements with the same name are taken into account.
+//
+// Parameters:
+//   - r: a pointer to the qxml Reader.
+//   - name: the name of the element to skip to the end of.
+func SkipToEnd(r *qxml.Reader, name string) {
+	depth := 1
+	for r.Next() {
+		switch e := r.Element().(type) {
+		case *qxml.StartElement:
+			if !e.HasEnd() && e.Name() == name {
+				depth++
+			}
+		case *qxml.EndElement:
+			if e.Name() == name {
+				depth--
+				if depth == 0 {
+					return
+				}
+			}
+		}
+	}
+}
+
+// AltContent tracks the nested markup compatibility alternate contents(mc:AlternateContent). The fallback
+// content(mc:Fallback) duplicates the choice content(mc:Choice), so it is skipped only if the choice content
+// has been handled by the parser, like the Office Math(a14:m) of PPTX, otherwise the text which exists only
+// in the fallback is kept.
+type AltContent []bool
+
+// Start tracks the start element of an alternate content.
+func (ac *AltContent) Start(e *qxml.StartElement) {
+	if !e.HasEnd() {
+		*ac = append(*ac, false)
+	}
+}
+
+// End tracks the end element of an alternate content.
+func (ac *AltContent) End() {
+	if n := len(*ac); n > 0 {
+		*ac = (*ac)[:n-1]
+	}
+}
+
+// Handled marks the choice content of the innermost alternate content as handled.
+func (ac AltContent) Handled() {
+	if n := len(ac); n > 0 {
+		ac[n-1] = true
+	}
+}
+
+// SkipFallback skips the fallback element if the choice content of the innermost alternate content has been handled.
+//
+// Parameters:
+//   - r: a pointer to the qxml Reader.
+//   - e: the fallback start element that has been read.
+//
+// Returns:
+//   - bool: true if the fallback is skipped.
+func (ac AltContent) SkipFallback(r *qxml.Reader, e *qxml.StartElement) bool {
+	if n := len(ac); n == 0 || !ac[n-1] {
+		return false
+	}
+	SkipElement(r, e)
+	return true
+}
+
+// DrawingAltText returns the accessibility alt text of the non-visual drawing properties element,
+// like wp:docPr, p:cNvPr and xdr:cNvPr.
+//
+// Parameters:
+//   - e: the start element of the non-visual drawing properties.
+//
+// Returns:
+//   - title: the unescaped title attribute.
+//   - descr: the unescaped descr attribute.
+func DrawingAltText(e *qxml.StartElement) (title, descr string) {
+	attrs := e.Attrs()
+	if kv := attrs.Get("title"); kv != nil {
+		title = html.UnescapeString(kv.Value())
+	}
+	if kv := attrs.Get("descr"); kv != nil {
+		descr = html.UnescapeString(kv.Value())
+	}
+
+	return title, descr
+}
@@ -0,0 +1,181 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package utils
+
+import (
+	"strconv"
+	"strings"
+)
+
+var (
+	// rtfSkipDestinations are the destinations whose content is not text.
+	rtfSkipDestinations = map[string]bool{
+		"fonttbl": true, "colortbl": true, "stylesheet": true, "info": true,
+		"pict": true, "listtable": true, "listoverridetable": true, "rsidtbl": true,
+		"generator": true, "themedata": true, "colorschememapping": true,
+		"latentstyles": true, "datastore": true, "xmlnstbl": true, "fldinst": true,
+		"header": true, "headerl": true, "headerr": true, "headerf": true,
+		"footer": true, "footerl": true, "footerr": true, "footerf": true,
+		"filetbl": true, "revtbl": true, "objdata": true, "bkmkstart": true, "bkmkend": true,
+	}
+	// rtfSymbols are the control words that stand for a text symbol.
+	rtfSymbols = map[string]string{
+		"par": "\n", "line": "\n", "sect": "\n", "page": "\n", "row": "\n",
+		"tab": "\t", "cell": "\t",
+		"emdash": "—", "endash": "–", "bullet": "•", "emspace": " ", "enspace": " ",
+		"lquote": "‘", "rquote": "’", "ldblquote": "“", "rdblquote": "”",
+	}
+	// cp1252 maps the bytes 0x80-0x9F of windows-1252 code page to unicode.
+	cp1252 = [32]rune{
+		'€', 0x81, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0x8D, 'Ž', 0x8F,
+		0x90, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0x9D, 'ž', 'Ÿ',
+	}
+)
+
+// rtfState is the state of a rtf group.
+type rtfState struct {
+	skip bool
+	uc   int
+}
+
+// RTFToText converts rich text format to plain text.
+//
+// Text of unicode control words(\uN) is decoded, and the hex escaped bytes(\'hh)
+// are decoded as windows-1252 code page.
+//
+// Parameters:
+//   - data: the rtf content.
+//
+// Returns:
+//   - string: the plain text with paragraphs separated by "\n".
+func RTFToText(data []byte) string {
+	var (
+		texts   = new(strings.Builder)
+		state   = rtfState{uc: 1}
+		stack   = make([]rtfState, 0, 16)
+		pending = 0 // chars to skip after \uN
+		n       = len(data)
+	)
+
+	emit := func(s string) {
+		if pending > 0 {
+			pending--
+			return
+		}
+		if !state.skip {
+			texts.WriteString(s)
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		c := data[i]
+		switch c {
+		case '{':
+			stack = append(stack, state)
+			pending = 0
+
+		case '}':
+			if len(stack) > 0 {
+				state = stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+			}
+			pending = 0
+
+		case '\r', '\n':
+
+		case '\\':
+			if i+1 >= n {
+				break
+			}
+			c = data[i+1]
+			switch {
+			case c == '\\' || c == '{' || c == '}':
+				emit(string(c))
+				i++
+			case c == '~':
+				emit(" ")
+				i++
+			case c == '_':
+				emit("-")
+				i++
+			case c == '-':
+				i++
+			case c == '*':
+				state.skip = true
+				i++
+			case c == '\r' || c == '\n':
+				emit("\n")
+				i++
+			case c == '\'':
+				if i+3 < n {
+					if b, err := strconv.ParseUint(string(data[i+2:i+4]), 16, 8); err == nil {
+						switch {
+						case b >= 0x80 && b < 0xA0:
+							emit(string(cp1252[b-0x80]))
+						default:
+							emit(string(rune(b)))
+						}
+					}
+				}
+				i += 3
+			case isASCIILetter(c):
+				j := i + 1
+				for j < n && isASCIILetter(data[j]) {
+					j++
+				}
+				word := string(data[i+1 : j])
+				k := j
+				if k < n && (data[k] == '-' || isASCIIDigit(data[k])) {
+					k++
+					for k < n && isASCIIDigit(data[k]) {
+						k++
+					}
+				}
+				param, hasParam := 0, k > j
+				if hasParam {
+					param, _ = strconv.Atoi(string(data[j:k]))
+				}
+				// a space delimiter is part of the control word.
+				if k < n && data[k] == ' ' {
+					k++
+				}
+				i = k - 1
+
+				switch {
+				case word == "u" && hasParam:
+					if param < 0 {
+						param += 65536
+					}
+					emit(string(rune(param)))
+					pending = state.uc
+				case word == "uc" && hasParam:
+					state.uc = param
+				case rtfSkipDestinations[word]:
+					state.skip = true
+				default:
+					if s, ok := rtfSymbols[word]; ok {
+						emit(s)
+					}
+				}
+			default:
+				i++
+			}
+
+		default:
+			emit(string(data[i : i+1]))
+		}
+	}
+
+	return texts.String()
+}
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isASCIIDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
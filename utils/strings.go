@@ -5,7 +5,13 @@
 
 package utils
 
-import "bytes"
+import (
+	"bytes"
+	"strings"
+	"unicode"
+
+	"github.com/young2j/oxmltotext/types"
+)
 
 // MaxLineLen returns the maximum line length in a given string.
 //
@@ -74,3 +80,111 @@ func MaxLineLenWithPrefix(s string, prefix []byte) (string, int) {
 
 	return buf.String(), maxLen
 }
+
+// JoinAltText joins the title and the description of the drawing alt text,
+// the title is dropped if it is the same as the description.
+//
+// Parameters:
+//   - title: the title of the drawing.
+//   - descr: the description of the drawing.
+//
+// Returns:
+//   - string: the alt text, empty if both are empty.
+func JoinAltText(title, descr string) string {
+	title, descr = strings.TrimSpace(title), strings.TrimSpace(descr)
+	switch {
+	case title == "" || title == descr:
+		return descr
+	case descr == "":
+		return title
+	}
+
+	return title + "\n" + descr
+}
+
+// FormatBox formats the text in a box outline with the label on the top border,
+// like the drawings text. The text is returned as is when noFmt is true.
+//
+// Parameters:
+//   - text: the text to format.
+//   - label: the label of the box.
+//   - noFmt: true to write the text without outline.
+//
+// Returns:
+//   - *strings.Builder: the formatted text.
+func FormatBox(text, label string, noFmt bool) *strings.Builder {
+	var (
+		fmtTexts = new(strings.Builder)
+		lineSep  = "\n"
+	)
+	if noFmt {
+		fmtTexts.WriteString(text)
+		fmtTexts.WriteString(lineSep)
+		return fmtTexts
+	}
+
+	var (
+		newText, maxLineLen = MaxLineLenWithPrefix(text, []byte(" "))
+		halfLine            = bytes.Repeat([]byte("─"), max((maxLineLen-len(label))/2, 0))
+	)
+	fmtTexts.WriteString("┌")
+	fmtTexts.Write(halfLine)
+	fmtTexts.WriteString(label)
+	fmtTexts.Write(halfLine)
+	fmtTexts.WriteString("┐")
+	fmtTexts.WriteString(lineSep)
+
+	fmtTexts.WriteString(newText)
+	fmtTexts.WriteString(lineSep)
+
+	fmtTexts.WriteString("└")
+	fmtTexts.Write(halfLine)
+	fmtTexts.WriteString(strings.Repeat("─", len(label)))
+	fmtTexts.Write(halfLine)
+	fmtTexts.WriteString("┘")
+	fmtTexts.WriteString(lineSep)
+
+	return fmtTexts
+}
+
+// HasEastAsian reports whether the text contains East Asian characters(Han, Hiragana, Katakana or Hangul),
+// which are the characters using the East Asian language of run properties.
+//
+// Parameters:
+//   - text: the text to check.
+//
+// Returns:
+//   - bool: true if any East Asian character is found.
+func HasEastAsian(text string) bool {
+	for _, c := range text {
+		if unicode.In(c, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AppendSpan appends the span to spans, the span is merged into the last span if they have the same properties.
+//
+// Parameters:
+//   - spans: the spans of a paragraph.
+//   - span: the span to append.
+//
+// Returns:
+//   - []types.Span: the spans after appending.
+func AppendSpan(spans []types.Span, span types.Span) []types.Span {
+	if span.Text == "" {
+		return spans
+	}
+	if n := len(spans); n > 0 {
+		last := spans[n-1]
+		last.Text = span.Text
+		if last == span {
+			spans[n-1].Text += span.Text
+			return spans
+		}
+	}
+
+	return append(spans, span)
+}
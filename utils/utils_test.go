@@ -8,7 +8,12 @@ package utils
 import (
 	"mime"
 	"os"
+	"strings"
 	"testing"
+
+	"github.com/young2j/oxmltotext/types"
+
+	qxml "github.com/dgrr/quickxml"
 )
 
 func TestCreateTempFile(t *testing.T) {
@@ -65,3 +70,65 @@ func TestFormatTarget(t *testing.T) {
 		t.Error(p)
 	}
 }
+
+func TestHTMLToText(t *testing.T) {
+	want := "Title & more\nHello world, again\na\tb\nend\n"
+	text := HTMLToText(`<html><head><title>x</title></head><body><h1>Title &amp; more</h1>` +
+		`<p>Hello   <b>world</b>, <i>again</i></p><table><tr><td>a</td><td> b </td></tr></table><!-- c --><br>end</body></html>`)
+	if text != want {
+		t.Errorf("%q", text)
+	}
+}
+
+func TestRTFToText(t *testing.T) {
+	want := "caf\u00e9 \u20ac\nline"
+	text := RTFToText([]byte(`{\rtf1\ansi{\fonttbl{\f0 Calibri;}}{\*\generator x;}\pard caf\'e9 \u8364?\par line}`))
+	if text != want {
+		t.Errorf("%q", text)
+	}
+}
+
+func TestOMMLToText(t *testing.T) {
+	cases := []struct {
+		omml   string
+		latex  string
+		linear string
+	}{
+		{
+			omml:   `<m:oMath><m:r><m:t>x=</m:t></m:r><m:f><m:num><m:r><m:t>−b±</m:t></m:r><m:rad><m:radPr><m:degHide m:val="1"/></m:radPr><m:deg/><m:e><m:sSup><m:e><m:r><m:t>b</m:t></m:r></m:e><m:sup><m:r><m:t>2</m:t></m:r></m:sup></m:sSup><m:r><m:t>−4ac</m:t></m:r></m:e></m:rad></m:num><m:den><m:r><m:t>2a</m:t></m:r></m:den></m:f></m:oMath>`,
+			latex:  `$x=\frac{-b\pm\sqrt{b^{2}-4ac}}{2a}$`,
+			linear: `x=(−b±√(b^2−4ac))/(2a)`,
+		},
+		{
+			omml:   `<m:oMath><m:nary><m:naryPr><m:chr m:val="∑"/><m:limLoc m:val="undOvr"/></m:naryPr><m:sub><m:r><m:t>i=1</m:t></m:r></m:sub><m:sup><m:r><m:t>n</m:t></m:r></m:sup><m:e><m:sSub><m:e><m:r><m:t>x</m:t></m:r></m:e><m:sub><m:r><m:t>i</m:t></m:r></m:sub></m:sSub></m:e></m:nary></m:oMath>`,
+			latex:  `$\sum_{i=1}^{n}{x_{i}}$`,
+			linear: `∑_(i=1)^n▒(x_i)`,
+		},
+		{
+			omml:   `<m:oMath><m:d><m:dPr><m:begChr m:val="["/><m:endChr m:val="]"/></m:dPr><m:e><m:m><m:mr><m:e><m:r><m:t>a</m:t></m:r></m:e><m:e><m:r><m:t>b</m:t></m:r></m:e></m:mr><m:mr><m:e><m:r><m:t>c</m:t></m:r></m:e><m:e><m:r><m:t>d</m:t></m:r></m:e></m:mr></m:m></m:e></m:d></m:oMath>`,
+			latex:  `$\left[\begin{matrix}a & b \\ c & d\end{matrix}\right]$`,
+			linear: `[■(a&b@c&d)]`,
+		},
+		{
+			omml:   `<m:oMath><m:func><m:fName><m:r><m:t>sin</m:t></m:r></m:fName><m:e><m:r><m:t>θ</m:t></m:r></m:e></m:func><m:r><m:t>+</m:t></m:r><m:func><m:fName><m:limLow><m:e><m:r><m:t>lim</m:t></m:r></m:e><m:lim><m:r><m:t>n→∞</m:t></m:r></m:lim></m:limLow></m:fName><m:e><m:sSup><m:e><m:d><m:e><m:r><m:t>1+</m:t></m:r><m:f><m:num><m:r><m:t>1</m:t></m:r></m:num><m:den><m:r><m:t>n</m:t></m:r></m:den></m:f></m:e></m:d></m:e><m:sup><m:r><m:t>n</m:t></m:r></m:sup></m:sSup></m:e></m:func></m:oMath>`,
+			latex:  `$\sin{\theta}+\lim_{n\rightarrow\infty}{{\left(1+\frac{1}{n}\right)}^{n}}$`,
+			linear: `sin θ+lim┬(n→∞)(1+1/n)^n`,
+		},
+	}
+
+	for _, c := range cases {
+		r := qxml.NewReader(strings.NewReader(c.omml))
+		if !r.Next() {
+			t.Fatal(r.Error())
+		}
+		math := ReadXMLElement(r, r.Element().(*qxml.StartElement))
+
+		if latex := OMMLToText(math, types.MathLaTeX); latex != c.latex {
+			t.Errorf("latex: got %s, want %s", latex, c.latex)
+		}
+		if linear := OMMLToText(math, types.MathLinear); linear != c.linear {
+			t.Errorf("linear: got %s, want %s", linear, c.linear)
+		}
+		t.Log(OMMLToText(math, types.MathPlain))
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package utils
+
+import (
+	"html"
+	"strings"
+
+	qxml "github.com/dgrr/quickxml"
+)
+
+// XMLNode is an element of a xml tree, the namespace prefixes of names are dropped.
+type XMLNode struct {
+	Name     string
+	Attrs    map[string]string
+	Text     string
+	Children []*XMLNode
+}
+
+// LocalName returns the name without namespace prefix.
+func LocalName(name string) string {
+	if i := strings.IndexByte(name, ':'); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+// newXMLNode creates a node from the start element with its attributes unescaped.
+func newXMLNode(e *qxml.StartElement) *XMLNode {
+	node := &XMLNode{Name: LocalName(e.Name())}
+	attrs := e.Attrs()
+	if attrs.Len() > 0 {
+		node.Attrs = make(map[string]string, attrs.Len())
+		attrs.Range(func(kv *qxml.KV) {
+			node.Attrs[LocalName(kv.Key())] = html.UnescapeString(kv.Value())
+		})
+	}
+
+	return node
+}
+
+// ParseXMLTree parses the whole xml document from the reader into a tree.
+//
+// Parameters:
+//   - r: a pointer to the qxml Reader.
+//
+// Returns:
+//   - *XMLNode: the document node whose child is the root element.
+func ParseXMLTree(r *qxml.Reader) *XMLNode {
+	doc := new(XMLNode)
+	readXMLChildren(r, doc, "")
+
+	return doc
+}
+
+// ReadXMLElement reads the element whose start element has been read into a tree.
+//
+// Parameters:
+//   - r: a pointer to the qxml Reader.
+//   - e: the start element that has been read.
+//
+// Returns:
+//   - *XMLNode: the element node.
+func ReadXMLElement(r *qxml.Reader, e *qxml.StartElement) *XMLNode {
+	node := newXMLNode(e)
+	if !e.HasEnd() {
+		readXMLChildren(r, node, e.Name())
+	}
+
+	return node
+}
+
+// readXMLChildren reads the children of the node until the end element of the given name.
+func readXMLChildren(r *qxml.Reader, node *XMLNode, end string) {
+	stack := []*XMLNode{node}
+
+	for r.Next() {
+		switch e := r.Element().(type) {
+		case *qxml.StartElement:
+			child := newXMLNode(e)
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, child)
+			if !e.HasEnd() {
+				stack = append(stack, child)
+			}
+
+		case *qxml.TextElement:
+			stack[len(stack)-1].Text += html.UnescapeString(e.String())
+
+		case *qxml.EndElement:
+			if len(stack) == 1 {
+				if e.Name() == end {
+					return
+				}
+				continue
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+}
+
+// Child returns the first child with the given local name, or nil if not found.
+func (n *XMLNode) Child(name string) *XMLNode {
+	if n == nil {
+		return nil
+	}
+	for _, child := range n.Children {
+		if child.Name == name {
+			return child
+		}
+	}
+
+	return nil
+}
+
+// Attr returns the attribute value of the given local name.
+func (n *XMLNode) Attr(name string) (string, bool) {
+	if n == nil {
+		return "", false
+	}
+	v, ok := n.Attrs[name]
+	return v, ok
+}
+
+// TextContent returns the concatenated text of the node and its descendants.
+func (n *XMLNode) TextContent() string {
+	if n == nil {
+		return ""
+	}
+	if len(n.Children) == 0 {
+		return n.Text
+	}
+
+	text := new(strings.Builder)
+	text.WriteString(n.Text)
+	for _, child := range n.Children {
+		text.WriteString(child.TextContent())
+	}
+
+	return text.String()
+}
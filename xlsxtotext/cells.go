@@ -0,0 +1,326 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package xlsxtotext
+
+import (
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/young2j/oxmltotext/types"
+)
+
+// parseCellRef parses the A1 style cell reference, "$" of absolute references is ignored.
+//
+// Parameters:
+//   - ref: the cell reference like "B3", "$B$3", "B" or "3".
+//
+// Returns:
+//   - col: the column number(start 1), 0 if the reference has no column.
+//   - row: the row number(start 1), 0 if the reference has no row.
+func parseCellRef(ref string) (col, row int) {
+	for i := 0; i < len(ref); i++ {
+		c := ref[i]
+		switch {
+		case c == '$':
+		case c >= 'A' && c <= 'Z':
+			col = col*26 + int(c-'A') + 1
+		case c >= 'a' && c <= 'z':
+			col = col*26 + int(c-'a') + 1
+		case c >= '0' && c <= '9':
+			row = row*10 + int(c-'0')
+		default:
+			return 0, 0
+		}
+	}
+
+	return col, row
+}
+
+// parseRangeRef parses the A1 style range reference like "A1:C3", a single cell reference like "B2" is
+// the range of the cell.
+//
+// Parameters:
+//   - ref: the range reference.
+//
+// Returns:
+//   - col1, row1: the column and row numbers of the top left cell, 0 if the reference is invalid.
+//   - col2, row2: the column and row numbers of the bottom right cell, 0 if the reference is invalid.
+func parseRangeRef(ref string) (col1, row1, col2, row2 int) {
+	start, end, ok := strings.Cut(ref, ":")
+	if !ok {
+		end = start
+	}
+	col1, row1 = parseCellRef(start)
+	col2, row2 = parseCellRef(end)
+	if col1 > col2 {
+		col1, col2 = col2, col1
+	}
+	if row1 > row2 {
+		row1, row2 = row2, row1
+	}
+
+	return col1, row1, col2, row2
+}
+
+// columnName returns the letters of the column number(start 1), like "A", "Z", "AA" and "XFD".
+func columnName(col int) string {
+	var name []byte
+	for col > 0 {
+		col--
+		name = append([]byte{byte('A' + col%26)}, name...)
+		col /= 26
+	}
+
+	return string(name)
+}
+
+// cellText returns the text of the cell value by the cell type(c@t) and the number format of the cell style(c@s).
+//
+// The shared strings(s) are resolved by their indexes, the booleans(b) are rendered as TRUE/FALSE,
+// the formula strings(str) and errors(e, like #N/A) are unescaped, and the numbers(n, the default)
+// and ISO 8601 dates(d) are formatted as Excel displays them unless the raw values are preferred,
+// see SetRawValues. The inline strings(inlineStr) are read from c/is instead.
+//
+// Parameters:
+//   - typ: the cell type.
+//   - v: the cell value(c/v).
+//   - style: the cell style, the index of the cell format.
+//
+// Returns:
+//   - string: the text of the cell.
+func (xp *XlsxParser) cellText(typ, v string, style int) string {
+	switch typ {
+	case "s":
+		i, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil || i < 0 || i >= len(xp.sharedStrings) {
+			return ""
+		}
+		return xp.formatText(xp.sharedStrings[i], style)
+	case "b":
+		switch strings.TrimSpace(v) {
+		case "1", "true":
+			return "TRUE"
+		case "0", "false":
+			return "FALSE"
+		}
+		return v
+	case "str", "inlineStr":
+		return xp.formatText(html.UnescapeString(v), style)
+	case "e":
+		return html.UnescapeString(v)
+	case "d":
+		if xp.rawValues {
+			return v
+		}
+		t, err := time.Parse("2006-01-02T15:04:05", strings.TrimSuffix(strings.TrimSpace(v), "Z"))
+		if err != nil {
+			return v
+		}
+		if nf := xp.styles.numFmt(style); nf != nil && nf.isDate() {
+			return nf.format(timeSerial(t, xp.workbook.date1904), xp.workbook.date1904)
+		}
+		return v
+	}
+
+	if xp.rawValues {
+		return v
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+	if err != nil {
+		return v
+	}
+	if nf := xp.styles.numFmt(style); nf != nil {
+		return nf.format(f, xp.workbook.date1904)
+	}
+
+	return formatGeneral(f)
+}
+
+// typedCell sets the type and the typed value of the cell by the cell type(c@t) and the number format of
+// the cell style(c@s), the numbers with date formats are dates and the empty numbers are empty.
+//
+// Parameters:
+//   - c: the cell to set.
+//   - typ: the cell type.
+//   - v: the cell value(c/v), empty for the inline strings.
+//   - style: the cell style, the index of the cell format.
+func (xp *XlsxParser) typedCell(c *types.Cell, typ, v string, style int) {
+	switch typ {
+	case "s", "str", "inlineStr":
+		c.Type = types.CellString
+		return
+	case "b":
+		c.Type = types.CellBool
+		c.Bool = strings.TrimSpace(v) == "1" || strings.TrimSpace(v) == "true"
+		return
+	case "e":
+		c.Type = types.CellError
+		return
+	case "d":
+		t, err := time.Parse("2006-01-02T15:04:05", strings.TrimSuffix(strings.TrimSpace(v), "Z"))
+		if err != nil {
+			c.Type = types.CellString
+			return
+		}
+		c.Type = types.CellDate
+		c.Time = t
+		c.Number = timeSerial(t, xp.workbook.date1904)
+		return
+	}
+
+	if strings.TrimSpace(v) == "" {
+		return
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+	if err != nil {
+		c.Type = types.CellString
+		return
+	}
+	c.Number = f
+	if nf := xp.styles.numFmt(style); nf != nil && nf.isDate() {
+		if t, ok := serialTime(f, xp.workbook.date1904); ok {
+			c.Type = types.CellDate
+			c.Time = t
+			return
+		}
+	}
+	c.Type = types.CellNumber
+}
+
+// formatText formats the string by the text section(@) of the number format of the cell style.
+func (xp *XlsxParser) formatText(s string, style int) string {
+	if xp.rawValues {
+		return s
+	}
+	if nf := xp.styles.numFmt(style); nf != nil {
+		return nf.formatText(s)
+	}
+
+	return s
+}
+
+// phoneticText returns the unescaped text of the string with the phonetic guides in parentheses if enabled.
+func (xp *XlsxParser) phoneticText(text, phonetic string) string {
+	if xp.parsePhonetic && phonetic != "" {
+		text += "(" + phonetic + ")"
+	}
+
+	return html.UnescapeString(text)
+}
+
+// sheetRow is the buffer of the cell values of a row, placed by their column numbers.
+type sheetRow struct {
+	num   int          // the row number(start 1)
+	col   int          // the column number of the last cell
+	cells []string     // the cell values, cells[0] is column A
+	typed []types.Cell // the typed cells, typed[0] is column A, only read for the structured cells
+}
+
+// reset resets the row buffer for the row number.
+func (sr *sheetRow) reset(num int) {
+	sr.num = num
+	sr.col = 0
+	sr.cells = sr.cells[:0]
+	sr.typed = sr.typed[:0]
+}
+
+// set sets the value of the cell at the column number(start 1).
+func (sr *sheetRow) set(col int, v string) {
+	for len(sr.cells) < col {
+		sr.cells = append(sr.cells, "")
+	}
+	sr.cells[col-1] = v
+}
+
+// setTyped sets the typed cell at the column number(start 1).
+func (sr *sheetRow) setTyped(col int, c types.Cell) {
+	for len(sr.typed) < col {
+		sr.typed = append(sr.typed, types.Cell{})
+	}
+	sr.typed[col-1] = c
+}
+
+// empty reports whether all the cells of the row are empty.
+func (sr *sheetRow) empty() bool {
+	for _, v := range sr.cells {
+		if v != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// write writes the cell values of the row separated by colSep.
+//
+// Parameters:
+//   - w: the builder to write to.
+//   - colSep: the separator of the columns.
+//   - padGaps: writes separators for empty cells to keep the values in their columns or collapses them.
+//   - trim: trims the trailing empty cells.
+//   - maxWidth: the max number of characters of a cell value, the characters beyond it are cut, 0 if no limit.
+func (sr *sheetRow) write(w *strings.Builder, colSep string, padGaps, trim bool, maxWidth int) {
+	cells := sr.cells
+	if trim {
+		for len(cells) > 0 && cells[len(cells)-1] == "" {
+			cells = cells[:len(cells)-1]
+		}
+	}
+
+	first := true
+	for _, v := range cells {
+		if v == "" && !padGaps {
+			continue
+		}
+		if !first {
+			w.WriteString(colSep)
+		}
+		w.WriteString(truncateWidth(v, maxWidth))
+		first = false
+	}
+}
+
+// truncateWidth returns the first n characters(runes) of v, or v if n <= 0 or v is not longer than n.
+func truncateWidth(v string, n int) string {
+	if n <= 0 || len(v) <= n {
+		return v
+	}
+	for i := range v {
+		if n == 0 {
+			return v[:i]
+		}
+		n--
+	}
+
+	return v
+}
+
+// rowWriter writes the rows of a sheet to the texts, the skipped and empty rows are written as empty rows
+// before the next non-empty row, see SetPadGaps and SetTrimEmpty.
+type rowWriter struct {
+	xp        *XlsxParser
+	texts     *strings.Builder
+	prevRow   int // the number of the previous row
+	blankRows int // the number of the empty rows not written yet
+}
+
+// write writes the row to the texts.
+func (rw *rowWriter) write(row *sheetRow) {
+	xp := rw.xp
+	if xp.padGaps && row.num > rw.prevRow+1 {
+		rw.blankRows += row.num - rw.prevRow - 1
+	}
+	rw.prevRow = row.num
+	if row.empty() && xp.trimEmpty {
+		rw.blankRows++
+		return
+	}
+	rw.texts.WriteString(strings.Repeat(xp.rowSep, rw.blankRows))
+	rw.blankRows = 0
+	row.write(rw.texts, xp.colSep, xp.padGaps, xp.trimEmpty, xp.maxColumnWidth)
+	rw.texts.WriteString(xp.rowSep)
+}
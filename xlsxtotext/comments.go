@@ -0,0 +1,404 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package xlsxtotext
+
+import (
+	"html"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/young2j/oxmltotext/opc"
+	"github.com/young2j/oxmltotext/types"
+
+	qxml "github.com/dgrr/quickxml"
+)
+
+// sheetComments is the comments of a worksheet written inline after the annotated cells, see SetInlineComments.
+type sheetComments struct {
+	rows    map[int][]cellComment // the comments keyed by the row numbers
+	rowNums []int                 // the row numbers with comments in ascending order
+	cursor  int                   // the index of rowNums of the next row not read
+	maxRow  int                   // the last row with comments
+}
+
+// cellComment is the inline text of the comments of a cell.
+type cellComment struct {
+	col  int
+	text string
+}
+
+// newSheetComments returns the comments of the cells to be written inline, nil if there are no comments.
+func newSheetComments(comments []types.Comment) *sheetComments {
+	if len(comments) == 0 {
+		return nil
+	}
+
+	sc := &sheetComments{rows: make(map[int][]cellComment, len(comments))}
+	for _, c := range comments {
+		text := new(strings.Builder)
+		text.WriteString(types.CommentMarkStart)
+		writeInlineComment(text, c)
+		for _, reply := range c.Replies {
+			text.WriteString(types.CommentReplySep)
+			writeInlineComment(text, reply)
+		}
+		text.WriteString(types.CommentMarkEnd)
+		if _, ok := sc.rows[c.Row]; !ok {
+			sc.rowNums = append(sc.rowNums, c.Row)
+		}
+		sc.rows[c.Row] = append(sc.rows[c.Row], cellComment{col: c.Col, text: text.String()})
+		sc.maxRow = max(sc.maxRow, c.Row)
+	}
+	sort.Ints(sc.rowNums)
+
+	return sc
+}
+
+// writeInlineComment writes the author and the text of the comment in a line.
+func writeInlineComment(w *strings.Builder, c types.Comment) {
+	if c.Author != "" {
+		w.WriteString(c.Author)
+		w.WriteString(": ")
+	}
+	w.WriteString(strings.Join(strings.Fields(c.Text), " "))
+}
+
+// commentedRow returns the first row between from and to(inclusive) with comments, 0 if none.
+// The rows are read in ascending order, so the rows before from are skipped for the later calls.
+func (sc *sheetComments) commentedRow(from, to int) int {
+	if sc == nil {
+		return 0
+	}
+	for sc.cursor < len(sc.rowNums) && sc.rowNums[sc.cursor] < from {
+		sc.cursor++
+	}
+	if sc.cursor < len(sc.rowNums) && sc.rowNums[sc.cursor] <= to {
+		return sc.rowNums[sc.cursor]
+	}
+
+	return 0
+}
+
+// fill writes the comments of the row after the values of the annotated cells.
+//
+// Parameters:
+//   - sr: the row to fill.
+//   - maxColumns: the max number of columns of a row, 0 if no limit.
+func (sc *sheetComments) fill(sr *sheetRow, maxColumns int) {
+	if sc == nil {
+		return
+	}
+	for _, cc := range sc.rows[sr.num] {
+		if maxColumns > 0 && cc.col > maxColumns {
+			continue
+		}
+		if cc.col <= len(sr.cells) && sr.cells[cc.col-1] != "" {
+			sr.set(cc.col, sr.cells[cc.col-1]+" "+cc.text)
+		} else {
+			sr.set(cc.col, cc.text)
+		}
+	}
+}
+
+// ExtractComments extracts the comments of the cells of the specified xlsx sheet, the notes(legacy comments)
+// and the threaded comments with their replies.
+//
+// The authors of threaded comments are resolved by the persons of the workbook, and the author names which
+// Excel writes at the start of the notes(like "Jane Doe:\n") are trimmed from the texts. The notes written
+// by Excel for the threaded comments(for the versions not supporting them) are skipped.
+//
+// Parameters:
+//   - sheet: the sheet number(start 1).
+//
+// Returns:
+//   - []types.Comment: the comments in the order of the annotated cells by rows and columns.
+//   - error: types.ErrNoSheet if the sheet is not found, or an error if the comments can not be read.
+func (xp *XlsxParser) ExtractComments(sheet int) ([]types.Comment, error) {
+	if _, ok := xp.sheetFiles[sheet]; !ok {
+		return nil, types.ErrNoSheet
+	}
+
+	part := xp.workbook.sheets[sheet-1].part
+	var threads []types.Comment
+	for _, name := range xp.pkg.Related(part, "threadedComment") {
+		comments, err := xp.parseThreadedComments(name)
+		if err != nil {
+			return nil, err
+		}
+		threads = append(threads, comments...)
+	}
+
+	threaded := make(map[string]bool, len(threads))
+	for _, c := range threads {
+		threaded[c.Ref] = true
+	}
+	comments := threads
+	for _, name := range xp.pkg.Related(part, "comments") {
+		notes, err := xp.parseNotes(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, note := range notes {
+			if !threaded[note.Ref] {
+				comments = append(comments, note)
+			}
+		}
+	}
+
+	sort.SliceStable(comments, func(i, j int) bool {
+		if comments[i].Row != comments[j].Row {
+			return comments[i].Row < comments[j].Row
+		}
+		return comments[i].Col < comments[j].Col
+	})
+
+	return comments, nil
+}
+
+// parseNotes parses the notes(commentList/comment) of the comments part, the authors are resolved by the
+// indexes of the author list(authors/author).
+func (xp *XlsxParser) parseNotes(name string) ([]types.Comment, error) {
+	file := xp.pkg.File(name)
+	if file == nil {
+		return nil, nil
+	}
+	rc, err := opc.OpenPart(file)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var (
+		authors    []string
+		authorIDs  []int
+		notes      []types.Comment
+		text       = new(strings.Builder)
+		t          = ""
+		inComment  = false // in a note with a valid cell reference
+		inText     = false
+		inPhonetic = false
+	)
+	r := qxml.NewReader(rc)
+NEXT:
+	for r.Next() {
+		switch e := r.Element().(type) {
+		case *qxml.StartElement:
+			switch e.Name() {
+			case "author":
+				if e.HasEnd() {
+					authors = append(authors, "")
+					continue
+				}
+				r.AssignNext(&t)
+				if !r.Next() {
+					break NEXT
+				}
+				authors = append(authors, html.UnescapeString(t))
+				t = ""
+
+			case "comment":
+				var note types.Comment
+				attrs := e.Attrs()
+				if ref := attrs.Get("ref"); ref != nil {
+					note.Ref = ref.Value()
+					note.Col, note.Row = parseCellRef(note.Ref)
+				}
+				id := -1
+				if authorID := attrs.Get("authorId"); authorID != nil {
+					id, _ = strconv.Atoi(authorID.Value())
+				}
+				inComment = note.Col > 0 && note.Row > 0
+				if !inComment {
+					continue
+				}
+				notes = append(notes, note)
+				authorIDs = append(authorIDs, id)
+				text.Reset()
+
+			case "text":
+				inText = inComment && !e.HasEnd()
+
+			case "rPh":
+				inPhonetic = !e.HasEnd()
+
+			case "t":
+				if !inText || inPhonetic || e.HasEnd() {
+					continue
+				}
+				r.AssignNext(&t)
+				if !r.Next() {
+					break NEXT
+				}
+				text.WriteString(t)
+				t = ""
+			}
+
+		case *qxml.EndElement:
+			switch e.Name() {
+			case "text":
+				if inText {
+					notes[len(notes)-1].Text = html.UnescapeString(text.String())
+				}
+				inText = false
+			case "comment":
+				inComment = false
+			case "rPh":
+				inPhonetic = false
+			}
+		}
+	}
+
+	for i := range notes {
+		if id := authorIDs[i]; id >= 0 && id < len(authors) {
+			notes[i].Author = authors[id]
+		}
+		if author := notes[i].Author; author != "" && strings.HasPrefix(notes[i].Text, author+":") {
+			notes[i].Text = strings.TrimSpace(notes[i].Text[len(author)+1:])
+		}
+	}
+
+	return notes, nil
+}
+
+// parseThreadedComments parses the threaded comments(threadedComment) of the threaded comments part,
+// the replies(threadedComment@parentId) are nested into the comments they reply to.
+func (xp *XlsxParser) parseThreadedComments(name string) ([]types.Comment, error) {
+	file := xp.pkg.File(name)
+	if file == nil {
+		return nil, nil
+	}
+	if xp.persons == nil {
+		xp.logWarn(xp.parsePersons())
+	}
+	rc, err := opc.OpenPart(file)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var (
+		comments  []types.Comment
+		parentIDs []string
+		t         = ""
+		inComment = false // in a threaded comment with a valid cell reference
+	)
+	r := qxml.NewReader(rc)
+NEXT:
+	for r.Next() {
+		switch e := r.Element().(type) {
+		case *qxml.StartElement:
+			switch e.Name() {
+			case "threadedComment":
+				c := types.Comment{Threaded: true}
+				attrs := e.Attrs()
+				if ref := attrs.Get("ref"); ref != nil {
+					c.Ref = ref.Value()
+					c.Col, c.Row = parseCellRef(c.Ref)
+				}
+				if id := attrs.Get("id"); id != nil {
+					c.ID = id.Value()
+				}
+				if personID := attrs.Get("personId"); personID != nil {
+					c.Author = xp.persons[personID.Value()]
+				}
+				if dT := attrs.Get("dT"); dT != nil {
+					c.Time = parseCommentTime(dT.Value())
+				}
+				if done := attrs.Get("done"); done != nil {
+					c.Done = done.Value() == "1" || done.Value() == "true"
+				}
+				parentID := ""
+				if parent := attrs.Get("parentId"); parent != nil {
+					parentID = parent.Value()
+				}
+				inComment = c.Col > 0 && c.Row > 0 && !e.HasEnd()
+				if !inComment {
+					continue
+				}
+				comments = append(comments, c)
+				parentIDs = append(parentIDs, parentID)
+
+			case "text":
+				if !inComment || e.HasEnd() {
+					continue
+				}
+				r.AssignNext(&t)
+				if !r.Next() {
+					break NEXT
+				}
+				comments[len(comments)-1].Text = html.UnescapeString(t)
+				t = ""
+			}
+
+		case *qxml.EndElement:
+			if e.Name() == "threadedComment" {
+				inComment = false
+			}
+		}
+	}
+
+	// the replies follow the comments they reply to
+	var (
+		threads = make([]types.Comment, 0, len(comments))
+		index   = make(map[string]int, len(comments)) // the index of the thread keyed by the ids of comments
+	)
+	for i, c := range comments {
+		if j, ok := index[parentIDs[i]]; ok && parentIDs[i] != "" {
+			threads[j].Replies = append(threads[j].Replies, c)
+			index[c.ID] = j
+			continue
+		}
+		threads = append(threads, c)
+		if c.ID != "" {
+			index[c.ID] = len(threads) - 1
+		}
+	}
+
+	return threads, nil
+}
+
+// parsePersons parses the display names of the persons(personList/person) of the workbook, which are the
+// authors of threaded comments.
+func (xp *XlsxParser) parsePersons() error {
+	xp.persons = make(map[string]string, 4)
+	if xp.personsFile == nil {
+		return nil
+	}
+	rc, err := opc.OpenPart(xp.personsFile)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	r := qxml.NewReader(rc)
+	for r.Next() {
+		e, ok := r.Element().(*qxml.StartElement)
+		if !ok || e.Name() != "person" {
+			continue
+		}
+		attrs := e.Attrs()
+		id, displayName := attrs.Get("id"), attrs.Get("displayName")
+		if id != nil && displayName != nil {
+			xp.persons[id.Value()] = html.UnescapeString(displayName.Value())
+		}
+	}
+
+	return nil
+}
+
+// parseCommentTime parses the time of the threaded comment(threadedComment@dT), like "2023-07-16T09:30:00.00",
+// zero if the time is invalid.
+func parseCommentTime(v string) time.Time {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05"} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
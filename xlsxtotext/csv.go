@@ -0,0 +1,171 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package xlsxtotext
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/young2j/oxmltotext/types"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/transform"
+)
+
+// WriteSheetCSV writes the cell values of the specified xlsx sheet to w as CSV.
+//
+// The cell values are placed in the fields by the cell references from column A, and the records are
+// written from the first row of the row range(row 1 by default) with the skipped rows as empty records.
+// Each record has the fields of the used range(dimension) of the sheet at least, and the trailing empty
+// records are trimmed. The fields are quoted by the quote mode and the text is encoded by the encoding,
+// see SetCSVDelimiter, SetCSVQuoteMode, SetCSVEncoding, SetCSVUseCRLF and SetCSVRowRange.
+// The sheet is read until the last row of the row range.
+//
+// Parameters:
+//   - w: the writer to write CSV to.
+//   - sheet: the sheet number(start 1).
+//
+// Returns:
+//   - error: types.ErrNoSheet if the sheet is not found, types.ErrBadDelimiter if the delimiter is a quote,
+//     line break or invalid rune, or an error if there is any issue with parsing the sheet
+//     or writing to w.
+func (xp *XlsxParser) WriteSheetCSV(w io.Writer, sheet int) error {
+	if !validCSVDelimiter(xp.csvDelimiter) {
+		return types.ErrBadDelimiter
+	}
+	if !xp.shareParsed {
+		err := xp.parseSharedStrings()
+		if err != nil {
+			return err
+		}
+	}
+	if _, ok := xp.sheetFiles[sheet]; !ok {
+		return types.ErrNoSheet
+	}
+	if xp.workbook.sheets[sheet-1].hidden() && xp.hiddenMode == types.HiddenExclude {
+		return nil
+	}
+
+	sr, err := xp.newSheetReader(sheet, nil)
+	if err != nil {
+		return err
+	}
+	defer sr.close()
+
+	var tw *transform.Writer // the writer encoding to GBK, closed to flush the pending bytes
+	switch xp.csvEncoding {
+	case types.EncodingUTF8BOM:
+		if _, err = io.WriteString(w, "\uFEFF"); err != nil {
+			return err
+		}
+	case types.EncodingGBK:
+		tw = transform.NewWriter(w, encoding.ReplaceUnsupported(simplifiedchinese.GBK.NewEncoder()))
+		w = tw
+	}
+
+	var (
+		bw        = bufio.NewWriter(w)
+		first     = max(xp.csvFirstRow, 1)
+		prevRow   = first - 1 // the number of the previous row
+		blankRows = 0         // the number of the empty rows not written yet
+		width     = 0         // the number of the fields of the used range
+		eol       = "\n"
+	)
+	if xp.csvCRLF {
+		eol = "\r\n"
+	}
+	for row := sr.next(); row != nil; row = sr.next() {
+		// the dimension is read before the first row
+		if width = sr.width; xp.maxColumns > 0 && width > xp.maxColumns {
+			width = xp.maxColumns
+		}
+		if row.num < first {
+			continue
+		}
+		if xp.csvLastRow > 0 && row.num > xp.csvLastRow {
+			break
+		}
+		blankRows += row.num - prevRow - 1
+		prevRow = row.num
+		if row.empty() {
+			blankRows++
+			continue
+		}
+		for ; blankRows > 0; blankRows-- {
+			xp.writeCSVRecord(bw, nil, width)
+			bw.WriteString(eol)
+		}
+		xp.writeCSVRecord(bw, row.cells, width)
+		bw.WriteString(eol)
+	}
+	if err = sr.err(); err != nil {
+		return err
+	}
+
+	if err = bw.Flush(); err != nil {
+		return err
+	}
+	if tw != nil {
+		return tw.Close()
+	}
+
+	return nil
+}
+
+// writeCSVRecord writes the fields of a record, the empty fields are padded to the width.
+func (xp *XlsxParser) writeCSVRecord(w *bufio.Writer, fields []string, width int) {
+	n := max(len(fields), width, 1)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			w.WriteRune(xp.csvDelimiter)
+		}
+		if i < len(fields) {
+			xp.writeCSVField(w, fields[i])
+		} else if xp.csvQuoteMode == types.QuoteAll {
+			w.WriteString(`""`)
+		}
+	}
+}
+
+// writeCSVField writes the field quoted by the quote mode, see SetCSVQuoteMode.
+func (xp *XlsxParser) writeCSVField(w *bufio.Writer, field string) {
+	switch xp.csvQuoteMode {
+	case types.QuoteNone:
+		w.WriteString(strings.Map(func(r rune) rune {
+			if r == xp.csvDelimiter || r == '\r' || r == '\n' {
+				return ' '
+			}
+			return r
+		}, field))
+		return
+	case types.QuoteMinimal:
+		if !strings.ContainsAny(field, "\"\r\n") && !strings.ContainsRune(field, xp.csvDelimiter) {
+			w.WriteString(field)
+			return
+		}
+	}
+
+	w.WriteByte('"')
+	for len(field) > 0 {
+		i := strings.IndexByte(field, '"')
+		if i < 0 {
+			w.WriteString(field)
+			break
+		}
+		w.WriteString(field[:i+1])
+		w.WriteByte('"')
+		field = field[i+1:]
+	}
+	w.WriteByte('"')
+}
+
+// validCSVDelimiter reports whether the delimiter can separate the fields.
+func validCSVDelimiter(r rune) bool {
+	return r != 0 && r != '"' && r != '\r' && r != '\n' && utf8.ValidRune(r) && r != utf8.RuneError
+}
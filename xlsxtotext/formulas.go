@@ -0,0 +1,350 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package xlsxtotext
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/young2j/oxmltotext/types"
+
+	qxml "github.com/dgrr/quickxml"
+)
+
+const (
+	maxSheetColumns = 16384   // the max column number of a worksheet(XFD)
+	maxSheetRows    = 1048576 // the max row number of a worksheet
+)
+
+var (
+	re_CELLREF = regexp.MustCompile(`^(\$?)([A-Za-z]{1,3})(\$?)([0-9]{1,7})$`)
+	re_COLREF  = regexp.MustCompile(`^(\$?)([A-Za-z]{1,3})$`)
+	re_ROWREF  = regexp.MustCompile(`^(\$?)([0-9]{1,7})$`)
+)
+
+// cellFormula is the formula of a cell(c/f).
+type cellFormula struct {
+	typ  string // the formula type(f@t): normal(or empty), shared, array or dataTable
+	si   string // the index of the shared formula(f@si)
+	ref  string // the range of the shared, array or data table formula(f@ref)
+	r1   string // the first input cell of the data table(f@r1)
+	r2   string // the second input cell of the two-dimensional data table(f@r2)
+	dt2D bool   // the data table is two-dimensional(f@dt2D)
+	dtr  bool   // the input cell of the one-dimensional data table is a row input cell(f@dtr)
+	text string // the formula text without "="
+}
+
+// newCellFormula returns the cell formula by the attributes of the formula element.
+func newCellFormula(attrs *qxml.Attrs) cellFormula {
+	var f cellFormula
+	attrs.Range(func(kv *qxml.KV) {
+		switch kv.Key() {
+		case "t":
+			f.typ = kv.Value()
+		case "si":
+			f.si = kv.Value()
+		case "ref":
+			f.ref = kv.Value()
+		case "r1":
+			f.r1 = kv.Value()
+		case "r2":
+			f.r2 = kv.Value()
+		case "dt2D":
+			f.dt2D = kv.Value() == "1" || kv.Value() == "true"
+		case "dtr":
+			f.dtr = kv.Value() == "1" || kv.Value() == "true"
+		}
+	})
+
+	return f
+}
+
+// rangeFormula is the array or data table formula over a range of cells.
+type rangeFormula struct {
+	col1, row1, col2, row2 int
+	text                   string // the formula, like "{=A1:A3*B1:B3}"
+}
+
+// sheetFormulas is the formulas of a worksheet referenced by other cells.
+type sheetFormulas struct {
+	shared map[string]sharedFormula // the master formulas of the shared formulas by f@si
+	ranges []rangeFormula           // the array and data table formulas over the current row or below
+	row    int                      // the current row, the ranges above it are dropped
+}
+
+// sharedFormula is the master formula of a shared formula, which is written in the first cell of the range.
+type sharedFormula struct {
+	col, row int
+	text     string
+}
+
+// formula returns the formula of the cell, like "=SUM(A1:A3)" or "{=A1:A3*B1:B3}".
+//
+// The shared formulas are expanded by shifting the relative references of their master formulas,
+// the array formulas are surrounded by braces, and the data tables are written as {=TABLE(r1,r2)}.
+//
+// Parameters:
+//   - f: the formula of the cell.
+//   - col: the column number of the cell.
+//   - row: the row number of the cell.
+//
+// Returns:
+//   - string: the formula, empty if the cell has no formula or the master of the shared formula is missing.
+func (sf *sheetFormulas) formula(f cellFormula, col, row int) string {
+	text := html.UnescapeString(f.text)
+	switch f.typ {
+	case "shared":
+		if text != "" {
+			if sf.shared == nil {
+				sf.shared = make(map[string]sharedFormula, 4)
+			}
+			sf.shared[f.si] = sharedFormula{col: col, row: row, text: text}
+			return "=" + text
+		}
+		master, ok := sf.shared[f.si]
+		if !ok {
+			return ""
+		}
+		return "=" + shiftFormula(master.text, row-master.row, col-master.col)
+	case "array", "dataTable":
+		if f.typ == "array" {
+			text = "{=" + text + "}"
+		} else {
+			switch {
+			case f.dt2D:
+				text = "{=TABLE(" + f.r1 + "," + f.r2 + ")}"
+			case f.dtr:
+				text = "{=TABLE(" + f.r1 + ",)}"
+			default:
+				text = "{=TABLE(," + f.r1 + ")}"
+			}
+		}
+		col1, row1, col2, row2 := parseRangeRef(f.ref)
+		if col1 == 0 || row1 == 0 {
+			col1, row1, col2, row2 = col, row, col, row
+		}
+		sf.ranges = append(sf.ranges, rangeFormula{col1, row1, col2, row2, text})
+		return text
+	}
+	if text == "" {
+		return ""
+	}
+
+	return "=" + text
+}
+
+// rangeFormula returns the array or data table formula over the cell, empty if none.
+// The rows are read in ascending order, so the ranges ending above the row are dropped
+// and only the ranges over the row are checked.
+func (sf *sheetFormulas) rangeFormula(col, row int) string {
+	if row > sf.row {
+		sf.row = row
+		ranges := sf.ranges[:0]
+		for _, f := range sf.ranges {
+			if f.row2 >= row {
+				ranges = append(ranges, f)
+			}
+		}
+		sf.ranges = ranges
+	}
+	for _, f := range sf.ranges {
+		if col >= f.col1 && col <= f.col2 && row >= f.row1 && row <= f.row2 {
+			return f.text
+		}
+	}
+
+	return ""
+}
+
+// formulaText returns the text of the cell with the formula by the formula mode, see SetFormulaMode.
+//
+// Parameters:
+//   - formula: the formula of the cell, empty if the cell has no formula.
+//   - value: the text of the cached value.
+//   - hasValue: the cell has a cached value(c/v).
+//
+// Returns:
+//   - string: the text of the cell.
+func (xp *XlsxParser) formulaText(formula, value string, hasValue bool) string {
+	if formula == "" {
+		return value
+	}
+	switch xp.formulaMode {
+	case types.FormulaText:
+		return formula
+	case types.FormulaBoth:
+		if !hasValue {
+			value = types.FormulaNoValue
+		}
+		return formula + types.FormulaValueSep + value
+	}
+
+	// the formulas never calculated have no values, like the empty cells
+	return value
+}
+
+// shiftFormula shifts the relative references of the formula by the offsets of rows and columns, as the
+// formula is copied to another cell. The absolute references("$"), strings, sheet names, functions and
+// structured references(like Table1[Column]) are kept, and the references shifted out of the worksheet
+// are replaced by #REF!.
+//
+// Parameters:
+//   - formula: the formula text.
+//   - dRow: the offset of rows.
+//   - dCol: the offset of columns.
+//
+// Returns:
+//   - string: the shifted formula.
+func shiftFormula(formula string, dRow, dCol int) string {
+	if dRow == 0 && dCol == 0 {
+		return formula
+	}
+
+	b := new(strings.Builder)
+	b.Grow(len(formula))
+	for i := 0; i < len(formula); {
+		c := formula[i]
+		switch {
+		case c == '"' || c == '\'':
+			// strings and quoted sheet names, the quotes are escaped by doubling
+			j := i + 1
+			for j < len(formula) {
+				if formula[j] == c {
+					if j+1 < len(formula) && formula[j+1] == c {
+						j += 2
+						continue
+					}
+					j++
+					break
+				}
+				j++
+			}
+			b.WriteString(formula[i:j])
+			i = j
+		case c == '[':
+			// external workbooks and structured references, which may be nested
+			j, depth := i, 0
+			for j < len(formula) {
+				if formula[j] == '[' {
+					depth++
+				} else if formula[j] == ']' {
+					depth--
+				}
+				j++
+				if depth == 0 {
+					break
+				}
+			}
+			b.WriteString(formula[i:j])
+			i = j
+		case isNameChar(c):
+			j := i
+			for j < len(formula) && isNameChar(formula[j]) {
+				j++
+			}
+			word := formula[i:j]
+			i = j
+			if j < len(formula) && (formula[j] == '(' || formula[j] == '!' || formula[j] == '[') {
+				b.WriteString(word)
+				continue
+			}
+			if j < len(formula) && formula[j] == ':' {
+				// whole columns or rows, like A:C or 1:3
+				k := j + 1
+				for k < len(formula) && isNameChar(formula[k]) {
+					k++
+				}
+				end := formula[j+1 : k]
+				if re_COLREF.MatchString(word) && re_COLREF.MatchString(end) {
+					b.WriteString(shiftRef(word, re_COLREF, 0, dCol))
+					b.WriteByte(':')
+					b.WriteString(shiftRef(end, re_COLREF, 0, dCol))
+					i = k
+					continue
+				}
+				if re_ROWREF.MatchString(word) && re_ROWREF.MatchString(end) {
+					b.WriteString(shiftRef(word, re_ROWREF, dRow, 0))
+					b.WriteByte(':')
+					b.WriteString(shiftRef(end, re_ROWREF, dRow, 0))
+					i = k
+					continue
+				}
+			}
+			b.WriteString(shiftRef(word, re_CELLREF, dRow, dCol))
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+
+	return b.String()
+}
+
+// isNameChar reports whether the byte is a character of names, functions and references.
+func isNameChar(c byte) bool {
+	return c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' ||
+		c == '_' || c == '.' || c == '$' || c == '\\' || c >= 0x80
+}
+
+// shiftRef shifts the relative column and row of the reference matched by re, which is one of
+// re_CELLREF, re_COLREF and re_ROWREF, the word is returned as it is if it is not a reference.
+func shiftRef(word string, re *regexp.Regexp, dRow, dCol int) string {
+	m := re.FindStringSubmatch(word)
+	if m == nil {
+		return word
+	}
+
+	var (
+		b        = new(strings.Builder)
+		colPart  = ""
+		rowPart  = ""
+		colAbs   = ""
+		rowAbs   = ""
+		col, row = 0, 0
+	)
+	switch re {
+	case re_CELLREF:
+		colAbs, colPart, rowAbs, rowPart = m[1], m[2], m[3], m[4]
+	case re_COLREF:
+		colAbs, colPart = m[1], m[2]
+	case re_ROWREF:
+		rowAbs, rowPart = m[1], m[2]
+	}
+	if colPart != "" {
+		col, _ = parseCellRef(colPart)
+		if col > maxSheetColumns {
+			return word
+		}
+		if colAbs == "" {
+			col += dCol
+		}
+	}
+	if rowPart != "" {
+		row, _ = strconv.Atoi(rowPart)
+		if row < 1 || row > maxSheetRows {
+			return word
+		}
+		if rowAbs == "" {
+			row += dRow
+		}
+	}
+	if colPart != "" && (col < 1 || col > maxSheetColumns) || rowPart != "" && (row < 1 || row > maxSheetRows) {
+		return "#REF!"
+	}
+
+	if colPart != "" {
+		b.WriteString(colAbs)
+		b.WriteString(columnName(col))
+	}
+	if rowPart != "" {
+		b.WriteString(rowAbs)
+		b.WriteString(strconv.Itoa(row))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,207 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package xlsxtotext
+
+import (
+	"archive/zip"
+	"sort"
+	"strconv"
+
+	"github.com/young2j/oxmltotext/opc"
+	"github.com/young2j/oxmltotext/types"
+
+	qxml "github.com/dgrr/quickxml"
+)
+
+// mergeRange is a range of merged cells(mergeCells/mergeCell@ref).
+type mergeRange struct {
+	col1, row1, col2, row2 int
+	value                  string     // the text of the top left cell
+	cell                   types.Cell // the typed top left cell
+}
+
+// sheetMerges is the merged cells of a worksheet.
+//
+// The rows are read in ascending order, the ranges covering the current row are tracked by the ranges
+// sorted by their top rows, so the ranges are not scanned for each row.
+type sheetMerges struct {
+	ranges  []mergeRange
+	topLeft map[[2]int]int // the indexes of the ranges by the column and row of their top left cells
+	maxRow  int            // the max row number of the ranges
+	byRow   []int          // the indexes of the ranges sorted by their top rows
+	next    int            // the index of byRow of the first range below the current row
+	active  []int          // the indexes of the ranges covering the current row
+	row     int            // the current row
+}
+
+// parseMergeCells parses the merged cells of the worksheet.
+//
+// The merged cells are written after the sheet data, so the worksheet is scanned before reading the rows.
+//
+// Parameters:
+//   - file: the worksheet part.
+//
+// Returns:
+//   - *sheetMerges: the merged cells of the worksheet.
+//   - error: an error if the worksheet part can not be read.
+func parseMergeCells(file *zip.File) (*sheetMerges, error) {
+	rc, err := opc.OpenPart(file)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	sm := &sheetMerges{topLeft: make(map[[2]int]int, 4)}
+	r := qxml.NewReader(rc)
+	for r.Next() {
+		e, ok := r.Element().(*qxml.StartElement)
+		if !ok || e.Name() != "mergeCell" {
+			continue
+		}
+		ref := e.Attrs().Get("ref")
+		if ref == nil {
+			continue
+		}
+		col1, row1, col2, row2 := parseRangeRef(ref.Value())
+		if col1 == 0 || row1 == 0 || (col1 == col2 && row1 == row2) {
+			continue
+		}
+		sm.topLeft[[2]int{col1, row1}] = len(sm.ranges)
+		sm.ranges = append(sm.ranges, mergeRange{col1: col1, row1: row1, col2: col2, row2: row2})
+		if row2 > sm.maxRow {
+			sm.maxRow = row2
+		}
+	}
+
+	sm.byRow = make([]int, len(sm.ranges))
+	for i := range sm.byRow {
+		sm.byRow[i] = i
+	}
+	sort.SliceStable(sm.byRow, func(i, j int) bool {
+		return sm.ranges[sm.byRow[i]].row1 < sm.ranges[sm.byRow[j]].row1
+	})
+
+	return sm, nil
+}
+
+// advance moves the current row to row, which should not be less than the current row,
+// and updates the ranges covering it.
+func (sm *sheetMerges) advance(row int) {
+	if row <= sm.row {
+		return
+	}
+	sm.row = row
+
+	active := sm.active[:0]
+	for _, i := range sm.active {
+		if sm.ranges[i].row2 >= row {
+			active = append(active, i)
+		}
+	}
+	for ; sm.next < len(sm.byRow) && sm.ranges[sm.byRow[sm.next]].row1 <= row; sm.next++ {
+		if i := sm.byRow[sm.next]; sm.ranges[i].row2 >= row {
+			active = append(active, i)
+		}
+	}
+	sm.active = active
+}
+
+// record records the value and the typed cell of the cell if it is the top left cell of a merged range.
+func (sm *sheetMerges) record(col, row int, v string, cell types.Cell) {
+	if sm == nil {
+		return
+	}
+	if i, ok := sm.topLeft[[2]int{col, row}]; ok {
+		sm.ranges[i].value = v
+		sm.ranges[i].cell = cell
+	}
+}
+
+// span returns the merged range of the top left cell, nil if the cell is not the top left cell of a merged range.
+func (sm *sheetMerges) span(col, row int) *mergeRange {
+	if sm == nil {
+		return nil
+	}
+	if i, ok := sm.topLeft[[2]int{col, row}]; ok {
+		return &sm.ranges[i]
+	}
+
+	return nil
+}
+
+// covered reports whether the cell is covered by a merged range except its top left cell.
+func (sm *sheetMerges) covered(col, row int) bool {
+	if sm == nil {
+		return false
+	}
+	sm.advance(row)
+	for _, i := range sm.active {
+		mr := &sm.ranges[i]
+		if col >= mr.col1 && col <= mr.col2 && row >= mr.row1 && row <= mr.row2 && (col != mr.col1 || row != mr.row1) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// coveredRow returns the first row between from and to(inclusive) covered by a merged range below its top row,
+// 0 if none.
+func (sm *sheetMerges) coveredRow(from, to int) int {
+	if sm == nil || from > to {
+		return 0
+	}
+	sm.advance(from)
+	first := 0
+	for _, i := range sm.active {
+		mr := &sm.ranges[i]
+		if row := max(from, mr.row1+1); row <= mr.row2 && row <= to && (first == 0 || row < first) {
+			first = row
+		}
+	}
+	if first == from {
+		return first
+	}
+
+	// the ranges below from, the first range over multiple rows covers the row below its top row
+	for _, i := range sm.byRow[sm.next:] {
+		mr := &sm.ranges[i]
+		if row := mr.row1 + 1; row > to || (first > 0 && row >= first) {
+			break
+		}
+		if mr.row2 > mr.row1 {
+			return mr.row1 + 1
+		}
+	}
+
+	return first
+}
+
+// fill repeats the values of the top left cells into the covered cells of the row.
+//
+// Parameters:
+//   - sr: the row to fill.
+//   - maxColumns: the max number of columns of a row, 0 if no limit.
+//   - typed: repeats the typed cells too.
+func (sm *sheetMerges) fill(sr *sheetRow, maxColumns int, typed bool) {
+	if sm == nil {
+		return
+	}
+	sm.advance(sr.num)
+	for _, i := range sm.active {
+		mr := &sm.ranges[i]
+		for col := mr.col1; col <= mr.col2 && (maxColumns <= 0 || col <= maxColumns); col++ {
+			if col != mr.col1 || sr.num != mr.row1 {
+				sr.set(col, mr.value)
+				if typed {
+					cell := mr.cell
+					cell.Ref, cell.Col, cell.Row = columnName(col)+strconv.Itoa(sr.num), col, sr.num
+					sr.setTyped(col, cell)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,1054 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package xlsxtotext
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// builtinNumFmts are the built-in number formats of SpreadsheetML(ECMA-376 18.8.30) keyed by numFmtId.
+var builtinNumFmts = map[int]string{
+	0:  "General",
+	1:  "0",
+	2:  "0.00",
+	3:  "#,##0",
+	4:  "#,##0.00",
+	9:  "0%",
+	10: "0.00%",
+	11: "0.00E+00",
+	12: "# ?/?",
+	13: "# ??/??",
+	14: "mm-dd-yy",
+	15: "d-mmm-yy",
+	16: "d-mmm",
+	17: "mmm-yy",
+	18: "h:mm AM/PM",
+	19: "h:mm:ss AM/PM",
+	20: "h:mm",
+	21: "h:mm:ss",
+	22: "m/d/yy h:mm",
+	37: "#,##0 ;(#,##0)",
+	38: "#,##0 ;[Red](#,##0)",
+	39: "#,##0.00;(#,##0.00)",
+	40: "#,##0.00;[Red](#,##0.00)",
+	45: "mm:ss",
+	46: "[h]:mm:ss",
+	47: "mmss.0",
+	48: "##0.0E+0",
+	49: "@",
+}
+
+// tokenKind is the kind of the token of number format code.
+type tokenKind int
+
+const (
+	tokenLiteral   tokenKind = iota
+	tokenGeneral             // General
+	tokenDigit               // digit placeholders: 0, # and ?
+	tokenPoint               // decimal point, or the point of subseconds in date formats
+	tokenComma               // thousands separator or scaling by thousand
+	tokenPercent             // %
+	tokenExponent            // E+, E-, e+ and e-
+	tokenSlash               // the slash of fractions
+	tokenText                // @
+	tokenYear                // y, yy, yyyy and e
+	tokenMonth               // m, mm, mmm, mmmm and mmmmm
+	tokenDay                 // d, dd, ddd and dddd
+	tokenHour                // h and hh
+	tokenMinute              // m and mm after hours or before seconds
+	tokenSecond              // s and ss
+	tokenSubsecond           // 0, 00 and 000 after the point of seconds
+	tokenAmPm                // AM/PM and A/P
+	tokenElapsed             // [h], [mm] and [ss]
+)
+
+// fmtToken is a token of number format code.
+type fmtToken struct {
+	kind tokenKind
+	text string
+}
+
+// fmtSection is a section of number format code, the sections are separated by semicolons.
+type fmtSection struct {
+	tokens    []fmtToken
+	cond      string  // the condition operator like ">=", empty if no condition
+	limit     float64 // the value of the condition
+	date      bool    // the section is a date or time format
+	text      bool    // the section has the text placeholder(@)
+	thousands bool    // the integer digits are grouped by thousands separators
+	scale     int     // the number of the commas scaling the number by thousand
+	percent   int     // the number of the percent signs scaling the number by hundred
+}
+
+// numFmt is a parsed number format.
+type numFmt struct {
+	sections []*fmtSection
+}
+
+// parseNumFmt parses the number format code.
+//
+// Parameters:
+//   - code: the format code like "#,##0.00;[Red]-#,##0.00" or "yyyy-mm-dd".
+//
+// Returns:
+//   - *numFmt: the parsed number format.
+func parseNumFmt(code string) *numFmt {
+	nf := &numFmt{sections: make([]*fmtSection, 0, 1)}
+	for _, section := range splitSections(code) {
+		nf.sections = append(nf.sections, parseSection(section))
+	}
+
+	return nf
+}
+
+// splitSections splits the format code by the semicolons out of quotes, brackets and escapes.
+func splitSections(code string) []string {
+	var (
+		sections = make([]string, 0, 1)
+		start    = 0
+		quoted   = false
+		bracket  = false
+	)
+	for i := 0; i < len(code); i++ {
+		switch c := code[i]; {
+		case quoted:
+			quoted = c != '"'
+		case bracket:
+			bracket = c != ']'
+		case c == '"':
+			quoted = true
+		case c == '[':
+			bracket = true
+		case c == '\\' || c == '_' || c == '*':
+			i++
+		case c == ';':
+			sections = append(sections, code[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(sections, code[start:])
+}
+
+// parseSection tokenizes the section of format code.
+func parseSection(code string) *fmtSection {
+	var (
+		fs    = &fmtSection{tokens: make([]fmtToken, 0, 8)}
+		runes = []rune(code)
+	)
+	literal := func(s string) {
+		if n := len(fs.tokens); n > 0 && fs.tokens[n-1].kind == tokenLiteral {
+			fs.tokens[n-1].text += s
+			return
+		}
+		fs.tokens = append(fs.tokens, fmtToken{kind: tokenLiteral, text: s})
+	}
+	repeat := func(i int, lower rune) int {
+		j := i
+		for j < len(runes) && (runes[j] == lower || runes[j] == lower-'a'+'A') {
+			j++
+		}
+		return j
+	}
+
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch {
+		case c == '"':
+			j := i + 1
+			for j < len(runes) && runes[j] != '"' {
+				j++
+			}
+			literal(string(runes[i+1 : j]))
+			i = j
+		case c == '\\':
+			if i+1 < len(runes) {
+				literal(string(runes[i+1]))
+				i++
+			}
+		case c == '_':
+			// the space of the width of the next character
+			literal(" ")
+			i++
+		case c == '*':
+			// the next character is repeated to fill the cell
+			i++
+		case c == '[':
+			j := i + 1
+			for j < len(runes) && runes[j] != ']' {
+				j++
+			}
+			fs.bracket(string(runes[i+1:min(j, len(runes))]), literal)
+			i = j
+		case strings.HasPrefix(strings.ToLower(string(runes[i:])), "general"):
+			fs.tokens = append(fs.tokens, fmtToken{kind: tokenGeneral})
+			i += len("general") - 1
+		case strings.HasPrefix(strings.ToUpper(string(runes[i:])), "AM/PM"):
+			fs.tokens = append(fs.tokens, fmtToken{kind: tokenAmPm, text: string(runes[i : i+5])})
+			fs.date = true
+			i += 4
+		case strings.HasPrefix(strings.ToUpper(string(runes[i:])), "A/P"):
+			fs.tokens = append(fs.tokens, fmtToken{kind: tokenAmPm, text: string(runes[i : i+3])})
+			fs.date = true
+			i += 2
+		case c == '0' || c == '#' || c == '?':
+			if n := len(fs.tokens); c == '0' && n > 0 && fs.tokens[n-1].kind == tokenPoint && fs.date {
+				j := i
+				for j < len(runes) && runes[j] == '0' {
+					j++
+				}
+				fs.tokens = append(fs.tokens, fmtToken{kind: tokenSubsecond, text: string(runes[i:j])})
+				i = j - 1
+				continue
+			}
+			fs.tokens = append(fs.tokens, fmtToken{kind: tokenDigit, text: string(c)})
+		case c == '.':
+			fs.tokens = append(fs.tokens, fmtToken{kind: tokenPoint, text: "."})
+		case c == ',':
+			fs.tokens = append(fs.tokens, fmtToken{kind: tokenComma, text: ","})
+		case c == '%':
+			fs.tokens = append(fs.tokens, fmtToken{kind: tokenPercent, text: "%"})
+		case (c == 'E' || c == 'e') && i+1 < len(runes) && (runes[i+1] == '+' || runes[i+1] == '-') && !fs.date:
+			fs.tokens = append(fs.tokens, fmtToken{kind: tokenExponent, text: string(runes[i : i+2])})
+			i++
+		case c == '/':
+			fs.tokens = append(fs.tokens, fmtToken{kind: tokenSlash, text: "/"})
+		case c == '@':
+			fs.tokens = append(fs.tokens, fmtToken{kind: tokenText})
+			fs.text = true
+		case c == 'y' || c == 'Y':
+			j := repeat(i, 'y')
+			fs.tokens = append(fs.tokens, fmtToken{kind: tokenYear, text: strings.Repeat("y", max(j-i, 2))})
+			fs.date = true
+			i = j - 1
+		case c == 'e' || c == 'E':
+			// the year of the era is the 4 digit year of the Gregorian calendar
+			j := repeat(i, 'e')
+			fs.tokens = append(fs.tokens, fmtToken{kind: tokenYear, text: "yyyy"})
+			fs.date = true
+			i = j - 1
+		case c == 'm' || c == 'M':
+			j := repeat(i, 'm')
+			fs.tokens = append(fs.tokens, fmtToken{kind: tokenMonth, text: strings.Repeat("m", j-i)})
+			fs.date = true
+			i = j - 1
+		case c == 'd' || c == 'D':
+			j := repeat(i, 'd')
+			fs.tokens = append(fs.tokens, fmtToken{kind: tokenDay, text: strings.Repeat("d", j-i)})
+			fs.date = true
+			i = j - 1
+		case c == 'h' || c == 'H':
+			j := repeat(i, 'h')
+			fs.tokens = append(fs.tokens, fmtToken{kind: tokenHour, text: strings.Repeat("h", j-i)})
+			fs.date = true
+			i = j - 1
+		case c == 's' || c == 'S':
+			j := repeat(i, 's')
+			fs.tokens = append(fs.tokens, fmtToken{kind: tokenSecond, text: strings.Repeat("s", j-i)})
+			fs.date = true
+			i = j - 1
+		case c == 'g' || c == 'G' || c == 'b' || c == 'B':
+			// the era and the Buddhist year are not supported
+		default:
+			literal(string(c))
+		}
+	}
+
+	if fs.date {
+		fs.resolveMinutes()
+	} else {
+		fs.resolveCommas()
+	}
+
+	return fs
+}
+
+// bracket parses the bracketed part of the section: elapsed time, currency and locale, condition or color.
+func (fs *fmtSection) bracket(s string, literal func(string)) {
+	lower := strings.ToLower(s)
+	switch {
+	case lower != "" && strings.Trim(lower, "h") == "",
+		lower != "" && strings.Trim(lower, "m") == "",
+		lower != "" && strings.Trim(lower, "s") == "":
+		fs.tokens = append(fs.tokens, fmtToken{kind: tokenElapsed, text: lower})
+		fs.date = true
+	case strings.HasPrefix(s, "$"):
+		// [$€-407] is the currency symbol with the locale, [$-409] is the locale only
+		symbol := s[1:]
+		if i := strings.IndexByte(symbol, '-'); i >= 0 {
+			symbol = symbol[:i]
+		}
+		if symbol != "" {
+			literal(symbol)
+		}
+	case strings.HasPrefix(s, ">=") || strings.HasPrefix(s, "<=") || strings.HasPrefix(s, "<>"):
+		fs.cond = s[:2]
+		fs.limit, _ = strconv.ParseFloat(strings.TrimSpace(s[2:]), 64)
+	case strings.HasPrefix(s, ">") || strings.HasPrefix(s, "<") || strings.HasPrefix(s, "="):
+		fs.cond = s[:1]
+		fs.limit, _ = strconv.ParseFloat(strings.TrimSpace(s[1:]), 64)
+	}
+	// the colors like [Red] and [Color10] are ignored
+}
+
+// resolveMinutes resolves the month tokens which are minutes, i.e. after hours or before seconds.
+func (fs *fmtSection) resolveMinutes() {
+	prev := -1 // the index of the previous date token
+	for i, t := range fs.tokens {
+		switch t.kind {
+		case tokenMonth:
+			if len(t.text) <= 2 && prev >= 0 && (fs.tokens[prev].kind == tokenHour || fs.tokens[prev].kind == tokenElapsed && fs.tokens[prev].text[0] == 'h') {
+				fs.tokens[i].kind = tokenMinute
+			}
+		case tokenSecond, tokenElapsed:
+			if t.kind == tokenElapsed && t.text[0] != 's' {
+				break
+			}
+			if prev >= 0 && fs.tokens[prev].kind == tokenMonth && len(fs.tokens[prev].text) <= 2 {
+				fs.tokens[prev].kind = tokenMinute
+			}
+		case tokenLiteral, tokenPoint, tokenSubsecond:
+			continue
+		}
+		prev = i
+	}
+}
+
+// resolveCommas resolves the commas of the number section, the commas between digit placeholders of
+// the integer part are thousands separators, and the commas after the digit placeholders scale the
+// number by thousand. The resolved commas are replaced by empty literals.
+func (fs *fmtSection) resolveCommas() {
+	var (
+		lastDig = -1 // the index of the last digit placeholder of the integer and decimal parts
+		pointAt = -1 // the index of the decimal point
+	)
+	for i, t := range fs.tokens {
+		switch t.kind {
+		case tokenDigit:
+			lastDig = i
+		case tokenPoint:
+			if pointAt < 0 {
+				pointAt = i
+			}
+		case tokenPercent:
+			fs.percent++
+		}
+		if t.kind == tokenExponent || t.kind == tokenSlash {
+			break
+		}
+	}
+
+	for i, t := range fs.tokens {
+		if t.kind != tokenComma {
+			continue
+		}
+		switch {
+		case i > lastDig:
+			if n := len(fs.tokens); i+1 == n || fs.tokens[i+1].kind != tokenDigit {
+				fs.scale++
+			}
+		case pointAt < 0 || i < pointAt:
+			fs.thousands = true
+		}
+		fs.tokens[i] = fmtToken{kind: tokenLiteral}
+	}
+}
+
+// match reports whether the value satisfies the condition of the section.
+func (fs *fmtSection) match(v float64) bool {
+	switch fs.cond {
+	case ">":
+		return v > fs.limit
+	case "<":
+		return v < fs.limit
+	case "=":
+		return v == fs.limit
+	case ">=":
+		return v >= fs.limit
+	case "<=":
+		return v <= fs.limit
+	case "<>":
+		return v != fs.limit
+	}
+	return true
+}
+
+// isDate reports whether the number format is a date or time format.
+func (nf *numFmt) isDate() bool {
+	return len(nf.sections) > 0 && nf.sections[0].date
+}
+
+// section returns the section to format the value, and whether the value is formatted as its absolute value.
+func (nf *numFmt) section(v float64) (*fmtSection, bool) {
+	sections := nf.sections
+	if n := len(sections); n > 1 && sections[n-1].text && !sections[0].text {
+		// the last section is for text
+		sections = sections[:n-1]
+	}
+	switch len(sections) {
+	case 0:
+		return nil, false
+	case 1:
+		return sections[0], false
+	}
+
+	if sections[0].cond != "" || sections[1].cond != "" {
+		for i, fs := range sections {
+			if fs.cond == "" || fs.match(v) {
+				// the section without condition of [>=0];[<0] like formats shows the absolute value
+				return fs, i > 0 && fs.cond == "" && v < 0
+			}
+		}
+		return sections[len(sections)-1], false
+	}
+
+	switch {
+	case v > 0 || (v == 0 && len(sections) == 2):
+		return sections[0], false
+	case v < 0:
+		return sections[1], true
+	default:
+		return sections[2], false
+	}
+}
+
+// format formats the number as Excel displays it.
+//
+// Parameters:
+//   - v: the number.
+//   - date1904: the date system of the workbook is 1904 or 1900.
+//
+// Returns:
+//   - string: the formatted text.
+func (nf *numFmt) format(v float64, date1904 bool) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return formatGeneral(v)
+	}
+	fs, abs := nf.section(v)
+	if fs == nil || fs.text {
+		return formatGeneral(v)
+	}
+	if abs {
+		v = math.Abs(v)
+	}
+	if fs.date {
+		if t, ok := serialTime(v, date1904); ok {
+			return fs.formatDate(v, t, date1904)
+		}
+		return formatGeneral(v)
+	}
+
+	return fs.formatNumber(v)
+}
+
+// formatText formats the text by the text section(@) of the number format.
+func (nf *numFmt) formatText(s string) string {
+	for _, fs := range nf.sections {
+		if !fs.text {
+			continue
+		}
+		out := new(strings.Builder)
+		for _, t := range fs.tokens {
+			switch t.kind {
+			case tokenText:
+				out.WriteString(s)
+			case tokenLiteral:
+				out.WriteString(t.text)
+			}
+		}
+		return out.String()
+	}
+
+	return s
+}
+
+// formatGeneral formats the number by the General format, as the shortest decimal of the number rounded to
+// 15 significant digits like Excel stores it, e.g. 0.1+0.2 is "0.3" and 4006381333931 is "4006381333931".
+// The digits are not cut by the column width as Excel displays, only the numbers too large or too small to
+// write as decimals(at least 1e21 or less than 1e-9) are written in scientific notation, like "1.5E+300".
+func formatGeneral(v float64) string {
+	if v == 0 {
+		return "0"
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	// the shortest decimal which rounds to the same 15 significant digits
+	v, _ = strconv.ParseFloat(strconv.FormatFloat(v, 'g', 15, 64), 64)
+	if abs := math.Abs(v); abs >= 1e21 || abs < 1e-9 {
+		return strconv.FormatFloat(v, 'E', -1, 64)
+	}
+
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// serialTime converts the serial number of the date system to the time.
+//
+// In the 1900 date system the serial 1 is 1900-01-01 and the serial 60 is the nonexistent 1900-02-29 for
+// the compatibility with Lotus 1-2-3, in the 1904 date system the serial 0 is 1904-01-01.
+func serialTime(v float64, date1904 bool) (time.Time, bool) {
+	if v < 0 || v > 2958466 || math.IsNaN(v) {
+		return time.Time{}, false
+	}
+
+	var (
+		days  = math.Floor(v)
+		ms    = math.Round((v - days) * 86400000)
+		epoch time.Time
+	)
+	switch {
+	case date1904:
+		epoch = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+	case days < 60:
+		epoch = time.Date(1899, 12, 31, 0, 0, 0, 0, time.UTC)
+	default:
+		epoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	}
+
+	return epoch.AddDate(0, 0, int(days)).Add(time.Duration(ms) * time.Millisecond), true
+}
+
+// timeSerial converts the time to the serial number of the date system.
+func timeSerial(t time.Time, date1904 bool) float64 {
+	epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	if date1904 {
+		epoch = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	v := float64(t.Sub(epoch)) / float64(24*time.Hour)
+	if !date1904 && v < 61 {
+		v--
+	}
+
+	return v
+}
+
+// formatDate formats the date and time of the serial number.
+func (fs *fmtSection) formatDate(v float64, t time.Time, date1904 bool) string {
+	var (
+		out      = new(strings.Builder)
+		hour12   = false
+		subDigit = 0
+	)
+	for _, tk := range fs.tokens {
+		switch tk.kind {
+		case tokenAmPm:
+			hour12 = true
+		case tokenSubsecond:
+			subDigit = max(subDigit, len(tk.text))
+		}
+	}
+
+	// the time is rounded to the displayed precision of seconds
+	unit := time.Second
+	for i := 0; i < subDigit && i < 3; i++ {
+		unit /= 10
+	}
+	t = t.Round(unit)
+	elapsed := time.Duration(math.Round(v*86400/unit.Seconds())) * unit
+
+	// the serial 0 is displayed as 1900-01-00 and the serial 60 as 1900-02-29 in the 1900 date system
+	year, month, day := t.Date()
+	if days := math.Floor(v); !date1904 && days == 0 {
+		year, month, day = 1900, time.January, 0
+	} else if !date1904 && days == 60 {
+		year, month, day = 1900, time.February, 29
+	}
+
+	for _, tk := range fs.tokens {
+		switch tk.kind {
+		case tokenLiteral:
+			out.WriteString(tk.text)
+		case tokenPoint:
+			out.WriteString(".")
+		case tokenComma, tokenPercent, tokenSlash:
+			out.WriteString(tk.text)
+		case tokenDigit:
+			if tk.text == "0" {
+				out.WriteString("0")
+			}
+		case tokenYear:
+			if len(tk.text) <= 2 {
+				out.WriteString(pad2(year % 100))
+			} else {
+				out.WriteString(strconv.Itoa(year))
+			}
+		case tokenMonth:
+			switch len(tk.text) {
+			case 1:
+				out.WriteString(strconv.Itoa(int(month)))
+			case 2:
+				out.WriteString(pad2(int(month)))
+			case 3:
+				out.WriteString(month.String()[:3])
+			case 5:
+				out.WriteString(month.String()[:1])
+			default:
+				out.WriteString(month.String())
+			}
+		case tokenDay:
+			switch len(tk.text) {
+			case 1:
+				out.WriteString(strconv.Itoa(day))
+			case 2:
+				out.WriteString(pad2(day))
+			case 3:
+				out.WriteString(t.Weekday().String()[:3])
+			default:
+				out.WriteString(t.Weekday().String())
+			}
+		case tokenHour:
+			h := t.Hour()
+			if hour12 {
+				if h = h % 12; h == 0 {
+					h = 12
+				}
+			}
+			if len(tk.text) >= 2 {
+				out.WriteString(pad2(h))
+			} else {
+				out.WriteString(strconv.Itoa(h))
+			}
+		case tokenMinute:
+			if len(tk.text) >= 2 {
+				out.WriteString(pad2(t.Minute()))
+			} else {
+				out.WriteString(strconv.Itoa(t.Minute()))
+			}
+		case tokenSecond:
+			if len(tk.text) >= 2 {
+				out.WriteString(pad2(t.Second()))
+			} else {
+				out.WriteString(strconv.Itoa(t.Second()))
+			}
+		case tokenSubsecond:
+			frac := strconv.Itoa(1000 + t.Nanosecond()/int(time.Millisecond))[1:]
+			out.WriteString(frac[:min(len(tk.text), 3)])
+		case tokenAmPm:
+			am, pm := "AM", "PM"
+			if len(tk.text) == 3 {
+				am, pm = tk.text[:1], tk.text[2:]
+			} else if tk.text[0] == 'a' {
+				am, pm = "am", "pm"
+			}
+			if t.Hour() < 12 {
+				out.WriteString(am)
+			} else {
+				out.WriteString(pm)
+			}
+		case tokenElapsed:
+			var n int64
+			switch tk.text[0] {
+			case 'h':
+				n = int64(elapsed / time.Hour)
+			case 'm':
+				n = int64(elapsed / time.Minute)
+			default:
+				n = int64(elapsed / time.Second)
+			}
+			s := strconv.FormatInt(n, 10)
+			for len(s) < len(tk.text) {
+				s = "0" + s
+			}
+			out.WriteString(s)
+		}
+	}
+
+	return out.String()
+}
+
+// pad2 formats the number with at least 2 digits.
+func pad2(n int) string {
+	if n < 10 {
+		return "0" + strconv.Itoa(n)
+	}
+	return strconv.Itoa(n)
+}
+
+// formatNumber formats the number by the digit placeholders, thousands separators, percent,
+// scientific notation and fractions of the section.
+func (fs *fmtSection) formatNumber(v float64) string {
+	var (
+		tokens   = fs.tokens
+		negative = v < 0
+		lastDig  = -1 // the index of the last digit placeholder
+		pointAt  = -1 // the index of the decimal point
+		expAt    = -1 // the index of the exponent
+		slashAt  = -1 // the index of the slash of fractions
+	)
+	v = math.Abs(v) * math.Pow(100, float64(fs.percent)) / math.Pow(1000, float64(fs.scale))
+	if math.IsInf(v, 0) {
+		// overflowed by the percent
+		return formatGeneral(v)
+	}
+
+	for i, t := range tokens {
+		switch t.kind {
+		case tokenGeneral:
+			return fs.formatLiterals(formatGeneral(v), negative)
+		case tokenDigit:
+			lastDig = i
+		case tokenPoint:
+			if pointAt < 0 && expAt < 0 {
+				pointAt = i
+			}
+		case tokenExponent:
+			if expAt < 0 {
+				expAt = i
+			}
+		case tokenSlash:
+			if slashAt < 0 && lastDig >= 0 {
+				slashAt = i
+			}
+		}
+	}
+	if lastDig < 0 {
+		return fs.formatLiterals("", negative)
+	}
+	if slashAt >= 0 {
+		return fs.formatFraction(v, negative, slashAt)
+	}
+
+	var (
+		intEnd   = len(tokens) // the end of the integer part
+		fracEnd  = len(tokens) // the end of the fraction part
+		exponent = 0
+		expSign  = ""
+	)
+	if pointAt >= 0 {
+		intEnd = pointAt
+	}
+	if expAt >= 0 {
+		fracEnd = expAt
+		if intEnd > expAt {
+			intEnd = expAt
+		}
+	}
+	intDigits := countDigits(tokens[:intEnd])
+	fracDigits := 0
+	if pointAt >= 0 {
+		fracDigits = countDigits(tokens[pointAt+1 : fracEnd])
+	}
+
+	if expAt >= 0 {
+		if v != 0 {
+			exponent = int(math.Floor(math.Log10(v)))
+			if n := max(intDigits, 1); n > 1 && tokens[0].kind == tokenDigit && tokens[0].text == "#" {
+				// engineering notation like ##0.0E+0
+				exponent = int(math.Floor(float64(exponent)/float64(n))) * n
+			} else {
+				exponent -= n - 1
+			}
+			v /= math.Pow(10, float64(exponent))
+			// the mantissa may be rounded to the next power of ten
+			if roundHalfUp(v, fracDigits) >= math.Pow(10, float64(max(intDigits, 1))) {
+				v /= 10
+				exponent++
+			}
+		}
+		expSign = tokens[expAt].text[1:]
+		if exponent < 0 {
+			expSign = "-"
+		} else if expSign == "-" {
+			expSign = ""
+		}
+	}
+
+	var s string
+	if v >= 1e15 {
+		// Excel keeps 15 significant digits, the rest digits of the integer part are zeros
+		e := strconv.FormatFloat(v, 'e', 14, 64) // like "1.23456789012345e+20"
+		exp, _ := strconv.Atoi(e[17:])
+		s = e[:1] + e[2:16] + strings.Repeat("0", exp-14)
+		if fracDigits > 0 {
+			s += "." + strings.Repeat("0", fracDigits)
+		}
+	} else {
+		s = strconv.FormatFloat(roundHalfUp(v, fracDigits), 'f', fracDigits, 64)
+	}
+	intPart, fracPart := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, fracPart = s[:i], s[i+1:]
+	}
+	if intPart == "0" {
+		intPart = ""
+	}
+	if negative && strings.Trim(intPart+fracPart, "0") == "" {
+		negative = false
+	}
+
+	out := new(strings.Builder)
+	if negative {
+		out.WriteString("-")
+	}
+	fs.writeInteger(out, tokens[:intEnd], intPart, fs.thousands)
+	if pointAt >= 0 && pointAt < fracEnd {
+		out.WriteString(".")
+		writeFraction(out, tokens[pointAt+1:fracEnd], fracPart)
+	}
+	if expAt >= 0 {
+		out.WriteString(tokens[expAt].text[:1])
+		out.WriteString(expSign)
+		expTokens := tokens[expAt+1:]
+		fs.writeInteger(out, expTokens, strconv.Itoa(abs(exponent)), false)
+	}
+
+	return out.String()
+}
+
+// writeInteger writes the integer digits by the placeholders from right to left, the extra digits are
+// written at the first placeholder.
+func (fs *fmtSection) writeInteger(out *strings.Builder, tokens []fmtToken, digits string, thousands bool) {
+	placeholders := countDigits(tokens)
+	if thousands && digits != "" {
+		digits = groupThousands(digits)
+	}
+	var (
+		parts = make([]string, len(tokens))
+		j     = len(digits)
+		seen  = 0
+	)
+	for i := len(tokens) - 1; i >= 0; i-- {
+		t := tokens[i]
+		switch t.kind {
+		case tokenDigit:
+			seen++
+			var d string
+			if j > 0 {
+				start := j - 1
+				// the thousands separator belongs to the digit before it
+				if start > 0 && digits[start-1] == ',' {
+					start--
+				}
+				if seen == placeholders {
+					start = 0
+				}
+				d = digits[start:j]
+				j = start
+			} else {
+				switch t.text {
+				case "0":
+					d = "0"
+				case "?":
+					d = " "
+				}
+			}
+			parts[i] = d
+		case tokenLiteral, tokenPercent:
+			parts[i] = t.text
+		case tokenText:
+			parts[i] = ""
+		}
+	}
+	if placeholders == 0 && digits != "" {
+		out.WriteString(digits)
+	}
+	for _, p := range parts {
+		out.WriteString(p)
+	}
+}
+
+// writeFraction writes the decimal digits by the placeholders from left to right, the trailing zeros of "#"
+// are dropped and those of "?" are replaced by spaces.
+func writeFraction(out *strings.Builder, tokens []fmtToken, digits string) {
+	var (
+		parts = make([]string, len(tokens))
+		j     = 0
+	)
+	for i, t := range tokens {
+		switch t.kind {
+		case tokenDigit:
+			if j < len(digits) {
+				parts[i] = digits[j : j+1]
+				j++
+			}
+		case tokenLiteral, tokenPercent:
+			parts[i] = t.text
+		}
+	}
+	for i := len(tokens) - 1; i >= 0; i-- {
+		t := tokens[i]
+		if t.kind != tokenDigit {
+			continue
+		}
+		if parts[i] != "0" || t.text == "0" {
+			break
+		}
+		if t.text == "?" {
+			parts[i] = " "
+		} else {
+			parts[i] = ""
+		}
+	}
+	for _, p := range parts {
+		out.WriteString(p)
+	}
+}
+
+// formatFraction formats the number as a fraction like "# ?/?" or "?/8".
+func (fs *fmtSection) formatFraction(v float64, negative bool, slashAt int) string {
+	var (
+		tokens   = fs.tokens
+		numStart = slashAt // the start of the numerator placeholders
+		intEnd   = -1      // the end of the integer placeholders
+	)
+	for numStart > 0 && tokens[numStart-1].kind == tokenDigit {
+		numStart--
+	}
+	for i := numStart - 1; i >= 0; i-- {
+		if tokens[i].kind == tokenDigit {
+			intEnd = i + 1
+			break
+		}
+	}
+
+	// the denominator is a fixed number or the count of its placeholders limits the digits
+	var (
+		denEnd   = slashAt + 1
+		fixedDen = 0
+	)
+	for denEnd < len(tokens) && tokens[denEnd].kind == tokenDigit {
+		denEnd++
+	}
+	if denEnd == slashAt+1 && denEnd < len(tokens) && tokens[denEnd].kind == tokenLiteral {
+		digits := strings.TrimRightFunc(tokens[denEnd].text, func(r rune) bool { return r < '0' || r > '9' })
+		fixedDen, _ = strconv.Atoi(digits)
+	}
+
+	whole := 0.0
+	frac := v
+	if intEnd >= 0 {
+		whole = math.Floor(v)
+		frac = v - whole
+	}
+	var num, den int
+	if fixedDen > 0 {
+		den = fixedDen
+		num = int(math.Round(frac * float64(den)))
+	} else {
+		num, den = approximate(frac, int(math.Pow(10, float64(denEnd-slashAt-1)))-1)
+	}
+	if intEnd >= 0 && num == den {
+		whole++
+		num = 0
+	}
+
+	out := new(strings.Builder)
+	if negative && (whole != 0 || num != 0) {
+		out.WriteString("-")
+	}
+	if intEnd >= 0 {
+		intDigits := ""
+		if whole != 0 || num == 0 {
+			intDigits = strconv.FormatFloat(whole, 'f', 0, 64)
+		}
+		fs.writeInteger(out, tokens[:intEnd], intDigits, false)
+		if num == 0 {
+			// the fraction part is blank for integers
+			return strings.TrimSpace(out.String())
+		}
+		for _, t := range tokens[intEnd:numStart] {
+			out.WriteString(t.text)
+		}
+	} else {
+		for _, t := range tokens[:numStart] {
+			out.WriteString(t.text)
+		}
+	}
+	fs.writeInteger(out, tokens[numStart:slashAt], strconv.Itoa(num), false)
+	out.WriteString("/")
+	if fixedDen == 0 {
+		out.WriteString(strconv.Itoa(den))
+	}
+	for _, t := range tokens[denEnd:] {
+		if t.kind == tokenLiteral {
+			out.WriteString(t.text)
+		}
+	}
+
+	return strings.TrimSpace(out.String())
+}
+
+// roundHalfUp rounds the number half away from zero to the decimal digits like Excel, the number is
+// rounded to 15 significant digits first to drop the binary floating point errors, e.g. 1.005 is 1.01.
+func roundHalfUp(v float64, digits int) float64 {
+	pow := math.Pow(10, float64(digits))
+	scaled, _ := strconv.ParseFloat(strconv.FormatFloat(v*pow, 'g', 15, 64), 64)
+	if r := math.Round(scaled) / pow; !math.IsInf(r, 0) && !math.IsNaN(r) {
+		return r
+	}
+	return v
+}
+
+// approximate returns the closest fraction of the number with the denominator at most maxDen.
+func approximate(v float64, maxDen int) (int, int) {
+	if maxDen < 1 {
+		maxDen = 1
+	}
+	bestNum, bestDen, bestErr := int(math.Round(v)), 1, math.Abs(v-math.Round(v))
+	for den := 2; den <= maxDen && bestErr > 0; den++ {
+		num := int(math.Round(v * float64(den)))
+		if err := math.Abs(v - float64(num)/float64(den)); err < bestErr-1e-12 {
+			bestNum, bestDen, bestErr = num, den, err
+		}
+	}
+
+	return bestNum, bestDen
+}
+
+// formatLiterals formats the section without digit placeholders, like "General" with literals or a literal text.
+func (fs *fmtSection) formatLiterals(general string, negative bool) string {
+	out := new(strings.Builder)
+	if negative && general != "" {
+		out.WriteString("-")
+	}
+	for _, t := range fs.tokens {
+		switch t.kind {
+		case tokenGeneral:
+			out.WriteString(general)
+		case tokenLiteral, tokenPercent, tokenComma, tokenPoint, tokenSlash:
+			out.WriteString(t.text)
+		}
+	}
+
+	return out.String()
+}
+
+// groupThousands inserts the thousands separators into the integer digits.
+func groupThousands(digits string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+	out := make([]byte, 0, len(digits)+len(digits)/3)
+	for i := 0; i < len(digits); i++ {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			out = append(out, ',')
+		}
+		out = append(out, digits[i])
+	}
+
+	return string(out)
+}
+
+// countDigits returns the number of the digit placeholders.
+func countDigits(tokens []fmtToken) int {
+	n := 0
+	for _, t := range tokens {
+		if t.kind == tokenDigit {
+			n++
+		}
+	}
+	return n
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -8,24 +8,15 @@ package xlsxtotext
 import (
 	"archive/zip"
 	"bytes"
-	"fmt"
 	"io"
 	"regexp"
-	"strconv"
 
+	"github.com/young2j/oxmltotext/opc"
+	"github.com/young2j/oxmltotext/types"
 	"github.com/young2j/oxmltotext/utils"
 )
 
-var (
-	re_SHARED       = regexp.MustCompile(`xl/sharedStrings\.xml`)
-	re_SHEET        = regexp.MustCompile(`xl/worksheets/sheet(\d+)\.xml`)
-	re_SHEET_RELS   = regexp.MustCompile(`xl/worksheets/_rels/sheet(\d+)\.xml\.rels`)
-	re_CHARTS       = regexp.MustCompile(`xl/charts/chart\d+\.xml`)
-	re_IMAGES       = regexp.MustCompile(`xl/media/image\d+\.(?:png|gif|jpg|jpeg)`)
-	re_DIAGRAMS     = regexp.MustCompile(`xl/diagrams/data\d+\.xml`)
-	re_DRAWINGS     = regexp.MustCompile(`xl/drawings/drawing\d+\.xml`)
-	re_DRAWING_RELS = regexp.MustCompile(`xl/drawings/_rels/drawing(\d+)\.xml\.rels`)
-)
+var re_IMAGES = regexp.MustCompile(`\.(?i:png|gif|jpg|jpeg)$`)
 
 // Open opens the specified xlsx file path and returns a new XlsxParser instance and an error, if any.
 //
@@ -97,9 +88,14 @@ func OpenURL(u string) (*XlsxParser, int, error) {
 	return xp, statusCode, err
 }
 
-// matchZipFile iterates through the files in a zip.Reader
-// and populates various maps in the XlsxParser struct
-// based on the file names and their contents.
+// matchZipFile discovers the parts of the xlsx file by the relationships of the package
+// and populates various maps in the XlsxParser struct.
+//
+// The workbook part is the target of officeDocument relationship(so .xlsm/.xltx/.xltm and renamed parts
+// are supported), the worksheets(numbered by the tab order of the workbook, see parseWorkbook) and shared
+// strings are the targets of the workbook relationships, the drawings are the targets of the worksheet
+// relationships, and the charts, images, diagrams and embeddings are the targets of the relationships of
+// any part reachable from the package.
 //
 // Parameters:
 // - xp: A pointer to an XlsxParser struct.
@@ -108,72 +104,69 @@ func OpenURL(u string) (*XlsxParser, int, error) {
 // Returns:
 // - error: An error if any occurred during the iteration and population process.
 func matchZipFile(xp *XlsxParser, r *zip.Reader) error {
-	sheetsNum := max(len(r.File)-10, 1)
-	xp.sheetFiles = make(map[int]*zip.File, sheetsNum)
+	pkg, err := opc.Open(r)
+	if err != nil {
+		return err
+	}
+
+	xp.pkg = pkg
 	xp.chartsFiles = make(map[string]*zip.File, 4)
 	xp.imagesFiles = make(map[string]*zip.File, 4)
 	xp.diagramsFiles = make(map[string]*zip.File, 4)
 	xp.drawingsFile = make(map[string]*zip.File, 4)
-	xp.sheetRelsMap = make(map[int]map[string]string, sheetsNum)
-	xp.drawingRelsMap = make(map[string]map[string]string, sheetsNum)
-
-	for _, file := range r.File {
-		switch {
-		case re_SHARED.MatchString(file.Name):
-			xp.sharedStringsFile = file
-		case re_CHARTS.MatchString(file.Name):
-			xp.chartsFiles[file.Name] = file
-		case re_IMAGES.MatchString(file.Name):
-			xp.imagesFiles[file.Name] = file
-		case re_DIAGRAMS.MatchString(file.Name):
-			xp.diagramsFiles[file.Name] = file
-		case re_DRAWINGS.MatchString(file.Name):
-			xp.drawingsFile[file.Name] = file
-
-		default:
-			matches := re_SHEET.FindStringSubmatch(file.Name)
-			if len(matches) > 1 {
-				i, err := strconv.Atoi(matches[1])
-				if err != nil {
-					xp.logWarn(err)
-					continue
-				}
-				xp.sheetFiles[i] = file
-				continue
-			}
+	xp.embedsFiles = make(map[string]*zip.File, 4)
+	xp.drawingRelsMap = make(map[string]map[string]string, 4)
 
-			matches = re_SHEET_RELS.FindStringSubmatch(file.Name)
-			if len(matches) > 1 {
-				i, err := strconv.Atoi(matches[1])
-				if err != nil {
-					xp.logWarn(err)
-					continue
-				}
-				relsMap, err := utils.ParseRelsMap(file, "xl/")
-				if err != nil {
-					return err
-				}
-				xp.sheetRelsMap[i] = relsMap
-				continue
-			}
+	main := pkg.MainPart("xl/workbook.xml")
+	if main == "" {
+		return types.ErrNonePart
+	}
+	if shared := pkg.Related(main, "sharedStrings"); len(shared) > 0 {
+		xp.sharedStringsFile = pkg.File(shared[0])
+	}
+	if styles := pkg.Related(main, "styles"); len(styles) > 0 {
+		xp.stylesFile = pkg.File(styles[0])
+	}
+	if persons := pkg.Related(main, "person"); len(persons) > 0 {
+		xp.personsFile = pkg.File(persons[0])
+	}
 
-			matches = re_DRAWING_RELS.FindStringSubmatch(file.Name)
-			if len(matches) > 1 {
-				i, err := strconv.Atoi(matches[1])
-				if err != nil {
-					xp.logWarn(err)
-					continue
-				}
-				relsMap, err := utils.ParseRelsMap(file, "xl/")
-				if err != nil {
-					return err
-				}
-				k := fmt.Sprintf("xl/drawings/drawing%d.xml", i)
-				xp.drawingRelsMap[k] = relsMap
-				continue
-			}
+	// the worksheets are numbered by the tab order of the workbook
+	wb, err := parseWorkbook(pkg, main)
+	if err != nil {
+		return err
+	}
+	xp.workbook = wb
+	xp.sheetFiles = make(map[int]*zip.File, len(wb.sheets))
+	xp.sheetRelsMap = make(map[int]map[string]string, len(wb.sheets))
+	for i, sheet := range wb.sheets {
+		xp.sheetFiles[i+1] = pkg.File(sheet.part)
+		if xp.sheetRelsMap[i+1], err = pkg.RelsMap(sheet.part); err != nil {
+			return err
 		}
 	}
 
-	return nil
+	return pkg.Walk(func(source string, rel opc.Relationship) {
+		file := pkg.File(rel.Target)
+		switch rel.Kind() {
+		case "drawing":
+			if _, ok := xp.drawingsFile[rel.Target]; ok {
+				return
+			}
+			xp.drawingsFile[rel.Target] = file
+			relsMap, err := pkg.RelsMap(rel.Target)
+			xp.logWarn(err)
+			xp.drawingRelsMap[rel.Target] = relsMap
+		case "chart":
+			xp.chartsFiles[rel.Target] = file
+		case "image":
+			if re_IMAGES.MatchString(rel.Target) {
+				xp.imagesFiles[rel.Target] = file
+			}
+		case "diagramData":
+			xp.diagramsFiles[rel.Target] = file
+		case "oleObject", "package":
+			xp.embedsFiles[rel.Target] = file
+		}
+	})
 }
@@ -0,0 +1,71 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package xlsxtotext
+
+import (
+	"github.com/young2j/oxmltotext/opc"
+	"github.com/young2j/oxmltotext/types"
+	"github.com/young2j/oxmltotext/utils"
+
+	qxml "github.com/dgrr/quickxml"
+)
+
+// parseImageInfos parses the alt text(xdr:cNvPr) of the images referenced by the drawing parts.
+// The alt text is read from the first drawing with alt text referencing the image.
+//
+// Parameters:
+//   - None
+//
+// Returns:
+//   - map[string]*types.Image: the images with alt text, keyed by the image part name.
+//   - error: an error if any.
+func (xp *XlsxParser) parseImageInfos() (map[string]*types.Image, error) {
+	infos := make(map[string]*types.Image, len(xp.imagesFiles))
+
+	for drawingName, f := range xp.drawingsFile {
+		rc, err := opc.OpenPart(f)
+		if err != nil {
+			return infos, err
+		}
+
+		var (
+			drawingRels  = xp.drawingRelsMap[drawingName]
+			title, descr = "", ""
+		)
+		r := qxml.NewReader(rc)
+		for r.Next() {
+			e, ok := r.Element().(*qxml.StartElement)
+			if !ok {
+				continue
+			}
+			switch e.Name() {
+			case "xdr:cNvPr":
+				title, descr = utils.DrawingAltText(e)
+
+			case "a:blip":
+				rId := e.Attrs().Get("r:embed")
+				if rId == nil {
+					continue
+				}
+				name, ok := drawingRels[rId.Value()]
+				if !ok {
+					continue
+				}
+				info, ok := infos[name]
+				if !ok {
+					info = &types.Image{Name: name}
+					infos[name] = info
+				}
+				if info.Title == "" && info.AltText == "" {
+					info.Title, info.AltText = title, descr
+				}
+			}
+		}
+		rc.Close()
+	}
+
+	return infos, nil
+}
@@ -11,6 +11,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/young2j/oxmltotext/opc"
 	"github.com/young2j/oxmltotext/types"
 	"github.com/young2j/oxmltotext/utils"
 
@@ -21,6 +22,7 @@ import (
 //
 // The function iterates through the XML elements of the drawing part reader and
 // extracts the drawings(charts, images, and diagrams) if the corresponding flags are set.
+// The alt text of images(xdr:cNvPr) is written alongside them if enabled.
 //
 // Parameters:
 //   - i: the index of the sheet
@@ -47,7 +49,7 @@ func (xp *XlsxParser) extractDrawings(i int, rId string) *strings.Builder {
 	if !ok {
 		return nil
 	}
-	rc, err := f.Open()
+	rc, err := opc.OpenPart(f)
 	if err != nil {
 		log.Println(err)
 		return nil
@@ -55,7 +57,10 @@ func (xp *XlsxParser) extractDrawings(i int, rId string) *strings.Builder {
 	defer rc.Close()
 
 	r := qxml.NewReader(rc)
-	var texts = new(strings.Builder)
+	var (
+		texts   = new(strings.Builder)
+		altText = ""
+	)
 
 	for r.Next() {
 		switch e := r.Element().(type) {
@@ -85,7 +90,16 @@ func (xp *XlsxParser) extractDrawings(i int, rId string) *strings.Builder {
 					}
 				}
 
-			case e.Name() == "a:blip" && xp.parseImages:
+			case e.Name() == "xdr:cNvPr":
+				altText = utils.JoinAltText(utils.DrawingAltText(e))
+
+			case e.Name() == "a:blip":
+				if altText != "" && (xp.parseAltText || xp.parseImages && xp.preferAltText) {
+					texts.WriteString(utils.FormatBox(altText, "alt text", xp.drawingsNoFmt).String())
+				}
+				if !xp.parseImages || xp.preferAltText && altText != "" {
+					continue
+				}
 				attrs := e.Attrs()
 				if attrs.Len() > 0 {
 					rIdKV := attrs.Get("r:embed")
@@ -128,7 +142,7 @@ func (xp *XlsxParser) extractChart(drawingName string, rId string) (*strings.Bui
 		return nil, types.ErrNonePart
 	}
 
-	rc, err := f.Open()
+	rc, err := opc.OpenPart(f)
 	if err != nil {
 		return nil, err
 	}
@@ -290,7 +304,7 @@ func (xp *XlsxParser) extractDiagram(drawingName string, rId string) (*strings.B
 		return nil, types.ErrNonePart
 	}
 
-	rc, err := f.Open()
+	rc, err := opc.OpenPart(f)
 	if err != nil {
 		return nil, err
 	}
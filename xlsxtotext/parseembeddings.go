@@ -0,0 +1,70 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package xlsxtotext
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/young2j/oxmltotext/types"
+	"github.com/young2j/oxmltotext/utils"
+)
+
+// extractEmbedding extracts text content from the embedded object by the embed interface.
+//
+// Parameters:
+//   - i: the index of the sheet.
+//   - rId: the reference id of the embedded object.
+//
+// Returns:
+//   - *strings.Builder: the formatted text of the embedded object.
+//   - error: any error that occurred during the extraction process.
+func (xp *XlsxParser) extractEmbedding(i int, rId string) (*strings.Builder, error) {
+	if xp.embedsMaxDepth <= 0 {
+		return nil, nil
+	}
+
+	if rId == "" {
+		return nil, types.ErrEmptyRID
+	}
+
+	if xp.embed == nil {
+		return nil, types.ErrNilEmbed
+	}
+
+	sheetRels, ok := xp.sheetRelsMap[i]
+	if !ok {
+		return nil, types.ErrNonePart
+	}
+
+	fname, ok := sheetRels[rId]
+	if !ok {
+		return nil, types.ErrNonePart
+	}
+
+	f, ok := xp.embedsFiles[fname]
+	if !ok {
+		return nil, types.ErrNonePart
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := xp.embed.Run(bytes.NewReader(data), int64(len(data)), fname, xp.embedsMaxDepth-1)
+	if err != nil {
+		return nil, err
+	}
+	return utils.FormatBox(text, "embedding", xp.drawingsNoFmt), nil
+}
@@ -0,0 +1,259 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package xlsxtotext
+
+import (
+	"strings"
+
+	"github.com/young2j/oxmltotext/types"
+)
+
+// cellRange is a range of cells of a worksheet.
+type cellRange struct {
+	sheet                  int // the sheet number(start 1)
+	col1, row1, col2, row2 int
+}
+
+// parseAreaRef parses the A1 style area reference, the whole columns(like "A:C") and the whole rows(like "1:5")
+// are the ranges to the bounds of the worksheet.
+//
+// Parameters:
+//   - ref: the area reference like "A1:F200", "$A$1:$F$200", "B2", "A:C" or "1:5".
+//
+// Returns:
+//   - col1, row1, col2, row2: the column and row numbers of the top left and bottom right cells.
+//   - ok: false if the reference is invalid.
+func parseAreaRef(ref string) (col1, row1, col2, row2 int, ok bool) {
+	col1, row1, col2, row2 = parseRangeRef(strings.TrimSpace(ref))
+	switch {
+	case col1 > 0 && row1 > 0:
+	case col1 > 0 && row2 == 0:
+		row1, row2 = 1, maxSheetRows
+	case col2 == 0 && row1 > 0:
+		col1, col2 = 1, maxSheetColumns
+	default:
+		return 0, 0, 0, 0, false
+	}
+	if col2 > maxSheetColumns || row2 > maxSheetRows {
+		return 0, 0, 0, 0, false
+	}
+
+	return col1, row1, col2, row2, true
+}
+
+// splitAreas splits the reference formula of a defined name into the areas with their sheet names,
+// like "'Q1 Sales'!$A$1:$B$2,Sheet2!$C:$C".
+//
+// Parameters:
+//   - formula: the reference formula.
+//
+// Returns:
+//   - sheets: the unquoted sheet names of the areas, empty if the area has no sheet name.
+//   - areas: the area references.
+func splitAreas(formula string) (sheets, areas []string) {
+	formula = strings.TrimPrefix(strings.TrimSpace(formula), "=")
+	if strings.HasPrefix(formula, "(") && strings.HasSuffix(formula, ")") {
+		formula = formula[1 : len(formula)-1]
+	}
+
+	var (
+		sheet   = new(strings.Builder)
+		area    = new(strings.Builder)
+		inQuote = false
+	)
+	for i := 0; i < len(formula); i++ {
+		c := formula[i]
+		switch {
+		case c == '\'' && inQuote && i+1 < len(formula) && formula[i+1] == '\'':
+			area.WriteByte(c)
+			i++
+		case c == '\'':
+			inQuote = !inQuote
+		case c == '!' && !inQuote:
+			sheet.Reset()
+			sheet.WriteString(area.String())
+			area.Reset()
+		case c == ',' && !inQuote:
+			sheets = append(sheets, sheet.String())
+			areas = append(areas, area.String())
+			sheet.Reset()
+			area.Reset()
+		default:
+			area.WriteByte(c)
+		}
+	}
+	sheets = append(sheets, sheet.String())
+	areas = append(areas, area.String())
+
+	return sheets, areas
+}
+
+// DefinedNames returns the defined names of the workbook, the names scoped to a sheet are prefixed by
+// the sheet name, like "Sheet1!SalesData".
+func (xp *XlsxParser) DefinedNames() []string {
+	names := make([]string, 0, len(xp.workbook.names))
+	for _, dn := range xp.workbook.names {
+		if dn.sheet != "" {
+			names = append(names, dn.sheet+"!"+dn.name)
+		} else {
+			names = append(names, dn.name)
+		}
+	}
+
+	return names
+}
+
+// ExtractRange extracts the texts of the cell range of the specified xlsx sheet.
+//
+// The cell values are placed in their columns from the first column of the range, and the sheet is read
+// until the last row of the range. The hidden mode and sheet heading are not applied to the range.
+//
+// Parameters:
+//   - sheet: the sheet number(start 1).
+//   - ref: the A1 style range like "A1:F200", "$A$1:$F$200", "A:C"(whole columns) or "1:5"(whole rows).
+//
+// Returns:
+//   - string: the texts of the range.
+//   - error: types.ErrNoSheet if the sheet is not found, types.ErrBadRange if the range is invalid,
+//     or an error if there is any issue with parsing the sheet.
+func (xp *XlsxParser) ExtractRange(sheet int, ref string) (string, error) {
+	if _, ok := xp.sheetFiles[sheet]; !ok {
+		return "", types.ErrNoSheet
+	}
+	col1, row1, col2, row2, ok := parseAreaRef(ref)
+	if !ok {
+		return "", types.ErrBadRange
+	}
+
+	texts, err := xp.parseRange(cellRange{sheet, col1, row1, col2, row2})
+	if err != nil {
+		return "", err
+	}
+
+	return texts.String(), nil
+}
+
+// ExtractDefinedName extracts the texts of the cell ranges referred by the defined name(definedNames of the workbook).
+//
+// The name is matched case-insensitively, the name scoped to a sheet can be specified by the sheet name like
+// "Sheet1!SalesData", otherwise the name of the workbook scope is preferred to the names scoped to sheets.
+// The texts of multiple areas(like "Sheet1!$A$1:$B$2,Sheet1!$D$1:$E$2") are concatenated, see ExtractRange.
+//
+// Parameters:
+//   - name: the defined name, see DefinedNames.
+//
+// Returns:
+//   - string: the texts of the ranges.
+//   - error: types.ErrNoDefinedName if the name is not found, types.ErrBadRange if the name does not refer to
+//     the ranges of the worksheets(like constants and formulas), or an error if there is any issue with parsing the sheets.
+func (xp *XlsxParser) ExtractDefinedName(name string) (string, error) {
+	dn, ok := xp.definedName(name)
+	if !ok {
+		return "", types.ErrNoDefinedName
+	}
+
+	sheets, areas := splitAreas(dn.ref)
+	ranges := make([]cellRange, 0, len(areas))
+	for i, area := range areas {
+		sheetName := sheets[i]
+		if sheetName == "" {
+			sheetName = dn.sheet
+		}
+		sheet, ok := xp.sheetIndex(sheetName)
+		if !ok {
+			return "", types.ErrBadRange
+		}
+		col1, row1, col2, row2, ok := parseAreaRef(area)
+		if !ok {
+			return "", types.ErrBadRange
+		}
+		ranges = append(ranges, cellRange{sheet, col1, row1, col2, row2})
+	}
+
+	texts := new(strings.Builder)
+	for _, cr := range ranges {
+		rangeTexts, err := xp.parseRange(cr)
+		if err != nil {
+			return texts.String(), err
+		}
+		texts.WriteString(rangeTexts.String())
+	}
+
+	return texts.String(), nil
+}
+
+// definedName returns the defined name by the name, which can be prefixed by the sheet name of its scope.
+func (xp *XlsxParser) definedName(name string) (definedName, bool) {
+	scope := ""
+	if i := strings.LastIndexByte(name, '!'); i >= 0 {
+		scope = strings.Trim(name[:i], "'")
+		name = name[i+1:]
+	}
+
+	var (
+		found definedName
+		ok    bool
+	)
+	for _, dn := range xp.workbook.names {
+		if !strings.EqualFold(dn.name, name) {
+			continue
+		}
+		if scope != "" {
+			if strings.EqualFold(dn.sheet, scope) {
+				return dn, true
+			}
+			continue
+		}
+		if dn.sheet == "" {
+			return dn, true
+		}
+		if !ok {
+			found, ok = dn, true
+		}
+	}
+
+	return found, ok
+}
+
+// parseRange parses the cell range of the sheet and returns the texts, see ExtractRange.
+func (xp *XlsxParser) parseRange(cr cellRange) (*strings.Builder, error) {
+	if !xp.shareParsed {
+		err := xp.parseSharedStrings()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sr, err := xp.newSheetReader(cr.sheet, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer sr.close()
+
+	var (
+		texts = new(strings.Builder)
+		rw    = &rowWriter{xp: xp, texts: texts, prevRow: cr.row1 - 1}
+		sub   = new(sheetRow) // the cells of the row in the range
+	)
+	for row := sr.next(); row != nil; row = sr.next() {
+		if row.num < cr.row1 {
+			continue
+		}
+		if row.num > cr.row2 {
+			break
+		}
+		sub.reset(row.num)
+		if len(row.cells) >= cr.col1 {
+			sub.cells = append(sub.cells, row.cells[cr.col1-1:min(cr.col2, len(row.cells))]...)
+		}
+		rw.write(sub)
+	}
+	if err = sr.err(); err != nil {
+		return nil, err
+	}
+
+	return texts, nil
+}
@@ -0,0 +1,378 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package xlsxtotext
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/young2j/oxmltotext/types"
+)
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Records is the iterator of the records of a xlsx sheet with a header row, the values of a record are
+// keyed by the column names of the header.
+//
+//	type Sale struct {
+//		Product string    `xlsx:"Product"`
+//		Amount  float64   `xlsx:"Amount"`
+//		Date    time.Time `xlsx:"Order Date"`
+//	}
+//
+//	records, err := xp.Records(1)
+//	if err != nil {
+//		return err
+//	}
+//	defer records.Close()
+//	for records.Next() {
+//		var sale Sale
+//		if err := records.Decode(&sale); err != nil {
+//			log.Println(err) // the errors of the row, see types.RowError
+//			continue
+//		}
+//	}
+//	return records.Err()
+type Records struct {
+	rows      *Rows
+	header    []string
+	headerRow int
+	cells     []types.Cell // the cells of the current record
+	date1904  bool         // the serial numbers of dates are in the 1904 date system
+
+	decodeType reflect.Type   // the struct type of the decoded fields
+	fields     []decodedField // the fields of the struct type matched by the header
+}
+
+// decodedField is a struct field decoded from a column.
+type decodedField struct {
+	index []int  // the index of the struct field
+	name  string // the name of the struct field
+	col   int    // the index of the column
+}
+
+// Records returns the iterator of the records of the specified xlsx sheet.
+//
+// The header row is the first non-empty row or the row set by SetHeaderRow, the rows before it are skipped.
+// The column names are the trimmed values of the header cells, the columns without names are named by
+// their column letters(like "C"), and the duplicate names are suffixed by their occurrences(like "Name_2").
+// The empty rows after the header row are skipped.
+//
+// Parameters:
+//   - sheet: the sheet number(start 1).
+//
+// Returns:
+//   - *Records: the iterator of the records, which should be closed after iterating.
+//   - error: types.ErrNoSheet if the sheet is not found, types.ErrNoHeader if the header row is not found,
+//     or an error if the sheet can not be read.
+func (xp *XlsxParser) Records(sheet int) (*Records, error) {
+	rows, err := xp.Rows(sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	for rows.Next() {
+		if xp.headerRow > 0 && rows.Row() < xp.headerRow {
+			continue
+		}
+		if xp.headerRow > 0 && rows.Row() > xp.headerRow {
+			break
+		}
+		header := rows.Columns()
+		if len(header) == 0 {
+			continue
+		}
+		rs := &Records{
+			rows:      rows,
+			header:    make([]string, len(header)),
+			headerRow: rows.Row(),
+			date1904:  xp.workbook.date1904,
+		}
+		seen := make(map[string]int, len(header))
+		for i, cell := range header {
+			name := strings.TrimSpace(cell.Value)
+			if name == "" {
+				name = columnName(i + 1)
+			}
+			if seen[name]++; seen[name] > 1 {
+				name += "_" + strconv.Itoa(seen[name])
+			}
+			rs.header[i] = name
+		}
+		return rs, nil
+	}
+
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, types.ErrNoHeader
+}
+
+// Header returns the column names of the header row.
+func (rs *Records) Header() []string {
+	return rs.header
+}
+
+// HeaderRow returns the row number(start 1) of the header row.
+func (rs *Records) HeaderRow() int {
+	return rs.headerRow
+}
+
+// Next advances to the next non-empty record, false if there are no more records or an error occurred, see Err.
+func (rs *Records) Next() bool {
+	for rs.rows.Next() {
+		cells := rs.rows.Columns()
+		for _, cell := range cells {
+			if cell.Value != "" {
+				rs.cells = cells
+				return true
+			}
+		}
+	}
+	rs.cells = nil
+
+	return false
+}
+
+// Row returns the row number(start 1) of the current record.
+func (rs *Records) Row() int {
+	return rs.rows.Row()
+}
+
+// Cells returns the typed cells of the current record placed by their columns, see Rows.Columns.
+func (rs *Records) Cells() []types.Cell {
+	return rs.cells
+}
+
+// Record returns the values of the current record keyed by the column names of the header,
+// the columns beyond the header are ignored and the missing cells are empty.
+func (rs *Records) Record() map[string]string {
+	record := make(map[string]string, len(rs.header))
+	for i, name := range rs.header {
+		if i < len(rs.cells) {
+			record[name] = rs.cells[i].Value
+		} else {
+			record[name] = ""
+		}
+	}
+
+	return record
+}
+
+// Decode decodes the current record into the struct pointed by v.
+//
+// The struct fields are matched with the columns by the names of the `xlsx:"Column Name"` tags, or the field
+// names if not tagged, case-insensitively. The fields tagged by `xlsx:"-"`, unexported fields and the fields
+// without columns are skipped. The cells are converted to the field types: string, bool, integers, floats,
+// time.Time(the dates and serial numbers, or the texts in RFC 3339 and "2006-01-02 15:04:05" layouts),
+// encoding.TextUnmarshaler and the pointers to them, the empty cells are decoded as zero values.
+//
+// Parameters:
+//   - v: the non-nil pointer to a struct.
+//
+// Returns:
+//   - error: types.ErrBadDecodeTarget if v is not a non-nil pointer to a struct, or the joined *types.RowError
+//     of the cells can not be converted, the other fields are decoded still.
+func (rs *Records) Decode(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return types.ErrBadDecodeTarget
+	}
+	rv = rv.Elem()
+	if rv.Type() != rs.decodeType {
+		rs.decodeType = rv.Type()
+		rs.fields = rs.matchFields(rv.Type())
+	}
+
+	var errs []error
+	for _, f := range rs.fields {
+		var cell types.Cell
+		if f.col < len(rs.cells) {
+			cell = rs.cells[f.col]
+		}
+		if err := decodeCell(rv.FieldByIndex(f.index), cell, rs.date1904); err != nil {
+			errs = append(errs, &types.RowError{
+				Row:    rs.Row(),
+				Column: rs.header[f.col],
+				Field:  f.name,
+				Value:  cell.Value,
+				Err:    err,
+			})
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// matchFields matches the fields of the struct type with the columns of the header.
+func (rs *Records) matchFields(t reflect.Type) []decodedField {
+	fields := make([]decodedField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name := sf.Name
+		if tag, ok := sf.Tag.Lookup("xlsx"); ok {
+			if tag == "-" {
+				continue
+			}
+			if tag != "" {
+				name = tag
+			}
+		}
+		for col, column := range rs.header {
+			if strings.EqualFold(column, strings.TrimSpace(name)) {
+				fields = append(fields, decodedField{index: sf.Index, name: sf.Name, col: col})
+				break
+			}
+		}
+	}
+
+	return fields
+}
+
+// Err returns the error occurred during the iteration, if any.
+func (rs *Records) Err() error {
+	return rs.rows.Err()
+}
+
+// Close closes the worksheet.
+func (rs *Records) Close() error {
+	return rs.rows.Close()
+}
+
+// decodeCell converts the cell to the value of the field, the serial numbers of dates are in the 1904 date system
+// if date1904 is true.
+func decodeCell(fv reflect.Value, cell types.Cell, date1904 bool) error {
+	if fv.Kind() == reflect.Pointer {
+		if cell.Value == "" {
+			fv.SetZero()
+			return nil
+		}
+		if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		fv = fv.Elem()
+	}
+	text := strings.TrimSpace(cell.Value)
+	if fv.Type() == timeType {
+		if text == "" {
+			fv.SetZero()
+			return nil
+		}
+		t, err := cellTime(cell, text, date1904)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(t))
+		return nil
+	}
+	if fv.CanAddr() && fv.Addr().Type().Implements(textUnmarshalerType) {
+		return fv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(cell.Value))
+	}
+	if cell.Value == "" {
+		fv.SetZero()
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(cell.Value)
+	case reflect.Bool:
+		if cell.Type == types.CellBool {
+			fv.SetBool(cell.Bool)
+			return nil
+		}
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := cellNumber(cell, text)
+		if err != nil {
+			return err
+		}
+		if n != math.Trunc(n) {
+			return fmt.Errorf("%v is not an integer", n)
+		}
+		// the float is checked before converting, the conversion of an out of range float is undefined
+		if n < math.MinInt64 || n >= math.MaxInt64 || fv.OverflowInt(int64(n)) {
+			return fmt.Errorf("%v out of range of %s", n, fv.Type())
+		}
+		fv.SetInt(int64(n))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := cellNumber(cell, text)
+		if err != nil {
+			return err
+		}
+		if n != math.Trunc(n) {
+			return fmt.Errorf("%v is not an integer", n)
+		}
+		if n < 0 || n >= math.MaxUint64 || fv.OverflowUint(uint64(n)) {
+			return fmt.Errorf("%v out of range of %s", n, fv.Type())
+		}
+		fv.SetUint(uint64(n))
+	case reflect.Float32, reflect.Float64:
+		n, err := cellNumber(cell, text)
+		if err != nil {
+			return err
+		}
+		if fv.OverflowFloat(n) {
+			return fmt.Errorf("%v out of range of %s", n, fv.Type())
+		}
+		fv.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported type %s", fv.Type())
+	}
+
+	return nil
+}
+
+// cellNumber returns the number of the numbers and dates, or parses the text of the other cells.
+func cellNumber(cell types.Cell, text string) (float64, error) {
+	if cell.Type == types.CellNumber || cell.Type == types.CellDate {
+		return cell.Number, nil
+	}
+
+	return strconv.ParseFloat(strings.ReplaceAll(text, ",", ""), 64)
+}
+
+// cellTime returns the time of the dates, converts the serial numbers, or parses the text of the other cells.
+func cellTime(cell types.Cell, text string, date1904 bool) (time.Time, error) {
+	switch cell.Type {
+	case types.CellDate:
+		return cell.Time, nil
+	case types.CellNumber:
+		if t, ok := serialTime(cell.Number, date1904); ok {
+			return t, nil
+		}
+		return time.Time{}, fmt.Errorf("%v out of range of dates", cell.Number)
+	}
+
+	var err error
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02"} {
+		var t time.Time
+		if t, err = time.Parse(layout, text); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, err
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package xlsxtotext
+
+import (
+	"strconv"
+
+	"github.com/young2j/oxmltotext/types"
+)
+
+// Rows is the iterator of the rows of a xlsx sheet, the rows are read from the worksheet one by one
+// and the memory of a row is reused for the next row.
+//
+//	rows, err := xp.Rows(1)
+//	if err != nil {
+//		return err
+//	}
+//	defer rows.Close()
+//	for rows.Next() {
+//		cells := rows.Columns()
+//	}
+//	return rows.Err()
+type Rows struct {
+	sr  *sheetReader
+	row *sheetRow
+}
+
+// Rows returns the iterator of the rows of the specified xlsx sheet.
+//
+// The rows not written in the worksheet are skipped(except the rows covered by merged cells in
+// types.MergeRepeat mode), the row number of the current row is returned by Row. The hidden mode is
+// not applied to the rows.
+//
+// Parameters:
+//   - sheet: the sheet number(start 1).
+//
+// Returns:
+//   - *Rows: the iterator of the rows, which should be closed after iterating.
+//   - error: types.ErrNoSheet if the sheet is not found, or an error if the sheet can not be read.
+func (xp *XlsxParser) Rows(sheet int) (*Rows, error) {
+	if !xp.shareParsed {
+		err := xp.parseSharedStrings()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sr, err := xp.newSheetReader(sheet, nil)
+	if err != nil {
+		return nil, err
+	}
+	sr.typed = true
+
+	return &Rows{sr: sr}, nil
+}
+
+// Next advances to the next row, false if there are no more rows or an error occurred, see Err.
+func (rs *Rows) Next() bool {
+	rs.row = rs.sr.next()
+	return rs.row != nil
+}
+
+// Row returns the row number(start 1) of the current row.
+func (rs *Rows) Row() int {
+	if rs.row == nil {
+		return 0
+	}
+
+	return rs.row.num
+}
+
+// Columns returns the typed cells of the current row placed by their columns, the cell of column A is
+// Columns()[0] and the trailing empty cells are trimmed, the cells not written in the worksheet are
+// types.CellEmpty. The returned cells are valid until the next call of Next.
+func (rs *Rows) Columns() []types.Cell {
+	if rs.row == nil {
+		return nil
+	}
+
+	cells := rs.row.typed
+	for len(cells) > 0 && cells[len(cells)-1].Type == types.CellEmpty && cells[len(cells)-1].Value == "" {
+		cells = cells[:len(cells)-1]
+	}
+	for i := range cells {
+		if cells[i].Col == 0 {
+			cells[i] = types.Cell{
+				Ref:     columnName(i+1) + strconv.Itoa(rs.row.num),
+				Col:     i + 1,
+				Row:     rs.row.num,
+				ColSpan: 1,
+				RowSpan: 1,
+			}
+		}
+	}
+
+	return cells
+}
+
+// Err returns the error occurred during the iteration, if any.
+func (rs *Rows) Err() error {
+	return rs.sr.err()
+}
+
+// Close closes the worksheet.
+func (rs *Rows) Close() error {
+	return rs.sr.close()
+}
@@ -0,0 +1,353 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package xlsxtotext
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/young2j/oxmltotext/opc"
+	"github.com/young2j/oxmltotext/types"
+
+	qxml "github.com/dgrr/quickxml"
+)
+
+// sheetReader reads the rows of a worksheet one by one, the row buffer is reused for each row.
+type sheetReader struct {
+	xp       *XlsxParser
+	index    int // the index of the sheet(start 1)
+	rc       io.ReadCloser
+	r        *qxml.Reader
+	row      *sheetRow      // the current row
+	gap      *sheetRow      // the row covered by merged cells but not written in the worksheet
+	prevRow  int            // the number of the previous row
+	pending  bool           // the current row is read but not returned for the gap rows before it
+	done     bool           // the end of the worksheet is reached
+	formulas *sheetFormulas // the shared and array formulas
+	merges   *sheetMerges   // the merged cells, nil in types.MergeBlank mode
+	comments *sheetComments // the comments written inline, nil if not inlined
+	width    int            // the number of columns of the used range(dimension@ref), 0 if unknown
+	extras   *strings.Builder
+	embedded map[string]bool
+	typed    bool // reads the typed cells of the rows, see sheetRow.typed
+
+	cellType       string // the type of the cell(c@t)
+	cellStyle      int    // the style of the cell(c@s)
+	cellValue      string // the text of the cell value(c/v or c/is)
+	rawValue       string // the raw cell value(c/v)
+	hasValue       bool   // the cell has a cached value
+	formula        string // the formula of the cell(c/f)
+	inlineText     *strings.Builder
+	inlinePhonetic *strings.Builder
+	inInline       bool // in the inline string(c/is)
+	inPhonetic     bool // in the phonetic run(rPh) of the inline string
+}
+
+// newSheetReader opens the worksheet at the given index for reading its rows.
+//
+// Parameters:
+//   - i: the index of the sheet(start 1).
+//   - extras: the builder to write the texts of the drawings and embeddings of the sheet to, nil to skip them.
+//
+// Returns:
+//   - *sheetReader: the reader of the worksheet, which should be closed after reading.
+//   - error: types.ErrNoSheet if the sheet does not exist, or an error if the worksheet can not be read.
+func (xp *XlsxParser) newSheetReader(i int, extras *strings.Builder) (*sheetReader, error) {
+	sheetFile, ok := xp.sheetFiles[i]
+	if !ok {
+		return nil, types.ErrNoSheet
+	}
+	if xp.styles == nil {
+		xp.logWarn(xp.parseStyles())
+	}
+
+	sr := &sheetReader{
+		xp:             xp,
+		index:          i,
+		row:            new(sheetRow),
+		formulas:       new(sheetFormulas),
+		extras:         extras,
+		embedded:       make(map[string]bool),
+		inlineText:     new(strings.Builder),
+		inlinePhonetic: new(strings.Builder),
+	}
+	if xp.mergeMode != types.MergeBlank {
+		merges, err := parseMergeCells(sheetFile)
+		if err != nil {
+			return nil, err
+		}
+		sr.merges = merges
+	}
+	if xp.inlineComments {
+		comments, err := xp.ExtractComments(i)
+		if err != nil {
+			return nil, err
+		}
+		sr.comments = newSheetComments(comments)
+	}
+
+	rc, err := opc.OpenPart(sheetFile)
+	if err != nil {
+		return nil, err
+	}
+	sr.rc = rc
+	sr.r = qxml.NewReader(rc)
+
+	return sr, nil
+}
+
+// close closes the worksheet.
+func (sr *sheetReader) close() error {
+	return sr.rc.Close()
+}
+
+// err returns the error of reading the worksheet, nil at the end of the worksheet.
+func (sr *sheetReader) err() error {
+	if err := sr.r.Error(); err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
+// next returns the next row of the worksheet, nil at the end of the worksheet.
+//
+// The rows not written in the worksheet are skipped, except the rows covered by merged cells in
+// types.MergeRepeat mode, which are filled with the values of the top left cells, and the rows with
+// the comments written inline.
+// The returned row is valid until the next call.
+func (sr *sheetReader) next() *sheetRow {
+	if !sr.pending && !sr.done {
+		sr.done = !sr.readRow()
+		sr.pending = !sr.done
+	}
+
+	to := sr.row.num - 1
+	if sr.done && sr.merges != nil {
+		to = sr.merges.maxRow
+	}
+	if sr.done && sr.comments != nil {
+		to = max(to, sr.comments.maxRow)
+	}
+	num := 0
+	if sr.xp.mergeMode == types.MergeRepeat {
+		num = sr.merges.coveredRow(sr.prevRow+1, to)
+	}
+	if n := sr.comments.commentedRow(sr.prevRow+1, to); n > 0 && (num == 0 || n < num) {
+		num = n
+	}
+	if num > 0 {
+		if sr.gap == nil {
+			sr.gap = new(sheetRow)
+		}
+		sr.gap.reset(num)
+		if sr.xp.mergeMode == types.MergeRepeat {
+			sr.merges.fill(sr.gap, sr.xp.maxColumns, sr.typed)
+		}
+		sr.comments.fill(sr.gap, sr.xp.maxColumns)
+		sr.prevRow = num
+		return sr.gap
+	}
+	if sr.done {
+		return nil
+	}
+
+	sr.pending = false
+	sr.prevRow = sr.row.num
+	if sr.xp.mergeMode == types.MergeRepeat {
+		sr.merges.fill(sr.row, sr.xp.maxColumns, sr.typed)
+	}
+	sr.comments.fill(sr.row, sr.xp.maxColumns)
+
+	return sr.row
+}
+
+// inColumns reports whether the current cell is in the columns to read, see SetMaxColumns.
+func (sr *sheetReader) inColumns() bool {
+	return sr.row.col > 0 && (sr.xp.maxColumns <= 0 || sr.row.col <= sr.xp.maxColumns)
+}
+
+// endCell sets the text of the current cell to the row.
+func (sr *sheetReader) endCell() {
+	if !sr.inColumns() {
+		return
+	}
+	if sr.formula == "" && len(sr.formulas.ranges) > 0 {
+		sr.formula = sr.formulas.rangeFormula(sr.row.col, sr.row.num)
+	}
+	text := sr.xp.formulaText(sr.formula, sr.cellValue, sr.hasValue)
+	sr.row.set(sr.row.col, text)
+
+	var cell types.Cell
+	if sr.typed {
+		cell = types.Cell{
+			Ref:     columnName(sr.row.col) + strconv.Itoa(sr.row.num),
+			Col:     sr.row.col,
+			Row:     sr.row.num,
+			Value:   text,
+			ColSpan: 1,
+			RowSpan: 1,
+			Formula: sr.formula,
+		}
+		if sr.hasValue {
+			sr.xp.typedCell(&cell, sr.cellType, sr.rawValue, sr.cellStyle)
+		}
+		sr.row.setTyped(sr.row.col, cell)
+	}
+	sr.merges.record(sr.row.col, sr.row.num, text, cell)
+}
+
+// readRow reads the next row(sheetData/row) into the row buffer, the drawings and embeddings after
+// the sheet data are written to the extras.
+//
+// Returns:
+//   - bool: false if there are no more rows.
+func (sr *sheetReader) readRow() bool {
+	var (
+		xp               = sr.xp
+		r                = sr.r
+		row              = sr.row
+		cellValueOrIndex = ""
+	)
+
+	for r.Next() {
+		switch e := r.Element().(type) {
+		case *qxml.EndElement:
+			switch e.Name() {
+			case "row":
+				return true
+			case "c":
+				sr.endCell()
+			case "is":
+				sr.cellValue = xp.phoneticText(sr.inlineText.String(), sr.inlinePhonetic.String())
+				sr.hasValue = true
+				sr.inInline = false
+			case "rPh":
+				sr.inPhonetic = false
+			}
+		case *qxml.StartElement:
+			switch e.Name() {
+			case "row":
+				num := sr.prevRow + 1
+				if rKV := e.Attrs().Get("r"); rKV != nil {
+					if n, err := strconv.Atoi(rKV.Value()); err == nil && n > sr.prevRow {
+						num = n
+					}
+				}
+				row.reset(num)
+				if e.HasEnd() {
+					return true
+				}
+
+			case "dimension":
+				if ref := e.Attrs().Get("ref"); ref != nil {
+					if col1, _, col2, _ := parseRangeRef(ref.Value()); col1 > 0 {
+						sr.width = col2
+					}
+				}
+
+			case "c":
+				row.col++
+				if rKV := e.Attrs().Get("r"); rKV != nil {
+					if col, _ := parseCellRef(rKV.Value()); col > row.col {
+						row.col = col
+					}
+				}
+				sr.cellType, sr.cellStyle = "", 0
+				sr.cellValue, sr.rawValue, sr.hasValue, sr.formula = "", "", false, ""
+				if tKV := e.Attrs().Get("t"); tKV != nil {
+					sr.cellType = tKV.Value()
+				}
+				if sKV := e.Attrs().Get("s"); sKV != nil {
+					sr.cellStyle, _ = strconv.Atoi(sKV.Value())
+				}
+				if e.HasEnd() {
+					sr.endCell()
+				}
+
+			case "v":
+				sr.hasValue = true
+				if e.HasEnd() {
+					continue
+				}
+				r.AssignNext(&cellValueOrIndex)
+				if !r.Next() {
+					return false
+				}
+				sr.cellValue = xp.cellText(sr.cellType, cellValueOrIndex, sr.cellStyle)
+				sr.rawValue = cellValueOrIndex
+				cellValueOrIndex = ""
+
+			case "f":
+				f := newCellFormula(e.Attrs())
+				if !e.HasEnd() {
+					r.AssignNext(&f.text)
+					if !r.Next() {
+						return false
+					}
+				}
+				sr.formula = sr.formulas.formula(f, row.col, row.num)
+
+			case "is":
+				sr.inInline = !e.HasEnd()
+				sr.inlineText.Reset()
+				sr.inlinePhonetic.Reset()
+
+			case "rPh":
+				sr.inPhonetic = !e.HasEnd()
+
+			case "t":
+				if !sr.inInline || e.HasEnd() {
+					continue
+				}
+				r.AssignNext(&cellValueOrIndex)
+				if !r.Next() {
+					return false
+				}
+				if sr.inPhonetic {
+					sr.inlinePhonetic.WriteString(cellValueOrIndex)
+				} else {
+					sr.inlineText.WriteString(cellValueOrIndex)
+				}
+				cellValueOrIndex = ""
+
+			case "drawing":
+				if sr.extras == nil {
+					continue
+				}
+				attrs := e.Attrs()
+				if attrs.Len() > 0 {
+					rIdKV := attrs.Get("r:id")
+					drawings := xp.extractDrawings(sr.index, rIdKV.Value())
+					if drawings != nil {
+						sr.extras.WriteString(drawings.String())
+					}
+				}
+
+			case "oleObject":
+				if sr.extras == nil || !xp.parseEmbeds {
+					continue
+				}
+				attrs := e.Attrs()
+				if attrs.Len() > 0 {
+					rIdKV := attrs.Get("r:id")
+					if rIdKV == nil || sr.embedded[rIdKV.Value()] {
+						continue
+					}
+					sr.embedded[rIdKV.Value()] = true
+					embedding, err := xp.extractEmbedding(sr.index, rIdKV.Value())
+					xp.logWarn(err)
+					if embedding != nil {
+						sr.extras.WriteString(embedding.String())
+					}
+				}
+			}
+		}
+	}
+
+	return false
+}
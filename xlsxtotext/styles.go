@@ -0,0 +1,108 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package xlsxtotext
+
+import (
+	"html"
+	"strconv"
+
+	"github.com/young2j/oxmltotext/opc"
+
+	qxml "github.com/dgrr/quickxml"
+)
+
+// cellStyles is the number formats of the cell formats(cellXfs) of the styles part.
+type cellStyles struct {
+	codes   map[int]string  // the custom number format codes keyed by numFmtId
+	xfs     []int           // the numFmtId of the cell formats, indexed by the cell style(c@s)
+	numFmts map[int]*numFmt // the parsed number formats keyed by numFmtId
+}
+
+// numFmt returns the number format of the cell style.
+//
+// Parameters:
+//   - style: the index of the cell format(c@s).
+//
+// Returns:
+//   - *numFmt: the number format, nil for the General format or the unknown formats.
+func (cs *cellStyles) numFmt(style int) *numFmt {
+	if style < 0 || style >= len(cs.xfs) {
+		return nil
+	}
+	id := cs.xfs[style]
+	if nf, ok := cs.numFmts[id]; ok {
+		return nf
+	}
+
+	code, ok := cs.codes[id]
+	if !ok {
+		code, ok = builtinNumFmts[id]
+	}
+	var nf *numFmt
+	if ok && code != "General" {
+		nf = parseNumFmt(code)
+	}
+	cs.numFmts[id] = nf
+
+	return nf
+}
+
+// parseStyles parses the custom number formats(numFmts) and the cell formats(cellXfs) of the styles part.
+//
+// Returns:
+//   - error: an error if the styles part can not be read.
+func (xp *XlsxParser) parseStyles() error {
+	xp.styles = &cellStyles{
+		codes:   make(map[int]string, 4),
+		xfs:     make([]int, 0, 8),
+		numFmts: make(map[int]*numFmt, 4),
+	}
+	if xp.stylesFile == nil {
+		return nil
+	}
+
+	rc, err := opc.OpenPart(xp.stylesFile)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	inCellXfs := false
+	r := qxml.NewReader(rc)
+	for r.Next() {
+		switch e := r.Element().(type) {
+		case *qxml.StartElement:
+			switch e.Name() {
+			case "numFmt":
+				attrs := e.Attrs()
+				id, code := attrs.Get("numFmtId"), attrs.Get("formatCode")
+				if id == nil || code == nil {
+					continue
+				}
+				if n, err := strconv.Atoi(id.Value()); err == nil {
+					xp.styles.codes[n] = html.UnescapeString(code.Value())
+				}
+			case "cellXfs":
+				inCellXfs = !e.HasEnd()
+			case "xf":
+				if !inCellXfs {
+					continue
+				}
+				id := 0
+				if idKV := e.Attrs().Get("numFmtId"); idKV != nil {
+					id, _ = strconv.Atoi(idKV.Value())
+				}
+				xp.styles.xfs = append(xp.styles.xfs, id)
+			}
+		case *qxml.EndElement:
+			if e.Name() == "cellXfs" {
+				inCellXfs = false
+			}
+		}
+	}
+
+	return nil
+}
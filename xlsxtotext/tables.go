@@ -0,0 +1,275 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package xlsxtotext
+
+import (
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/young2j/oxmltotext/opc"
+	"github.com/young2j/oxmltotext/types"
+
+	qxml "github.com/dgrr/quickxml"
+)
+
+// tableInfo is a table(ListObject) of a worksheet.
+type tableInfo struct {
+	name        string // the name of the table(table@name)
+	displayName string // the name shown by Excel and used by the structured references(table@displayName)
+	ref         string // the range of the table(table@ref)
+	cr          cellRange
+	headerRows  int      // the number of header rows(table@headerRowCount), 0 or 1
+	totalsRows  int      // the number of totals rows(table@totalsRowCount), 0 or 1
+	columns     []string // the column names(tableColumns/tableColumn@name)
+}
+
+// parseTables parses the tables of the worksheets, which are the targets of the table relationships
+// of the worksheets, in the tab order of the sheets.
+func (xp *XlsxParser) parseTables() error {
+	xp.tables = make([]tableInfo, 0, 4)
+	for i, sheet := range xp.workbook.sheets {
+		for _, name := range xp.pkg.Related(sheet.part, "table") {
+			ti, ok, err := xp.parseTable(name)
+			if err != nil {
+				return err
+			}
+			if ok {
+				ti.cr.sheet = i + 1
+				xp.tables = append(xp.tables, ti)
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseTable parses the table part.
+//
+// Parameters:
+//   - name: the part name of the table, like "xl/tables/table1.xml".
+//
+// Returns:
+//   - tableInfo: the table.
+//   - bool: false if the table has no valid range.
+//   - error: an error if the table part can not be read.
+func (xp *XlsxParser) parseTable(name string) (tableInfo, bool, error) {
+	ti := tableInfo{headerRows: 1}
+	file := xp.pkg.File(name)
+	if file == nil {
+		return ti, false, nil
+	}
+	rc, err := opc.OpenPart(file)
+	if err != nil {
+		return ti, false, err
+	}
+	defer rc.Close()
+
+	r := qxml.NewReader(rc)
+	for r.Next() {
+		e, ok := r.Element().(*qxml.StartElement)
+		if !ok {
+			continue
+		}
+		attrs := e.Attrs()
+		switch e.Name() {
+		case "table":
+			if kv := attrs.Get("name"); kv != nil {
+				ti.name = html.UnescapeString(kv.Value())
+			}
+			if kv := attrs.Get("displayName"); kv != nil {
+				ti.displayName = html.UnescapeString(kv.Value())
+			}
+			if kv := attrs.Get("ref"); kv != nil {
+				ti.ref = kv.Value()
+			}
+			if kv := attrs.Get("headerRowCount"); kv != nil {
+				ti.headerRows, _ = strconv.Atoi(kv.Value())
+			}
+			if kv := attrs.Get("totalsRowCount"); kv != nil {
+				ti.totalsRows, _ = strconv.Atoi(kv.Value())
+			}
+		case "tableColumn":
+			column := ""
+			if kv := attrs.Get("name"); kv != nil {
+				column = html.UnescapeString(kv.Value())
+			}
+			ti.columns = append(ti.columns, column)
+		}
+	}
+
+	col1, row1, col2, row2 := parseRangeRef(ti.ref)
+	if col1 == 0 || row1 == 0 || col2 > maxSheetColumns || row2 > maxSheetRows {
+		return ti, false, nil
+	}
+	ti.cr = cellRange{col1: col1, row1: row1, col2: col2, row2: row2}
+	ti.headerRows = min(max(ti.headerRows, 0), 1)
+	ti.totalsRows = min(max(ti.totalsRows, 0), 1)
+	if ti.displayName == "" {
+		ti.displayName = ti.name
+	}
+
+	return ti, true, nil
+}
+
+// Tables returns the tables(ListObjects) of the worksheets with their rows, in the tab order of the sheets.
+//
+// The column names are the names of the table columns, the columns without names are named by their column
+// letters(like "C"). The header and totals rows are not included in the data rows. The hidden mode is not
+// applied to the tables.
+//
+// Returns:
+//   - []types.Table: the tables.
+//   - error: an error if there is any issue with parsing the tables or the sheets.
+func (xp *XlsxParser) Tables() ([]types.Table, error) {
+	if xp.tables == nil {
+		if err := xp.parseTables(); err != nil {
+			return nil, err
+		}
+	}
+
+	tables := make([]types.Table, 0, len(xp.tables))
+	for i := range xp.tables {
+		table, err := xp.readTable(&xp.tables[i])
+		if err != nil {
+			return tables, err
+		}
+		tables = append(tables, table)
+	}
+
+	return tables, nil
+}
+
+// TableNames returns the names of the tables(table@displayName) in the tab order of the sheets,
+// without reading the sheets.
+//
+// Returns:
+//   - []string: the names of the tables.
+//   - error: an error if the tables can not be parsed.
+func (xp *XlsxParser) TableNames() ([]string, error) {
+	if xp.tables == nil {
+		if err := xp.parseTables(); err != nil {
+			return nil, err
+		}
+	}
+
+	names := make([]string, 0, len(xp.tables))
+	for _, ti := range xp.tables {
+		names = append(names, ti.displayName)
+	}
+
+	return names, nil
+}
+
+// ExtractTable extracts the table by its name, the sheet of the table is read only until the last row of
+// the table, see Tables.
+//
+// Parameters:
+//   - name: the name of the table(table@displayName or table@name), case-insensitive like Excel.
+//
+// Returns:
+//   - types.Table: the table.
+//   - error: types.ErrNoTable if the table is not found, or an error if there is any issue with parsing the sheet.
+func (xp *XlsxParser) ExtractTable(name string) (types.Table, error) {
+	if xp.tables == nil {
+		if err := xp.parseTables(); err != nil {
+			return types.Table{}, err
+		}
+	}
+
+	for i := range xp.tables {
+		ti := &xp.tables[i]
+		if strings.EqualFold(ti.displayName, name) || strings.EqualFold(ti.name, name) {
+			return xp.readTable(ti)
+		}
+	}
+
+	return types.Table{}, types.ErrNoTable
+}
+
+// readTable reads the rows of the table from its sheet.
+//
+// The rows are built from the rows read from the sheet, the rows and cells not written in the sheet are padded
+// afterwards within the used range of the sheet, so a table range larger than the sheet data is not allocated.
+func (xp *XlsxParser) readTable(ti *tableInfo) (types.Table, error) {
+	cr := ti.cr
+	table := types.Table{
+		Name:  ti.displayName,
+		Sheet: xp.workbook.sheets[cr.sheet-1].name,
+		Ref:   ti.ref,
+	}
+
+	if !xp.shareParsed {
+		err := xp.parseSharedStrings()
+		if err != nil {
+			return table, err
+		}
+	}
+	sr, err := xp.newSheetReader(cr.sheet, nil)
+	if err != nil {
+		return table, err
+	}
+	defer sr.close()
+
+	var (
+		rows    [][]string
+		lastRow = cr.row1 - 1 // the last row read in the table
+		lastCol = 0           // the last column with values read in the table
+	)
+	for row := sr.next(); row != nil; row = sr.next() {
+		if row.num < cr.row1 {
+			continue
+		}
+		if row.num > cr.row2 {
+			break
+		}
+		for ; lastRow < row.num-1; lastRow++ {
+			rows = append(rows, nil)
+		}
+		lastRow = row.num
+		var cells []string
+		if len(row.cells) >= cr.col1 {
+			cells = append(cells, row.cells[cr.col1-1:min(cr.col2, len(row.cells))]...)
+			lastCol = max(lastCol, cr.col1+len(cells)-1)
+		}
+		rows = append(rows, cells)
+	}
+	if err = sr.err(); err != nil {
+		return table, err
+	}
+
+	// the columns are the named columns and the columns of the used range(dimension) of the sheet
+	width := max(min(cr.col2, max(sr.width, lastCol, cr.col1+len(ti.columns)-1))-cr.col1+1, 0)
+	table.Columns = make([]string, width)
+	for i := range table.Columns {
+		if i < len(ti.columns) && ti.columns[i] != "" {
+			table.Columns[i] = ti.columns[i]
+		} else {
+			table.Columns[i] = columnName(cr.col1 + i)
+		}
+	}
+	for i, cells := range rows {
+		if len(cells) < width {
+			rows[i] = append(cells, make([]string, width-len(cells))...)
+		}
+	}
+
+	if ti.headerRows > 0 && len(rows) > 0 {
+		rows = rows[1:]
+	}
+	if ti.totalsRows > 0 {
+		// the totals row is the last row of the table range, which may be not written in the sheet
+		if lastRow == cr.row2 && len(rows) > 0 {
+			table.Totals = rows[len(rows)-1]
+			rows = rows[:len(rows)-1]
+		} else {
+			table.Totals = make([]string, width)
+		}
+	}
+	table.Rows = rows
+
+	return table, nil
+}
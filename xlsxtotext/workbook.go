@@ -0,0 +1,165 @@
+// Copyright (c) 2023 young2j
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package xlsxtotext
+
+import (
+	"html"
+	"sort"
+	"strconv"
+
+	"github.com/young2j/oxmltotext/opc"
+
+	qxml "github.com/dgrr/quickxml"
+)
+
+// sheetInfo is a worksheet of the workbook.
+type sheetInfo struct {
+	name  string // the sheet name, empty if the worksheet is not listed in the workbook
+	state string // the visibility of the sheet: visible(or empty), hidden or veryHidden
+	part  string // the part name of the worksheet
+}
+
+// hidden reports whether the sheet is hidden or very hidden.
+func (si sheetInfo) hidden() bool {
+	return si.state == "hidden" || si.state == "veryHidden"
+}
+
+// definedName is a defined name of the workbook(definedNames/definedName).
+type definedName struct {
+	name  string // the name, like "SalesData" or "_xlnm.Print_Area"
+	sheet string // the name of the sheet the name is scoped to(definedName@localSheetId), empty for the workbook
+	ref   string // the formula of the name, like "Sheet1!$A$1:$F$200"
+}
+
+// workbook is the workbook part of the xlsx file.
+type workbook struct {
+	sheets   []sheetInfo   // the worksheets in the tab order
+	date1904 bool          // the workbook uses the 1904 date system(workbookPr@date1904)
+	names    []definedName // the defined names
+}
+
+// parseWorkbook parses the worksheets in the tab order of the sheet list(sheets/sheet) of the workbook,
+// the date system of the workbook properties(workbookPr) and the defined names(definedNames).
+//
+// The chartsheets, dialogsheets and macrosheets of the sheet list are skipped, and the worksheets not in the
+// list are appended in the natural order of their part names, like sheet1.xml, sheet2.xml, ..., sheet10.xml.
+//
+// Parameters:
+//   - pkg: the package of the xlsx file.
+//   - main: the workbook part name.
+//
+// Returns:
+//   - *workbook: the parsed workbook.
+//   - error: an error if the workbook part can not be read.
+func parseWorkbook(pkg *opc.Package, main string) (*workbook, error) {
+	var (
+		wb     = &workbook{sheets: make([]sheetInfo, 0, 4)}
+		listed = make(map[string]bool, 4)
+		tabs   = make([]string, 0, 4) // the names of all the sheets in the sheet list, for definedName@localSheetId
+		locals = make([]int, 0, 4)    // the localSheetId of the defined names, -1 for the workbook scope
+	)
+	rels, err := pkg.Relationships(main)
+	if err != nil {
+		return nil, err
+	}
+	worksheets := make(map[string]string, len(rels))
+	for _, rel := range rels {
+		if !rel.External && rel.Kind() == "worksheet" {
+			worksheets[rel.ID] = rel.Target
+		}
+	}
+
+	if file := pkg.File(main); file != nil {
+		rc, err := opc.OpenPart(file)
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+
+		r := qxml.NewReader(rc)
+		for r.Next() {
+			e, ok := r.Element().(*qxml.StartElement)
+			if !ok {
+				continue
+			}
+			if e.Name() == "definedName" {
+				dn, local := definedName{}, -1
+				if name := e.Attrs().Get("name"); name != nil {
+					dn.name = html.UnescapeString(name.Value())
+				}
+				if localSheetId := e.Attrs().Get("localSheetId"); localSheetId != nil {
+					if id, err := strconv.Atoi(localSheetId.Value()); err == nil {
+						local = id
+					}
+				}
+				if !e.HasEnd() {
+					r.AssignNext(&dn.ref)
+					if !r.Next() {
+						break
+					}
+					dn.ref = html.UnescapeString(dn.ref)
+				}
+				wb.names = append(wb.names, dn)
+				locals = append(locals, local)
+				continue
+			}
+			if e.Name() == "workbookPr" {
+				if date1904 := e.Attrs().Get("date1904"); date1904 != nil {
+					wb.date1904 = date1904.Value() == "1" || date1904.Value() == "true"
+				}
+				continue
+			}
+			if e.Name() != "sheet" {
+				continue
+			}
+			attrs := e.Attrs()
+			tab := ""
+			if name := attrs.Get("name"); name != nil {
+				tab = html.UnescapeString(name.Value())
+			}
+			tabs = append(tabs, tab)
+			rId := attrs.Get("r:id")
+			if rId == nil {
+				continue
+			}
+			part, ok := worksheets[rId.Value()]
+			if !ok || pkg.File(part) == nil || listed[part] {
+				continue
+			}
+			sheet := sheetInfo{name: tab, part: part}
+			if state := attrs.Get("state"); state != nil {
+				sheet.state = state.Value()
+			}
+			wb.sheets = append(wb.sheets, sheet)
+			listed[part] = true
+		}
+	}
+
+	for i, local := range locals {
+		if local >= 0 && local < len(tabs) {
+			wb.names[i].sheet = tabs[local]
+		}
+	}
+
+	unlisted := make([]string, 0, len(worksheets))
+	for _, part := range worksheets {
+		if !listed[part] && pkg.File(part) != nil {
+			unlisted = append(unlisted, part)
+			listed[part] = true
+		}
+	}
+	sort.Slice(unlisted, func(i, j int) bool {
+		if len(unlisted[i]) != len(unlisted[j]) {
+			return len(unlisted[i]) < len(unlisted[j])
+		}
+		return unlisted[i] < unlisted[j]
+	})
+	for _, part := range unlisted {
+		wb.sheets = append(wb.sheets, sheetInfo{part: part})
+	}
+
+	return wb, nil
+}
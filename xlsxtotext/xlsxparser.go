@@ -9,6 +9,7 @@ import (
 	"archive/zip"
 	"strings"
 
+	"github.com/young2j/oxmltotext/opc"
 	"github.com/young2j/oxmltotext/types"
 
 	"go.uber.org/zap"
@@ -17,28 +18,60 @@ import (
 // XlsxParser represents the XML file structure and settings for parsing a xlsx file.
 type XlsxParser struct {
 	zipReadCloser     *zip.ReadCloser
+	pkg               *opc.Package
+	workbook          *workbook
 	sharedStringsFile *zip.File
-	sharedStringsMap  map[string]*string
+	sharedStrings     []string
+	stylesFile        *zip.File
+	styles            *cellStyles
+	personsFile       *zip.File
+	persons           map[string]string // the display names of the persons keyed by the ids
+	tables            []tableInfo       // the tables of the worksheets, nil if not parsed
 	sheetFiles        map[int]*zip.File
 	chartsFiles       map[string]*zip.File
 	imagesFiles       map[string]*zip.File
 	diagramsFiles     map[string]*zip.File
 	drawingsFile      map[string]*zip.File
+	embedsFiles       map[string]*zip.File
 	sheetRelsMap      map[int]map[string]string
 	drawingRelsMap    map[string]map[string]string
 
-	parseCharts   bool
-	parseImages   bool
-	parseDiagrams bool
-	drawingsNoFmt bool
-	ocr           types.OCR
+	parseCharts    bool
+	parseImages    bool
+	parseAltText   bool
+	preferAltText  bool
+	parseDiagrams  bool
+	parseEmbeds    bool
+	drawingsNoFmt  bool
+	embedsMaxDepth int
+	ocr            types.OCR
+	embed          types.Embed
 
 	onlySharedStrings bool
+	parsePhonetic     bool
 	sheetSep          string
 	rowSep            string
 	colSep            string
+	sheetHeading      string
+	hiddenMode        types.HiddenMode
+	rawValues         bool
+	formulaMode       types.FormulaMode
+	mergeMode         types.MergeMode
+	inlineComments    bool
+	headerRow         int
+	padGaps           bool
+	maxColumns        int
+	maxColumnWidth    int
+	trimEmpty         bool
 	shareParsed       bool
 
+	csvDelimiter rune
+	csvQuoteMode types.QuoteMode
+	csvEncoding  types.Encoding
+	csvCRLF      bool
+	csvFirstRow  int
+	csvLastRow   int
+
 	logger         *zap.Logger
 	disableLogging bool
 }
@@ -47,10 +80,14 @@ func newXlsxParser() *XlsxParser {
 	logger, _ := zap.NewProduction()
 
 	return &XlsxParser{
-		sharedStringsMap: make(map[string]*string, 0),
-		sheetSep:         strings.Repeat("-", 100) + "\n",
-		rowSep:           "\n",
-		colSep:           "\t",
-		logger:           logger,
+		sheetSep:       strings.Repeat("-", 100) + "\n",
+		rowSep:         "\n",
+		colSep:         "\t",
+		padGaps:        true,
+		trimEmpty:      true,
+		csvDelimiter:   ',',
+		csvCRLF:        true,
+		embedsMaxDepth: 1,
+		logger:         logger,
 	}
 }
@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/young2j/oxmltotext/ocr"
+	"github.com/young2j/oxmltotext/opc"
 	"github.com/young2j/oxmltotext/types"
 
 	qxml "github.com/dgrr/quickxml"
@@ -21,6 +22,12 @@ func (xp *XlsxParser) SetOnlySharedStrings(v bool) {
 	xp.onlySharedStrings = v
 }
 
+// SetParsePhonetic writes the phonetic guides(rPh, like the furigana of Japanese) of strings
+// in parentheses after the text or not. Default is false.
+func (xp *XlsxParser) SetParsePhonetic(v bool) {
+	xp.parsePhonetic = v
+}
+
 // SetSheetSep sets the separator of the sheet text. Default is "-"x100.
 func (xp *XlsxParser) SetSheetSep(sep string) {
 	xp.sheetSep = sep
@@ -36,6 +43,99 @@ func (xp *XlsxParser) SetColSep(sep string) {
 	xp.colSep = sep
 }
 
+// SetSheetHeading sets the format of the heading written at the start of each sheet, the placeholder "%s"
+// is replaced by the sheet name, like "%s\n" or "## %s\n\n". Other text, including a literal "%", is written
+// as is. Default is "", no heading is written.
+func (xp *XlsxParser) SetSheetHeading(format string) {
+	xp.sheetHeading = format
+}
+
+// SetHiddenMode sets the mode of handling hidden sheets(state="hidden" or "veryHidden").
+// Default is types.HiddenInclude.
+func (xp *XlsxParser) SetHiddenMode(mode types.HiddenMode) {
+	xp.hiddenMode = mode
+}
+
+// SetRawValues writes the raw values of cells instead of the values formatted by the number formats
+// of the cell styles(like dates, percentages and currencies) or not. Default is false.
+func (xp *XlsxParser) SetRawValues(v bool) {
+	xp.rawValues = v
+}
+
+// SetFormulaMode sets the mode of writing the cells with formulas, the shared formulas are expanded
+// to each cell of their ranges. Default is types.FormulaValue.
+func (xp *XlsxParser) SetFormulaMode(mode types.FormulaMode) {
+	xp.formulaMode = mode
+}
+
+// SetMergeMode sets the mode of writing the merged cells(mergeCells), see ExtractSheetCells for types.MergeSpan.
+// Default is types.MergeBlank.
+func (xp *XlsxParser) SetMergeMode(mode types.MergeMode) {
+	xp.mergeMode = mode
+}
+
+// SetInlineComments writes the comments(notes and threaded comments with their replies) of cells after
+// the cell values in types.CommentMarkStart and types.CommentMarkEnd or not, see ExtractComments. Default is false.
+func (xp *XlsxParser) SetInlineComments(v bool) {
+	xp.inlineComments = v
+}
+
+// SetPadGaps writes separators for the empty cells and rows between values or not. Default is true.
+// When enabled the values are kept in their columns and rows, otherwise the empty cells and skipped rows are collapsed.
+func (xp *XlsxParser) SetPadGaps(v bool) {
+	xp.padGaps = v
+}
+
+// SetMaxColumns sets the max number of columns of a row, the cells beyond it are ignored. Default is 0(no limit).
+func (xp *XlsxParser) SetMaxColumns(n int) {
+	xp.maxColumns = n
+}
+
+// SetMaxColumnWidth sets the max number of characters of the cell values written to the texts, the characters
+// beyond it are cut so that a long value does not dominate the row. The CSV, the cells and the records are not
+// affected. Default is 0(no limit).
+func (xp *XlsxParser) SetMaxColumnWidth(n int) {
+	xp.maxColumnWidth = n
+}
+
+// SetTrimEmpty trims the trailing empty cells of rows and the trailing empty rows of sheets or not. Default is true.
+func (xp *XlsxParser) SetTrimEmpty(v bool) {
+	xp.trimEmpty = v
+}
+
+// SetHeaderRow sets the row number(start 1) of the header row of records, see Records.
+// Default is 0, the first non-empty row is the header row.
+func (xp *XlsxParser) SetHeaderRow(row int) {
+	xp.headerRow = row
+}
+
+// SetCSVDelimiter sets the delimiter of the fields of CSV, like ',' or '\t' for TSV. Default is ','.
+func (xp *XlsxParser) SetCSVDelimiter(delimiter rune) {
+	xp.csvDelimiter = delimiter
+}
+
+// SetCSVQuoteMode sets the mode of quoting the fields of CSV. Default is types.QuoteMinimal.
+func (xp *XlsxParser) SetCSVQuoteMode(mode types.QuoteMode) {
+	xp.csvQuoteMode = mode
+}
+
+// SetCSVEncoding sets the character encoding of CSV. Default is types.EncodingUTF8.
+func (xp *XlsxParser) SetCSVEncoding(encoding types.Encoding) {
+	xp.csvEncoding = encoding
+}
+
+// SetCSVUseCRLF ends the records of CSV with "\r\n" as RFC 4180 or "\n". Default is true.
+func (xp *XlsxParser) SetCSVUseCRLF(v bool) {
+	xp.csvCRLF = v
+}
+
+// SetCSVRowRange sets the range of the row numbers(start 1, inclusive) of CSV, 0 means no limit.
+// Default is 0, 0(all the rows).
+func (xp *XlsxParser) SetCSVRowRange(first, last int) {
+	xp.csvFirstRow = first
+	xp.csvLastRow = last
+}
+
 // SetParseCharts parses charts or not. Default is false.
 func (xp *XlsxParser) SetParseCharts(v bool) {
 	xp.parseCharts = v
@@ -56,6 +156,34 @@ func (xp *XlsxParser) SetParseImages(v bool) {
 	}
 }
 
+// SetParseAltText writes the alt text(title and description) of images alongside them or not. Default is false.
+func (xp *XlsxParser) SetParseAltText(v bool) {
+	xp.parseAltText = v
+}
+
+// SetPreferAltText uses the alt text of images instead of ocr when present or not. Default is false.
+// It takes effect only when images are parsed, see SetParseImages.
+func (xp *XlsxParser) SetPreferAltText(v bool) {
+	xp.preferAltText = v
+}
+
+// SetParseEmbeddings parses embedded objects(OLE objects and packages) or not. Default is false.
+// The embed interface must be set by SetEmbedInterface, see package embedtotext.
+func (xp *XlsxParser) SetParseEmbeddings(v bool) {
+	xp.parseEmbeds = v
+}
+
+// SetEmbeddingsMaxDepth sets the max depth of recursively parsing embedded objects. Default is 1.
+// The depth of 0 or less does not parse the embedded objects.
+func (xp *XlsxParser) SetEmbeddingsMaxDepth(depth int) {
+	xp.embedsMaxDepth = depth
+}
+
+// SetEmbedInterface sets the embed interface used to extract text from embedded objects.
+func (xp *XlsxParser) SetEmbedInterface(embed types.Embed) {
+	xp.embed = embed
+}
+
 // SetDrawingsNoFmt sets drawings text no outline format.
 func (xp *XlsxParser) SetDrawingsNoFmt(v bool) {
 	xp.drawingsNoFmt = v
@@ -76,6 +204,27 @@ func (xp *XlsxParser) NumSheets() int {
 	return len(xp.sheetFiles)
 }
 
+// SheetNames returns the names of sheets in the tab order, the i-th name is of the sheet i+1.
+func (xp *XlsxParser) SheetNames() []string {
+	names := make([]string, 0, len(xp.workbook.sheets))
+	for _, sheet := range xp.workbook.sheets {
+		names = append(names, sheet.name)
+	}
+
+	return names
+}
+
+// sheetIndex returns the number(start 1) of the sheet with the name, the names are case-insensitive like Excel.
+func (xp *XlsxParser) sheetIndex(name string) (int, bool) {
+	for i, sheet := range xp.workbook.sheets {
+		if strings.EqualFold(sheet.name, name) {
+			return i + 1, true
+		}
+	}
+
+	return 0, false
+}
+
 // Close closes the zipReader and OCR client.
 // After extracting the text, please remember to call this method.
 func (xp *XlsxParser) Close() (err error) {
@@ -95,7 +244,7 @@ func (xp *XlsxParser) Close() (err error) {
 	return nil
 }
 
-// ExtractImages extracts images from the xlsx file.
+// ExtractImages extracts images from the xlsx file, along with the alt text of the drawings referencing them.
 //
 // Parameters:
 //   - None
@@ -104,6 +253,9 @@ func (xp *XlsxParser) Close() (err error) {
 //   - []types.Image: a slice of images extracted from the xlsx file.
 //   - error: an error if any occurred during the extraction process.
 func (xp *XlsxParser) ExtractImages() ([]types.Image, error) {
+	infos, err := xp.parseImageInfos()
+	xp.logWarn(err)
+
 	images := make([]types.Image, 0, len(xp.imagesFiles))
 	for name, f := range xp.imagesFiles {
 		r, err := f.Open()
@@ -117,11 +269,15 @@ func (xp *XlsxParser) ExtractImages() ([]types.Image, error) {
 		}
 		r.Close()
 
-		images = append(images, types.Image{
+		info := types.Image{
 			Raw:    img,
 			Name:   name,
 			Format: format,
-		})
+		}
+		if alt, ok := infos[name]; ok {
+			info.AltText, info.Title, info.Caption = alt.AltText, alt.Title, alt.Caption
+		}
+		images = append(images, info)
 	}
 
 	return images, nil
@@ -162,6 +318,84 @@ func (xp *XlsxParser) ExtractSheetTexts(sheets ...int) (string, error) {
 	return texts.String(), nil
 }
 
+// ExtractSheetTextsByName extracts the texts from the specified xlsx sheets by their names.
+//
+// Parameters:
+//   - names: the names of the sheets to extract texts from, see SheetNames.
+//
+// Returns:
+//   - string: A string containing the extracted texts.
+//   - error: types.ErrNoSheet if a sheet is not found, or an error if there is any issue with parsing the sheets.
+func (xp *XlsxParser) ExtractSheetTextsByName(names ...string) (string, error) {
+	sheets := make([]int, 0, len(names))
+	for _, name := range names {
+		i, ok := xp.sheetIndex(name)
+		if !ok {
+			return "", types.ErrNoSheet
+		}
+		sheets = append(sheets, i)
+	}
+
+	return xp.ExtractSheetTexts(sheets...)
+}
+
+// ExtractSheetCells extracts the non-empty cells of the specified xlsx sheet with their positions and
+// typed values, the rows without non-empty cells are skipped. The merged cells are handled by the merge mode,
+// in types.MergeSpan mode the top left cells are spanned over the merged cells by ColSpan and RowSpan
+// and the covered cells are omitted, see SetMergeMode.
+//
+// Parameters:
+//   - sheet: the sheet number(start 1).
+//
+// Returns:
+//   - [][]types.Cell: the cells of the rows in the row order.
+//   - error: types.ErrNoSheet if the sheet is not found, or an error if there is any issue with parsing the sheet.
+func (xp *XlsxParser) ExtractSheetCells(sheet int) ([][]types.Cell, error) {
+	if !xp.shareParsed {
+		err := xp.parseSharedStrings()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := xp.sheetFiles[sheet]; !ok {
+		return nil, types.ErrNoSheet
+	}
+	if xp.workbook.sheets[sheet-1].hidden() && xp.hiddenMode == types.HiddenExclude {
+		return nil, nil
+	}
+
+	sr, err := xp.newSheetReader(sheet, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer sr.close()
+	sr.typed = true
+
+	rows := make([][]types.Cell, 0, 16)
+	for row := sr.next(); row != nil; row = sr.next() {
+		var cells []types.Cell
+		for i, cell := range row.typed {
+			col := i + 1
+			if row.cells[i] == "" || xp.mergeMode == types.MergeSpan && sr.merges.covered(col, row.num) {
+				continue
+			}
+			if mr := sr.merges.span(col, row.num); mr != nil && xp.mergeMode == types.MergeSpan {
+				cell.ColSpan = mr.col2 - mr.col1 + 1
+				cell.RowSpan = mr.row2 - mr.row1 + 1
+			}
+			cells = append(cells, cell)
+		}
+		if len(cells) > 0 {
+			rows = append(rows, cells)
+		}
+	}
+	if err = sr.err(); err != nil {
+		return rows, err
+	}
+
+	return rows, nil
+}
+
 // ExtractTexts extracts the texts from the xlsx file.
 //
 // It iterates through each sheet of the xlsx file and appends the text content
@@ -185,8 +419,8 @@ func (xp *XlsxParser) ExtractTexts() (string, error) {
 
 	texts := new(strings.Builder)
 	if xp.onlySharedStrings {
-		for _, v := range xp.sharedStringsMap {
-			texts.WriteString(*v)
+		for _, v := range xp.sharedStrings {
+			texts.WriteString(v)
 			texts.WriteString(xp.rowSep)
 		}
 		return texts.String(), nil
@@ -208,10 +442,9 @@ func (xp *XlsxParser) ExtractTexts() (string, error) {
 
 // parseSharedStrings parses the shared strings in the xlsx file.
 //
-// It opens the shared strings file and reads the XML elements to populate the
-// sharedStringsMap with the values. It assigns the values to the corresponding
-// indices in the sharedStringsMap. It returns an error if there is any issue
-// with opening the file or parsing the XML elements.
+// Each shared string item(si) is a plain text(t) or a rich text of runs(r/t), the texts of runs are
+// concatenated, and the phonetic guides(rPh) are dropped or written in parentheses after the text,
+// see SetParsePhonetic. The shared strings are indexed by the order of the items.
 //
 // Returns:
 //   - error: An error if there is any issue with opening the file or parsing
@@ -219,16 +452,22 @@ func (xp *XlsxParser) ExtractTexts() (string, error) {
 func (xp *XlsxParser) parseSharedStrings() error {
 	if xp.sharedStringsFile == nil {
 		xp.logWarn(types.ErrNoSharedStrings)
+		xp.shareParsed = true
 		return nil
 	}
 
-	rc, err := xp.sharedStringsFile.Open()
+	rc, err := opc.OpenPart(xp.sharedStringsFile)
 	if err != nil {
 		return err
 	}
 	defer rc.Close()
 
-	var c = 0
+	var (
+		text       = new(strings.Builder)
+		phonetic   = new(strings.Builder)
+		t          = ""
+		inPhonetic = false
+	)
 	r := qxml.NewReader(rc)
 NEXT:
 	for r.Next() {
@@ -241,19 +480,43 @@ NEXT:
 				if uniqueCount != nil {
 					cap, _ = strconv.Atoi(uniqueCount.Value())
 				}
-				xp.sharedStringsMap = make(map[string]*string, cap)
-				for i := 0; i < cap; i++ {
-					xp.sharedStringsMap[strconv.Itoa(i)] = new(string)
+				if cap < 0 || cap > 1<<20 {
+					cap = 1 << 20
+				}
+				xp.sharedStrings = make([]string, 0, cap)
+
+			case "si":
+				text.Reset()
+				phonetic.Reset()
+				if e.HasEnd() {
+					xp.sharedStrings = append(xp.sharedStrings, "")
 				}
 
+			case "rPh":
+				inPhonetic = !e.HasEnd()
+
 			case "t":
-				if v, ok := xp.sharedStringsMap[strconv.Itoa(c)]; ok {
-					r.AssignNext(v)
-					if !r.Next() {
-						break NEXT
-					}
+				if e.HasEnd() {
+					continue
+				}
+				r.AssignNext(&t)
+				if !r.Next() {
+					break NEXT
 				}
-				c++
+				if inPhonetic {
+					phonetic.WriteString(t)
+				} else {
+					text.WriteString(t)
+				}
+				t = ""
+			}
+
+		case *qxml.EndElement:
+			switch e.Name() {
+			case "si":
+				xp.sharedStrings = append(xp.sharedStrings, xp.phoneticText(text.String(), phonetic.String()))
+			case "rPh":
+				inPhonetic = false
 			}
 		}
 	}
@@ -265,6 +528,12 @@ NEXT:
 
 // parseSheet parses a sheet at the given index and returns the extracted texts, tables, charts, diagrams, and images.
 //
+// The cell values are placed in their columns by the cell references(c@r) and the skipped rows are
+// represented by the row numbers(row@r), see SetPadGaps, SetMaxColumns, SetMaxColumnWidth and SetTrimEmpty. The merged cells
+// are handled by the merge mode, see SetMergeMode.
+// The cell values are rendered by the cell types(c@t) and the number formats of the cell styles(c@s), see cellText. The sheet name is written as the heading,
+// see SetSheetHeading, and the hidden sheets are handled by the hidden mode, see SetHiddenMode.
+//
 // Parameters:
 //   - i: the index of the sheet to parse.
 //
@@ -272,61 +541,48 @@ NEXT:
 //   - texts: a strings.Builder containing the extracted texts.
 //   - error: an error if the slide does not exist or if there was an error opening the sheet file.
 func (xp *XlsxParser) parseSheet(i int) (*strings.Builder, error) {
-	sheetFile, ok := xp.sheetFiles[i]
-	if !ok {
+	if _, ok := xp.sheetFiles[i]; !ok {
 		return nil, types.ErrNoSheet
 	}
+	sheet := xp.workbook.sheets[i-1]
+	if sheet.hidden() && xp.hiddenMode == types.HiddenExclude {
+		return new(strings.Builder), nil
+	}
 
-	rc, err := sheetFile.Open()
+	extras := new(strings.Builder)
+	sr, err := xp.newSheetReader(i, extras)
 	if err != nil {
 		return nil, err
 	}
-	defer rc.Close()
+	defer sr.close()
 
-	var (
-		texts            = new(strings.Builder)
-		cellValueOrIndex = ""
-	)
-	r := qxml.NewReader(rc)
+	texts := new(strings.Builder)
+	rw := &rowWriter{xp: xp, texts: texts}
+	for row := sr.next(); row != nil; row = sr.next() {
+		rw.write(row)
+	}
+	texts.WriteString(extras.String())
 
-NEXT:
-	for r.Next() {
-		switch e := r.Element().(type) {
-		case *qxml.EndElement:
-			if e.Name() == "row" {
-				texts.WriteString(xp.rowSep)
-			}
-		case *qxml.StartElement:
-			switch e.Name() {
-			case "v":
-				r.AssignNext(&cellValueOrIndex)
-				if !r.Next() {
-					break NEXT
-				}
+	marked := sheet.hidden() && xp.hiddenMode == types.HiddenMark
+	if texts.Len() == 0 || (xp.sheetHeading == "" && !marked) {
+		return texts, nil
+	}
 
-				s, ok := xp.sharedStringsMap[cellValueOrIndex]
-				if ok {
-					texts.WriteString(*s)
-				} else {
-					texts.WriteString(cellValueOrIndex)
-				}
-				texts.WriteString(xp.colSep)
-				cellValueOrIndex = ""
-
-			case "drawing":
-				attrs := e.Attrs()
-				if attrs.Len() > 0 {
-					rIdKV := attrs.Get("r:id")
-					drawings := xp.extractDrawings(i, rIdKV.Value())
-					if drawings != nil {
-						texts.WriteString(drawings.String())
-					}
-				}
-			}
-		}
+	sheetTexts := new(strings.Builder)
+	if xp.sheetHeading != "" {
+		sheetTexts.WriteString(strings.ReplaceAll(xp.sheetHeading, "%s", sheet.name))
+	}
+	if marked {
+		sheetTexts.WriteString(types.HiddenMarkStart)
+		sheetTexts.WriteString(xp.rowSep)
+	}
+	sheetTexts.WriteString(texts.String())
+	if marked {
+		sheetTexts.WriteString(types.HiddenMarkEnd)
+		sheetTexts.WriteString(xp.rowSep)
 	}
 
-	return texts, nil
+	return sheetTexts, nil
 }
 
 func (xp *XlsxParser) logWarn(err error) {
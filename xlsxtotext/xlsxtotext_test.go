@@ -6,15 +6,41 @@
 package xlsxtotext
 
 import (
+	"archive/zip"
 	"bytes"
+	"errors"
+	"hash/crc32"
 	"image/jpeg"
+	"io"
+	"math"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/young2j/oxmltotext/types"
 )
 
 var (
-	xlsxPath = "../filesamples/file-sample_100kb.xlsx"
-	xlsxURL  = "https://zzzx.snnu.edu.cn/__local/F/62/4E/896DC0778F426C757828CED677C_97EE9695_75E1.xlsx?e=.xlsx"
+	xlsxPath        = "../filesamples/file-sample_100kb.xlsx"
+	xlsxAltTextPath = "../filesamples/file-sample_alttext.xlsx"
+	xlsxRenamedPath = "../filesamples/file-sample_renamed.xlsm"
+	xlsxStrictPath  = "../filesamples/file-sample_strict.xlsx"
+	xlsxCellsPath   = "../filesamples/file-sample_cells.xlsx"
+	xlsxTypesPath   = "../filesamples/file-sample_types.xlsx"
+	xlsxSharedPath  = "../filesamples/file-sample_sharedstrings.xlsx"
+	xlsxSheetsPath  = "../filesamples/file-sample_sheets.xlsx"
+	xlsxNumFmtPath  = "../filesamples/file-sample_numfmt.xlsx"
+	xlsx1904Path    = "../filesamples/file-sample_date1904.xlsx"
+	xlsxFormulaPath = "../filesamples/file-sample_formulas.xlsx"
+	xlsxMergesPath  = "../filesamples/file-sample_merges.xlsx"
+	xlsxCSVPath     = "../filesamples/file-sample_csv.xlsx"
+	xlsxNamesPath   = "../filesamples/file-sample_names.xlsx"
+	xlsxRecordsPath = "../filesamples/file-sample_records.xlsx"
+	xlsxCommentPath = "../filesamples/file-sample_comments.xlsx"
+	xlsxTablesPath  = "../filesamples/file-sample_tables.xlsx"
+	xlsxURL         = "https://zzzx.snnu.edu.cn/__local/F/62/4E/896DC0778F426C757828CED677C_97EE9695_75E1.xlsx?e=.xlsx"
 )
 
 func TestOpen(t *testing.T) {
@@ -73,6 +99,59 @@ func TestOpenReader(t *testing.T) {
 	t.Log(texts)
 }
 
+func TestOpenWithoutPackageRels(t *testing.T) {
+	xp, err := Open(xlsxPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer xp.Close()
+	want, err := xp.ExtractTexts()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the workbook is found without the package relationships
+	zr, err := zip.OpenReader(xlsxPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	b := new(bytes.Buffer)
+	zw := zip.NewWriter(b)
+	for _, f := range zr.File {
+		if f.Name == "_rels/.rels" {
+			continue
+		}
+		if err = zw.Copy(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	xp, err = OpenReader(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer xp.Close()
+	texts, err := xp.ExtractTexts()
+	if err != nil {
+		t.Error(err)
+	}
+	if texts == "" || texts != want {
+		t.Error("unexpected texts without package relationships")
+	}
+
+	// the package without the workbook
+	b.Reset()
+	zw = zip.NewWriter(b)
+	zw.Create("xl/other.xml")
+	zw.Close()
+	if _, err = OpenReader(bytes.NewReader(b.Bytes()), int64(b.Len())); err != types.ErrNonePart {
+		t.Errorf("want types.ErrNonePart: %v", err)
+	}
+}
+
 func TestOpenURL(t *testing.T) {
 	xp, _, err := OpenURL(xlsxURL)
 	if err != nil {
@@ -190,3 +269,1013 @@ func TestExtractImages(t *testing.T) {
 	}
 
 }
+
+func TestAltText(t *testing.T) {
+	xp, err := Open(xlsxAltTextPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer xp.Close()
+
+	imgs, err := xp.ExtractImages()
+	if err != nil {
+		t.Error(err)
+	}
+	found := false
+	for _, img := range imgs {
+		t.Logf("img: %v alt text: %q", img.Name, img.AltText)
+		if img.AltText == "Bar chart of quarterly sales" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("missing image alt text")
+	}
+
+	xp.SetParseAltText(true)
+	texts, err := xp.ExtractTexts()
+	if err != nil {
+		t.Error(err)
+	}
+	if !strings.Contains(texts, "alt text") || !strings.Contains(texts, " Bar chart of quarterly sales\n") {
+		t.Error("missing alt text")
+	}
+
+	t.Log(texts)
+}
+
+func TestRenamedParts(t *testing.T) {
+	want, err := Open(xlsxPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer want.Close()
+	xp, err := Open(xlsxRenamedPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer xp.Close()
+
+	// the parts are discovered by relationships instead of their conventional names
+	want.SetParseCharts(true)
+	xp.SetParseCharts(true)
+	wantTexts, _ := want.ExtractTexts()
+	texts, err := xp.ExtractTexts()
+	if err != nil {
+		t.Error(err)
+	}
+	if texts == "" || texts != wantTexts {
+		t.Error("unexpected texts of renamed parts")
+	}
+	imgs, err := xp.ExtractImages()
+	if err != nil {
+		t.Error(err)
+	}
+	t.Logf("images: %d", len(imgs))
+	if len(imgs) == 0 {
+		t.Error("missing images")
+	}
+
+	t.Log(texts)
+}
+
+func TestStrict(t *testing.T) {
+	want, err := Open(xlsxPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer want.Close()
+	xp, err := Open(xlsxStrictPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer xp.Close()
+
+	// the parts use the Strict namespaces and non-default prefixes
+	want.SetParseCharts(true)
+	xp.SetParseCharts(true)
+	wantTexts, _ := want.ExtractTexts()
+	texts, err := xp.ExtractTexts()
+	if err != nil {
+		t.Error(err)
+	}
+	if texts == "" || texts != wantTexts {
+		t.Error("unexpected texts of strict parts")
+	}
+
+	t.Log(texts)
+}
+
+func TestCellPositions(t *testing.T) {
+	xp, err := Open(xlsxCellsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer xp.Close()
+
+	xp.SetSheetSep("")
+	texts, err := xp.ExtractTexts()
+	if err != nil {
+		t.Error(err)
+	}
+	t.Logf("%q", texts)
+	rows := strings.Split(texts, "\n")
+	if len(rows) != 7 || rows[0] != "Name\t\tScore" || rows[1] != "" || rows[3] != "Bob\t4.5\t5\t\t\t6" || rows[5] != "Next" {
+		t.Error("unexpected cell positions")
+	}
+	if !strings.HasSuffix(rows[4], strings.Repeat("\t", 29)+"Far") {
+		t.Error("unexpected far column")
+	}
+
+	// the cells beyond max columns are ignored, and the trailing empty cells and rows are kept
+	xp.SetMaxColumns(10)
+	xp.SetTrimEmpty(false)
+	texts, _ = xp.ExtractTexts()
+	t.Logf("%q", texts)
+	rows = strings.Split(texts, "\n")
+	if len(rows) != 9 || !strings.HasSuffix(rows[2], "\t\t") || rows[4] != "" || rows[7] != "" {
+		t.Error("unexpected untrimmed rows")
+	}
+
+	// the gaps are collapsed
+	xp.SetPadGaps(false)
+	xp.SetTrimEmpty(true)
+	texts, _ = xp.ExtractTexts()
+	t.Logf("%q", texts)
+	if !strings.HasPrefix(texts, "Name\tScore\n") || !strings.Contains(texts, "\nBob\t4.5\t5\t6\n") {
+		t.Error("unexpected collapsed rows")
+	}
+
+	// the cell values are cut to the max column width
+	xp.SetMaxColumnWidth(2)
+	texts, _ = xp.ExtractTexts()
+	t.Logf("%q", texts)
+	if !strings.HasPrefix(texts, "Na\tSc\n") || !strings.Contains(texts, "\nBo\t4.\t5\t6\n") {
+		t.Error("unexpected cut values")
+	}
+	if truncateWidth("日本語", 2) != "日本" || truncateWidth("ab", 2) != "ab" {
+		t.Error("unexpected truncated width")
+	}
+}
+
+func TestCellTypes(t *testing.T) {
+	xp, err := Open(xlsxTypesPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer xp.Close()
+
+	xp.SetSheetSep("")
+	texts, err := xp.ExtractTexts()
+	if err != nil {
+		t.Error(err)
+	}
+	t.Logf("%q", texts)
+	rows := strings.Split(texts, "\n")
+	if len(rows) != 5 || rows[0] != "Zero\tOne\tTwo\tThree" {
+		t.Fatal("unexpected shared strings")
+	}
+	// the numbers are never resolved as shared strings
+	if rows[1] != "3\t1\tTRUE\tFALSE" {
+		t.Error("unexpected numbers and booleans")
+	}
+	// the inline strings are extracted without phonetic runs
+	if rows[2] != "#N/A\tZero&<\tInline\tRich Text" {
+		t.Error("unexpected errors and strings")
+	}
+	if rows[3] != "2023-10-05T00:00:00\t#DIV/0!" {
+		t.Error("unexpected dates")
+	}
+}
+
+func TestSharedStrings(t *testing.T) {
+	xp, err := Open(xlsxSharedPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer xp.Close()
+
+	xp.SetSheetSep("")
+	texts, err := xp.ExtractTexts()
+	if err != nil {
+		t.Error(err)
+	}
+	t.Logf("%q", texts)
+	// the runs of rich text are concatenated and the phonetic guides are dropped
+	if texts != "Plain\nBold and italic\n漢字\n\nTom & Jerry\nLast\n" {
+		t.Error("unexpected shared strings")
+	}
+
+	xp.SetParsePhonetic(true)
+	xp.shareParsed = false
+	texts, _ = xp.ExtractTexts()
+	t.Logf("%q", texts)
+	if !strings.Contains(texts, "\n漢字(かんじ)\n") {
+		t.Error("missing phonetic guides")
+	}
+
+	// the shared strings are written in order
+	xp.SetOnlySharedStrings(true)
+	texts, _ = xp.ExtractTexts()
+	if !strings.HasPrefix(texts, "Plain\nBold and italic\n") || !strings.HasSuffix(texts, "Last\n") {
+		t.Error("unexpected order of shared strings")
+	}
+}
+
+func TestSheetNames(t *testing.T) {
+	xp, err := Open(xlsxSheetsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer xp.Close()
+
+	// the sheets are in the tab order of the workbook
+	names := xp.SheetNames()
+	t.Log(names)
+	if len(names) != 4 || names[0] != "Data & Notes" || names[1] != "Summary" {
+		t.Fatal("unexpected sheet names")
+	}
+
+	xp.SetSheetSep("")
+	xp.SetSheetHeading("# %s\n")
+	texts, err := xp.ExtractSheetTexts(1)
+	if err != nil {
+		t.Error(err)
+	}
+	t.Logf("%q", texts)
+	if texts != "# Data & Notes\ndata text\n" {
+		t.Error("unexpected sheet heading")
+	}
+	texts, err = xp.ExtractSheetTextsByName("summary", "Data & Notes")
+	if err != nil {
+		t.Error(err)
+	}
+	if texts != "# Summary\nsummary text\n# Data & Notes\ndata text\n" {
+		t.Error("unexpected texts by names")
+	}
+	if _, err = xp.ExtractSheetTextsByName("Missing"); err != types.ErrNoSheet {
+		t.Error("unexpected missing sheet error")
+	}
+
+	// a literal "%" of the heading is written as is
+	xp.SetSheetHeading("# %s 100%\n")
+	texts, _ = xp.ExtractSheetTexts(1)
+	if texts != "# Data & Notes 100%\ndata text\n" {
+		t.Errorf("unexpected sheet heading: %q", texts)
+	}
+
+	xp.SetSheetHeading("")
+	xp.SetHiddenMode(types.HiddenExclude)
+	texts, _ = xp.ExtractTexts()
+	t.Logf("%q", texts)
+	if texts != "data text\nsummary text\n" {
+		t.Error("unexpected texts without hidden sheets")
+	}
+	xp.SetHiddenMode(types.HiddenMark)
+	texts, _ = xp.ExtractTexts()
+	t.Logf("%q", texts)
+	if !strings.HasSuffix(texts, "[hidden]\nhidden text\n[/hidden]\n[hidden]\nvery hidden text\n[/hidden]\n") {
+		t.Error("unexpected marked hidden sheets")
+	}
+}
+
+func TestNumFmt(t *testing.T) {
+	for _, c := range []struct {
+		code string
+		v    float64
+		want string
+	}{
+		{"General", 0.1 + 0.2, "0.3"},
+		{"General", 123456789012, "123456789012"},
+		{"General", 4006381333931, "4006381333931"},
+		{"General", 40063813339312, "40063813339312"},
+		{"General", 400638133393123, "400638133393123"},
+		{"General", 3.14159265358979, "3.14159265358979"},
+		{"General", 0.123456789012345678, "0.123456789012346"},
+		{"General", 1234567890123456789, "1234567890123460000"},
+		{"General", 1.5e300, "1.5E+300"},
+		{"General", 1.5e-10, "1.5E-10"},
+		{"General", -4.5, "-4.5"},
+		{"0", 2.5, "3"},
+		{"0.00", 3.14159, "3.14"},
+		{"0.00", 1.005, "1.01"},
+		{"0", -2.5, "-3"},
+		{"#,##0", 1234567.8, "1,234,568"},
+		{"#,##0.00", -1234.5, "-1,234.50"},
+		{"0%", 0.256, "26%"},
+		{"0.00%", 0.256, "25.60%"},
+		{"0.00E+00", 12345, "1.23E+04"},
+		{"0.00E+00", 0.00012, "1.20E-04"},
+		{"##0.0E+0", 12345, "12.3E+3"},
+		{"# ?/?", 1.5, "1 1/2"},
+		{"# ?/?", 0.25, "1/4"},
+		{"# ??/??", 3.14159, "3 14/99"},
+		{"?/8", 0.375, "3/8"},
+		{"# ?/?", 2, "2"},
+		{"mm-dd-yy", 45123, "07-16-23"},
+		{"yyyy-mm-dd", 45123, "2023-07-16"},
+		{"d-mmm-yy", 45123, "16-Jul-23"},
+		{"mmmm d, yyyy", 45123, "July 16, 2023"},
+		{"dddd", 45123, "Sunday"},
+		{"h:mm AM/PM", 0.75, "6:00 PM"},
+		{"h:mm:ss", 0.5000001, "12:00:00"},
+		{"[h]:mm:ss", 1.5, "36:00:00"},
+		{"mm:ss.0", 0.00123, "01:46.3"},
+		{"m/d/yy h:mm", 45123.25, "7/16/23 6:00"},
+		{"yyyy-mm-dd hh:mm:ss", 1.0, "1900-01-01 00:00:00"},
+		{"yyyy-mm-dd", 61, "1900-03-01"},
+		{"yyyy-mm-dd", 60, "1900-02-29"},
+		{"yyyy-mm-dd", 59, "1900-02-28"},
+		{"mm-dd-yy", 0, "01-00-00"},
+		{`#,##0 ;(#,##0)`, -1234, "(1,234)"},
+		{`#,##0.00;[Red](#,##0.00)`, -5, "(5.00)"},
+		{`[$€-407]#,##0.00`, 1234.5, "€1,234.50"},
+		{`"Qty: "0`, 5, "Qty: 5"},
+		{`#,##0.00 "USD"`, 10, "10.00 USD"},
+		{`0.0,,"M"`, 12345678, "12.3M"},
+		{`[>=1000]#,##0,"K";0`, 12345, "12K"},
+		{`[>=1000]#,##0,"K";0`, 999, "999"},
+		{`0.00;-0.00;"zero"`, 0, "zero"},
+		{`#.00`, 0.5, ".50"},
+		{`0.0#`, 1.5, "1.5"},
+		{`0.0?`, 1.5, "1.5 "},
+		{`$#,##0_);($#,##0)`, 1000, "$1,000 "},
+		{`$#,##0_);($#,##0)`, -1000, "($1,000)"},
+		{`00000`, 123, "00123"},
+		{`@`, 12, "12"},
+		{"0.00", math.Inf(1), "+Inf"},
+		{"0.00", math.NaN(), "NaN"},
+		{"0%", 1e307, "+Inf"},
+		{"#,##0", 1234567890123456789, "1,234,567,890,123,460,000"},
+		{"0.0", 1e20, "100000000000000000000.0"},
+	} {
+		got := parseNumFmt(c.code).format(c.v, false)
+		t.Logf("%s %v => %q", c.code, c.v, got)
+		if got != c.want {
+			t.Errorf("want: %q", c.want)
+		}
+	}
+	if got := parseNumFmt("yyyy-mm-dd").format(0, true); got != "1904-01-01" {
+		t.Errorf("unexpected 1904 date: %q", got)
+	}
+}
+
+func TestNumberFormats(t *testing.T) {
+	xp, err := Open(xlsxNumFmtPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer xp.Close()
+
+	xp.SetSheetSep("")
+	texts, err := xp.ExtractTexts()
+	if err != nil {
+		t.Error(err)
+	}
+	t.Logf("%q", texts)
+	rows := strings.Split(texts, "\n")
+	if len(rows) != 5 {
+		t.Fatal("unexpected rows")
+	}
+	for i, want := range []string{
+		"07-16-23\t25.60%\t-1,234.50\t2023-07-16",
+		"€1,234.50\t-€5.00\t06:00:00 PM\tQty: 5",
+		"12.3M\t36:00\t1.23E+04\t1 1/2",
+		"0.3\t2023-07-16\t12\t01-00-00",
+	} {
+		if rows[i] != want {
+			t.Errorf("want: %q", want)
+		}
+	}
+
+	// the raw values are written as they are
+	xp.SetRawValues(true)
+	texts, _ = xp.ExtractTexts()
+	t.Logf("%q", texts)
+	if !strings.HasPrefix(texts, "45123\t0.256\t-1234.5\t45123.75\n") {
+		t.Error("unexpected raw values")
+	}
+
+	xp1904, err := Open(xlsx1904Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer xp1904.Close()
+	texts, _ = xp1904.ExtractTexts()
+	t.Logf("%q", texts)
+	if !strings.HasPrefix(texts, "01-01-04\t2023-07-16\n") {
+		t.Error("unexpected dates of the 1904 date system")
+	}
+}
+
+func TestShiftFormula(t *testing.T) {
+	for _, c := range []struct {
+		formula    string
+		dRow, dCol int
+		want       string
+	}{
+		{"A1+B1", 1, 0, "A2+B2"},
+		{"$A$1+A$1+$A1", 2, 2, "$A$1+C$1+$A3"},
+		{"SUM(A1:A3)*LOG10(B1)", 1, 1, "SUM(B2:B4)*LOG10(C2)"},
+		{`"A1"&'Sheet 1'!A1&Sheet2!B2`, 1, 0, `"A1"&'Sheet 1'!A2&Sheet2!B3`},
+		{"SUM(A:B)+SUM(1:2)", 1, 1, "SUM(B:C)+SUM(2:3)"},
+		{"Table1[[#This Row],[A1]]*C1", 1, 0, "Table1[[#This Row],[A1]]*C2"},
+		{"A1-1.5E+10", -1, 0, "#REF!-1.5E+10"},
+		{"XFD1+TRUE", 0, 1, "#REF!+TRUE"},
+	} {
+		got := shiftFormula(c.formula, c.dRow, c.dCol)
+		t.Logf("%s (%d, %d) => %s", c.formula, c.dRow, c.dCol, got)
+		if got != c.want {
+			t.Errorf("want: %s", c.want)
+		}
+	}
+}
+
+func TestFormulas(t *testing.T) {
+	xp, err := Open(xlsxFormulaPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer xp.Close()
+
+	xp.SetSheetSep("")
+	texts, err := xp.ExtractTexts()
+	if err != nil {
+		t.Error(err)
+	}
+	t.Logf("%q", texts)
+	// the formula without cached value is empty
+	if texts != "1\t2\t3\n3\t4\t13\n5\t6\t31\n7\t8\n\t\t\t12\nTotal: 16\t\t\t30\n" {
+		t.Error("unexpected cached values")
+	}
+
+	// the shared formulas are expanded and the array formulas cover their ranges
+	xp.SetFormulaMode(types.FormulaText)
+	texts, _ = xp.ExtractTexts()
+	t.Logf("%q", texts)
+	rows := strings.Split(texts, "\n")
+	if len(rows) != 7 {
+		t.Fatal("unexpected rows")
+	}
+	for i, want := range []string{
+		"1\t2\t=A1+B1",
+		"3\t4\t=A2*B2+$A$1",
+		"5\t6\t=A3*B3+$A$1",
+		"7\t8\t=A4*B4+$A$1",
+		"\t\t\t{=A2:A3*B2:B3}",
+		`="Total: "&SUM(A1:A4)` + "\t\t\t{=A2:A3*B2:B3}",
+	} {
+		if rows[i] != want {
+			t.Errorf("want: %q", want)
+		}
+	}
+
+	xp.SetFormulaMode(types.FormulaBoth)
+	texts, _ = xp.ExtractTexts()
+	t.Logf("%q", texts)
+	if !strings.Contains(texts, "=A2*B2+$A$1"+types.FormulaValueSep+"13\n") ||
+		!strings.Contains(texts, "=A4*B4+$A$1"+types.FormulaValueSep+types.FormulaNoValue+"\n") {
+		t.Error("unexpected formulas and values")
+	}
+}
+
+func TestMergeCells(t *testing.T) {
+	xp, err := Open(xlsxMergesPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer xp.Close()
+
+	xp.SetSheetSep("")
+	texts, err := xp.ExtractTexts()
+	if err != nil {
+		t.Error(err)
+	}
+	t.Logf("%q", texts)
+	if texts != "Quarter\t\t\tTotal\nQ1\tQ2\tQ3\n1\t2\t3\t6\nNorth\t10\n\t20\n" {
+		t.Error("unexpected blank covered cells")
+	}
+
+	// the covered cells are filled, including the rows not written in the sheet
+	xp.SetMergeMode(types.MergeRepeat)
+	texts, _ = xp.ExtractTexts()
+	t.Logf("%q", texts)
+	if texts != "Quarter\tQuarter\tQuarter\tTotal\nQ1\tQ2\tQ3\tTotal\n1\t2\t3\t6\nNorth\t10\nNorth\t20\nNorth\n" {
+		t.Error("unexpected repeated covered cells")
+	}
+
+	xp.SetMergeMode(types.MergeSpan)
+	rows, err := xp.ExtractSheetCells(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("%+v", rows)
+	if len(rows) != 5 || len(rows[0]) != 2 || len(rows[3]) != 2 {
+		t.Fatal("unexpected cells")
+	}
+	if c := rows[0][0]; c.Ref != "A1" || c.Value != "Quarter" || c.ColSpan != 3 || c.RowSpan != 1 {
+		t.Errorf("unexpected cell: %+v", c)
+	}
+	if c := rows[0][1]; c.Ref != "D1" || c.ColSpan != 1 || c.RowSpan != 2 {
+		t.Errorf("unexpected cell: %+v", c)
+	}
+	if c := rows[3][0]; c.Ref != "A4" || c.Value != "North" || c.RowSpan != 3 {
+		t.Errorf("unexpected cell: %+v", c)
+	}
+
+	if _, err = xp.ExtractSheetCells(2); err != types.ErrNoSheet {
+		t.Error("want types.ErrNoSheet")
+	}
+}
+
+func TestWriteSheetCSV(t *testing.T) {
+	xp, err := Open(xlsxCSVPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer xp.Close()
+
+	b := new(bytes.Buffer)
+	if err = xp.WriteSheetCSV(b, 1); err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("%q", b.String())
+	if b.String() != "Name,Note,Amount,\r\n张三,\"He said \"\"hi\"\", then left\",1234.5,\r\n,,,\r\n\"Line1\nLine2\",tab\there,,€\r\n" {
+		t.Error("unexpected CSV")
+	}
+
+	// TSV without quoting
+	xp.SetCSVDelimiter('\t')
+	xp.SetCSVQuoteMode(types.QuoteNone)
+	xp.SetCSVUseCRLF(false)
+	xp.SetCSVRowRange(2, 4)
+	b.Reset()
+	xp.WriteSheetCSV(b, 1)
+	t.Logf("%q", b.String())
+	if b.String() != "张三\tHe said \"hi\", then left\t1234.5\t\n\t\t\t\nLine1 Line2\ttab here\t\t€\n" {
+		t.Error("unexpected TSV")
+	}
+
+	xp.SetCSVDelimiter(',')
+	xp.SetCSVQuoteMode(types.QuoteAll)
+	xp.SetCSVRowRange(0, 1)
+	xp.SetCSVEncoding(types.EncodingUTF8BOM)
+	b.Reset()
+	xp.WriteSheetCSV(b, 1)
+	t.Logf("%q", b.String())
+	if b.String() != "\uFEFF\"Name\",\"Note\",\"Amount\",\"\"\n" {
+		t.Error("unexpected CSV with BOM")
+	}
+
+	xp.SetCSVQuoteMode(types.QuoteMinimal)
+	xp.SetCSVRowRange(2, 2)
+	xp.SetCSVEncoding(types.EncodingGBK)
+	b.Reset()
+	xp.WriteSheetCSV(b, 1)
+	t.Logf("%q", b.String())
+	if !bytes.HasPrefix(b.Bytes(), []byte{0xd5, 0xc5, 0xc8, 0xfd, ','}) {
+		t.Error("unexpected GBK encoding")
+	}
+
+	xp.SetCSVDelimiter('"')
+	if err = xp.WriteSheetCSV(b, 1); err != types.ErrBadDelimiter {
+		t.Error("want types.ErrBadDelimiter")
+	}
+
+	// the errors of reading the sheet are returned
+	xp = openCorruptSheet(t, xlsxCSVPath)
+	defer xp.Close()
+	err = xp.WriteSheetCSV(io.Discard, 1)
+	t.Log(err)
+	if err != zip.ErrChecksum {
+		t.Error("want zip.ErrChecksum")
+	}
+}
+
+// openCorruptSheet opens the xlsx file with the checksum of its first sheet broken.
+func openCorruptSheet(t *testing.T, path string) *XlsxParser {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+
+	b := new(bytes.Buffer)
+	zw := zip.NewWriter(b)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		fh := &zip.FileHeader{Name: f.Name, Method: zip.Store, CRC32: crc32.ChecksumIEEE(data)}
+		if f.Name == "xl/worksheets/sheet1.xml" {
+			fh.CRC32++
+		}
+		fh.CompressedSize64 = uint64(len(data))
+		fh.UncompressedSize64 = uint64(len(data))
+		w, err := zw.CreateRaw(fh)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(data)
+	}
+	if err = zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	xp, err := OpenReader(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return xp
+}
+
+func TestExtractRange(t *testing.T) {
+	xp, err := Open(xlsxNamesPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer xp.Close()
+
+	for _, c := range []struct {
+		ref  string
+		want string
+	}{
+		{"B1:C4", "b1\tc1\nb2\tc2\n\nb4\tc4\n"},
+		{"$B$2:$B$2", "b2\n"},
+		{"C4:B2", "b2\tc2\n\nb4\tc4\n"},
+		{"A:A", "a1\na2\n\na4\n"},
+		{"2:2", "a2\tb2\tc2\n"},
+	} {
+		texts, err := xp.ExtractRange(1, c.ref)
+		if err != nil {
+			t.Error(err)
+		}
+		t.Logf("%s => %q", c.ref, texts)
+		if texts != c.want {
+			t.Errorf("want: %q", c.want)
+		}
+	}
+	if _, err = xp.ExtractRange(1, "A1:B"); err != types.ErrBadRange {
+		t.Error("want types.ErrBadRange")
+	}
+	if _, err = xp.ExtractRange(3, "A1"); err != types.ErrNoSheet {
+		t.Error("want types.ErrNoSheet")
+	}
+
+	xp = openCorruptSheet(t, xlsxNamesPath)
+	defer xp.Close()
+	if _, err = xp.ExtractRange(1, "A:A"); err != zip.ErrChecksum {
+		t.Error("want zip.ErrChecksum")
+	}
+}
+
+func TestExtractDefinedName(t *testing.T) {
+	xp, err := Open(xlsxNamesPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer xp.Close()
+
+	names := xp.DefinedNames()
+	t.Log(names)
+	if strings.Join(names, ",") != "SalesData,Data!Region,Q1 Sales!Region,Multi,Rate" {
+		t.Error("unexpected defined names")
+	}
+
+	for _, c := range []struct {
+		name string
+		want string
+	}{
+		{"salesdata", "Apple\t10\nPear\t20\n"},
+		{"Region", "a1\na2\n"},
+		{"'Q1 Sales'!Region", "Amount\n10\n20\n"},
+		{"Multi", "a1\nc2\n"},
+	} {
+		texts, err := xp.ExtractDefinedName(c.name)
+		if err != nil {
+			t.Error(err)
+		}
+		t.Logf("%s => %q", c.name, texts)
+		if texts != c.want {
+			t.Errorf("want: %q", c.want)
+		}
+	}
+	if _, err = xp.ExtractDefinedName("Rate"); err != types.ErrBadRange {
+		t.Error("want types.ErrBadRange")
+	}
+	if _, err = xp.ExtractDefinedName("Missing"); err != types.ErrNoDefinedName {
+		t.Error("want types.ErrNoDefinedName")
+	}
+}
+
+func TestRows(t *testing.T) {
+	xp, err := Open(xlsxTypesPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer xp.Close()
+
+	rows, err := xp.Rows(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var got [][]types.Cell
+	for rows.Next() {
+		cells := rows.Columns()
+		t.Logf("%d: %+v", rows.Row(), cells)
+		got = append(got, append([]types.Cell(nil), cells...))
+	}
+	if err = rows.Err(); err != nil {
+		t.Error(err)
+	}
+	if len(got) != 4 || len(got[0]) != 4 || len(got[3]) != 2 {
+		t.Fatal("unexpected rows")
+	}
+	for _, c := range []struct {
+		cell types.Cell
+		typ  types.CellType
+	}{
+		{got[0][1], types.CellString},
+		{got[1][0], types.CellNumber},
+		{got[1][2], types.CellBool},
+		{got[2][0], types.CellError},
+		{got[2][1], types.CellString},
+		{got[2][3], types.CellString},
+		{got[3][0], types.CellDate},
+	} {
+		if c.cell.Type != c.typ {
+			t.Errorf("unexpected type of %s: %v", c.cell.Ref, c.cell.Type)
+		}
+	}
+	if got[1][0].Number != 3 || !got[1][2].Bool || got[1][3].Bool {
+		t.Error("unexpected numbers and booleans")
+	}
+	if got[2][1].Formula != `=A1&"<"` || got[2][1].Value != "Zero&<" {
+		t.Errorf("unexpected formula string: %+v", got[2][1])
+	}
+	if got[3][0].Time.Format("2006-01-02") != "2023-10-05" || got[3][0].Ref != "A4" {
+		t.Errorf("unexpected date: %+v", got[3][0])
+	}
+
+	// the numbers with date formats are dates
+	xpNumFmt, err := Open(xlsxNumFmtPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer xpNumFmt.Close()
+	rows, err = xpNumFmt.Rows(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		t.Fatal("want a row")
+	}
+	cells := rows.Columns()
+	t.Logf("%+v", cells)
+	if cells[0].Type != types.CellDate || cells[0].Time.Format("2006-01-02") != "2023-07-16" || cells[0].Number != 45123 {
+		t.Errorf("unexpected date: %+v", cells[0])
+	}
+	if cells[1].Type != types.CellNumber || cells[1].Number != 0.256 || cells[1].Value != "25.60%" {
+		t.Errorf("unexpected number: %+v", cells[1])
+	}
+
+	if _, err = xp.Rows(2); err != types.ErrNoSheet {
+		t.Error("want types.ErrNoSheet")
+	}
+}
+
+func TestRecords(t *testing.T) {
+	xp, err := Open(xlsxRecordsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer xp.Close()
+
+	records, err := xp.Records(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer records.Close()
+
+	header := records.Header()
+	t.Log(records.HeaderRow(), header)
+	if records.HeaderRow() != 2 || strings.Join(header, ",") != "Product,Amount,Order Date,Paid,Qty,Note,Note_2" {
+		t.Fatal("unexpected header")
+	}
+
+	type sale struct {
+		Product string    `xlsx:"product"`
+		Amount  float64   `xlsx:"Amount"`
+		Date    time.Time `xlsx:"Order Date"`
+		Paid    bool
+		Qty     *int
+		Note    string `xlsx:"Note_2"`
+		Skipped string `xlsx:"-"`
+	}
+
+	var sales []sale
+	var rowErrs []*types.RowError
+	for records.Next() {
+		t.Log(records.Row(), records.Record())
+		var s sale
+		if err := records.Decode(&s); err != nil {
+			t.Log(err)
+			for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+				var rowErr *types.RowError
+				if errors.As(err, &rowErr) {
+					rowErrs = append(rowErrs, rowErr)
+				}
+			}
+		}
+		sales = append(sales, s)
+	}
+	if err = records.Err(); err != nil {
+		t.Error(err)
+	}
+	t.Logf("%+v", sales)
+	if len(sales) != 2 {
+		t.Fatal("unexpected records")
+	}
+	if s := sales[0]; s.Product != "Apple" || s.Amount != 1.5 || s.Date.Format("2006-01-02") != "2023-07-16" ||
+		!s.Paid || s.Qty == nil || *s.Qty != 3 || s.Note != "b" {
+		t.Errorf("unexpected record: %+v", s)
+	}
+	if s := sales[1]; s.Product != "Pear" || s.Date.Format("2006-01-02") != "2023-07-17" || s.Paid || s.Note != "" {
+		t.Errorf("unexpected record: %+v", s)
+	}
+	// the errors of the cells can not be converted
+	if len(rowErrs) != 2 || rowErrs[0].Row != 5 || rowErrs[0].Column != "Amount" || rowErrs[1].Field != "Qty" {
+		t.Error("unexpected row errors")
+	}
+
+	var s sale
+	if err = records.Decode(s); err != types.ErrBadDecodeTarget {
+		t.Error("want types.ErrBadDecodeTarget")
+	}
+
+	xp.SetHeaderRow(1)
+	if _, err = xp.Records(1); err != types.ErrNoHeader {
+		t.Error("want types.ErrNoHeader")
+	}
+
+	// the numbers out of the range of the integers are not converted
+	var (
+		i64 int64
+		u64 uint64
+	)
+	for _, c := range []struct {
+		fv reflect.Value
+		v  float64
+	}{
+		{reflect.ValueOf(&i64).Elem(), 1e19},
+		{reflect.ValueOf(&i64).Elem(), -1e19},
+		{reflect.ValueOf(&i64).Elem(), math.Inf(1)},
+		{reflect.ValueOf(&u64).Elem(), 2e19},
+		{reflect.ValueOf(&u64).Elem(), -1},
+	} {
+		err = decodeCell(c.fv, types.Cell{Value: "x", Type: types.CellNumber, Number: c.v}, false)
+		t.Log(err)
+		if err == nil {
+			t.Errorf("want out of range error of %v", c.v)
+		}
+	}
+	if err = decodeCell(reflect.ValueOf(&i64).Elem(), types.Cell{Value: "x", Type: types.CellNumber, Number: -1 << 63}, false); err != nil || i64 != math.MinInt64 {
+		t.Errorf("unexpected min int64: %v %v", i64, err)
+	}
+}
+
+func TestComments(t *testing.T) {
+	xp, err := Open(xlsxCommentPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer xp.Close()
+
+	comments, err := xp.ExtractComments(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("%+v", comments)
+	// the note written for the threaded comment of B3 is skipped
+	if len(comments) != 3 {
+		t.Fatal("unexpected comments")
+	}
+	if c := comments[0]; c.Ref != "A2" || c.Author != "Jane Doe" || c.Text != "Check the supplier & price" || c.Threaded {
+		t.Errorf("unexpected note: %+v", c)
+	}
+	c := comments[1]
+	if c.Ref != "B3" || c.Author != "Jane Doe" || c.Text != "Is this final?" || !c.Threaded || !c.Done ||
+		!c.Time.Equal(time.Date(2023, 7, 16, 9, 30, 0, 0, time.UTC)) {
+		t.Errorf("unexpected threaded comment: %+v", c)
+	}
+	if len(c.Replies) != 1 || c.Replies[0].Author != "Bob Lee" || c.Replies[0].Text != "Yes, approved." {
+		t.Errorf("unexpected replies: %+v", c.Replies)
+	}
+	if c := comments[2]; c.Ref != "D5" || c.Col != 4 || c.Row != 5 {
+		t.Errorf("unexpected note: %+v", c)
+	}
+
+	// the comments of the cells not written in the sheet are written in their cells
+	xp.SetInlineComments(true)
+	xp.SetSheetSep("")
+	texts, err := xp.ExtractTexts()
+	if err != nil {
+		t.Error(err)
+	}
+	t.Logf("%q", texts)
+	want := "Product\tAmount\n" +
+		"Apple [comment]Jane Doe: Check the supplier & price[/comment]\t12\n" +
+		"Pear\t7 [comment]Jane Doe: Is this final? | Bob Lee: Yes, approved.[/comment]\n" +
+		"\n" +
+		"\t\t\t[comment]Jane Doe: Empty cell note[/comment]\n"
+	if texts != want {
+		t.Error("unexpected inline comments")
+	}
+
+	if _, err = xp.ExtractComments(2); err != types.ErrNoSheet {
+		t.Error("want types.ErrNoSheet")
+	}
+
+	// the commented rows are found in ascending order
+	sc := newSheetComments([]types.Comment{{Col: 1, Row: 5}, {Col: 1, Row: 2}, {Col: 2, Row: 5}})
+	for _, c := range [][3]int{{1, 10, 2}, {3, 4, 0}, {3, 10, 5}, {6, 10, 0}} {
+		if got := sc.commentedRow(c[0], c[1]); got != c[2] {
+			t.Errorf("commented row between %d and %d: %d, want: %d", c[0], c[1], got, c[2])
+		}
+	}
+}
+
+func TestTables(t *testing.T) {
+	xp, err := Open(xlsxTablesPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer xp.Close()
+
+	names, err := xp.TableNames()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(names)
+	if len(names) != 2 || names[0] != "Sales" || names[1] != "Lookup" {
+		t.Error("unexpected table names")
+	}
+
+	tables, err := xp.Tables()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("%q", tables)
+	if len(tables) != 2 {
+		t.Fatal("unexpected tables")
+	}
+	// the header and totals rows are not data rows, and the row not written in the sheet is empty
+	sales := tables[0]
+	if sales.Name != "Sales" || sales.Sheet != "Report" || sales.Ref != "B3:D8" ||
+		strings.Join(sales.Columns, ",") != "Product,Region,Amount" {
+		t.Errorf("unexpected table: %q", sales)
+	}
+	if len(sales.Rows) != 4 || strings.Join(sales.Rows[1], ",") != "Pear,,7.5" || strings.Join(sales.Rows[2], ",") != ",," {
+		t.Errorf("unexpected rows: %q", sales.Rows)
+	}
+	if strings.Join(sales.Totals, ",") != "Total,,22.5" {
+		t.Errorf("unexpected totals: %q", sales.Totals)
+	}
+
+	// the table without header row, the column without name is named by its letter
+	lookup, err := xp.ExtractTable("table2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("%q", lookup)
+	if lookup.Name != "Lookup" || strings.Join(lookup.Columns, ",") != "Key & Code,B" ||
+		len(lookup.Rows) != 2 || strings.Join(lookup.Rows[0], ",") != "x,1" || lookup.Totals != nil {
+		t.Errorf("unexpected table: %q", lookup)
+	}
+
+	if _, err = xp.ExtractTable("Missing"); err != types.ErrNoTable {
+		t.Error("want types.ErrNoTable")
+	}
+
+	// the rows and columns of a table larger than the sheet data are limited to the used range
+	ti := xp.tables[0]
+	ti.ref, ti.cr.col2, ti.cr.row2 = "B3:XFD1048576", maxSheetColumns, maxSheetRows
+	huge, err := xp.readTable(&ti)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("%q", huge)
+	if len(huge.Columns) != 3 || len(huge.Rows) < 5 || len(huge.Rows) > 10 ||
+		strings.Join(huge.Rows[4], ",") != "Total,,22.5" || strings.Join(huge.Totals, ",") != ",," {
+		t.Errorf("unexpected huge table: %q", huge)
+	}
+}